@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+// lintResult — отчет по одному файлу сценария для вывода -json
+type lintResult struct {
+	File   string           `json:"file"`
+	Issues []director.Issue `json:"issues"`
+}
+
+// runLint проверяет файлы сценариев и возвращает код выхода:
+// 0 — ошибок нет, 1 — найдены ошибки (или предупреждения при -strict), 2 — ошибка запуска.
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("pdf2video lint", flag.ContinueOnError)
+	schemaPtr := fs.Bool("schema", false, "Вывести JSON Schema формата сценария (для валидации в редакторе) и выйти")
+	jsonPtr := fs.Bool("json", false, "Вывести отчет в формате JSON (для CI)")
+	strictPtr := fs.Bool("strict", false, "Считать предупреждения ошибками")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Использование: pdf2video lint [-json] [-strict] scenario.yaml...")
+		fmt.Fprintln(fs.Output(), "               pdf2video lint -schema > scenario.schema.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if *schemaPtr {
+		schema, err := director.ScenarioSchema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[-] Ошибка генерации схемы: %v\n", err)
+			return 2
+		}
+		fmt.Println(string(schema))
+		return 0
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	failed := false
+	var results []lintResult
+	for _, path := range fs.Args() {
+		issues, err := director.LintFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[-] Ошибка чтения сценария %s: %v\n", path, err)
+			return 2
+		}
+		if director.HasErrors(issues) || (*strictPtr && len(issues) > 0) {
+			failed = true
+		}
+
+		if *jsonPtr {
+			if issues == nil {
+				issues = []director.Issue{}
+			}
+			results = append(results, lintResult{File: path, Issues: issues})
+			continue
+		}
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", path, issue)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("%s: OK\n", path)
+		}
+	}
+
+	if *jsonPtr {
+		data, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(data))
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
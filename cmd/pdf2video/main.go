@@ -7,7 +7,6 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 
 	"github.com/ivlev/pdf2video/internal/config"
@@ -19,6 +18,14 @@ import (
 )
 
 func main() {
+	// Подкоманда проверки сценариев: не требует входных файлов и ffmpeg
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "scenario" {
+		os.Exit(runScenario(os.Args[2:]))
+	}
+
 	// Увеличиваем лимиты системы (для macOS/Linux)
 	system.InitResourceLimits()
 
@@ -47,13 +54,11 @@ func main() {
 		fmt.Printf("[*] Обнаружено аппаратное ускорение: %s\n", cfg.VideoEncoder)
 	}
 
-	var src source.Source
-	if strings.HasSuffix(strings.ToLower(cfg.InputPath), ".pdf") {
-		src, err = source.NewFitzPDFSource(cfg.InputPath)
-	} else {
-		src, err = source.NewImageSource(cfg.InputPath)
+	if cfg.ReflowSize != "" && !source.ReflowSupported() {
+		fmt.Println("[!] Предупреждение: сборка без перекомпоновки EPUB, FB2 и MOBI (нужна сборка с системной MuPDF: -tags extlib), -reflow-size игнорируется")
 	}
 
+	src, err := source.OpenInputs(cfg.InputPaths, engine.SourceOptions(cfg))
 	if err != nil {
 		log.Fatalf("[-] Ошибка инициализации источника: %v", err)
 	}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+const scenarioUsage = `Использование: pdf2video scenario <команда> [флаги] файлы...
+
+Команды:
+  shift   -slide N -by SEC [-o out.yaml] scenario.yaml      сдвинуть кадры слайда N на SEC секунд
+  stretch -total SEC [-o out.yaml] scenario.yaml            растянуть длительности слайдов до общей SEC
+  merge   -from other.yaml -pages 3-8 [-input deck.pdf] [-o out.yaml] scenario.yaml
+                                                           перенести работу камеры страниц 3-8 из other.yaml
+  split   -at N scenario.yaml first.yaml second.yaml        разделить сценарий перед слайдом N
+  diff    old.yaml new.yaml                                 показать различия (код выхода 1, если есть)
+
+Без -o файл сценария перезаписывается. Результат проверяется как в pdf2video lint
+и не сохраняется, если содержит ошибки.`
+
+// runScenario выполняет подкоманды редактирования сценариев и возвращает код выхода
+func runScenario(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, scenarioUsage)
+		return 2
+	}
+
+	var err error
+	code := 0
+	switch args[0] {
+	case "shift":
+		err = scenarioShift(args[1:])
+	case "stretch":
+		err = scenarioStretch(args[1:])
+	case "merge":
+		err = scenarioMerge(args[1:])
+	case "split":
+		err = scenarioSplit(args[1:])
+	case "diff":
+		code, err = scenarioDiff(args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Println(scenarioUsage)
+		return 0
+	default:
+		err = fmt.Errorf("неизвестная команда %q", args[0])
+	}
+
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(os.Stderr, "[-] %v\n", err)
+		}
+		return 2
+	}
+	return code
+}
+
+// newScenarioFlags создает набор флагов подкоманды с общим флагом -o
+func newScenarioFlags(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet("pdf2video scenario "+name, flag.ContinueOnError)
+	out := fs.String("o", "", "Куда сохранить результат (по умолчанию перезаписать исходный сценарий)")
+	return fs, out
+}
+
+// readSingleScenario читает единственный позиционный аргумент подкоманды
+func readSingleScenario(fs *flag.FlagSet) (*director.Scenario, string, error) {
+	if fs.NArg() != 1 {
+		return nil, "", fmt.Errorf("ожидается один файл сценария, получено %d", fs.NArg())
+	}
+	path := fs.Arg(0)
+	s, err := director.ReadScenario(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка чтения сценария %s: %v", path, err)
+	}
+	return s, path, nil
+}
+
+// saveScenario проверяет сценарий и сохраняет его, только если в нем нет ошибок
+func saveScenario(s *director.Scenario, path string) error {
+	issues := s.Lint()
+	for _, issue := range issues {
+		fmt.Printf("%s: %s\n", path, issue)
+	}
+	if director.HasErrors(issues) {
+		return fmt.Errorf("сценарий %s не сохранен: результат содержит ошибки", path)
+	}
+	if err := director.WriteScenario(s, path); err != nil {
+		return err
+	}
+	fmt.Printf("[+] Сценарий сохранен: %s\n", path)
+	return nil
+}
+
+func outputPath(out, in string) string {
+	if out != "" {
+		return out
+	}
+	return in
+}
+
+func scenarioShift(args []string) error {
+	fs, out := newScenarioFlags("shift")
+	slide := fs.Int("slide", 0, "Номер слайда (с 1)")
+	by := fs.Float64("by", 0, "Сдвиг кадров в секундах (может быть отрицательным)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, path, err := readSingleScenario(fs)
+	if err != nil {
+		return err
+	}
+	if err := s.Shift(*slide, *by); err != nil {
+		return err
+	}
+	return saveScenario(s, outputPath(*out, path))
+}
+
+func scenarioStretch(args []string) error {
+	fs, out := newScenarioFlags("stretch")
+	total := fs.Float64("total", 0, "Новая сумма длительностей слайдов в секундах")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, path, err := readSingleScenario(fs)
+	if err != nil {
+		return err
+	}
+	if err := s.Stretch(*total); err != nil {
+		return err
+	}
+	return saveScenario(s, outputPath(*out, path))
+}
+
+func scenarioMerge(args []string) error {
+	fs, out := newScenarioFlags("merge")
+	from := fs.String("from", "", "Сценарий, из которого берется работа камеры")
+	pages := fs.String("pages", "", "Диапазон страниц: N или N-M")
+	input := fs.String("input", "", "Файл страниц, как в поле input слайдов (по умолчанию основной вход: #N)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, path, err := readSingleScenario(fs)
+	if err != nil {
+		return err
+	}
+	if *from == "" {
+		return fmt.Errorf("укажите сценарий-источник флагом -from")
+	}
+	src, err := director.ReadScenario(*from)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения сценария %s: %v", *from, err)
+	}
+
+	if *pages == "" {
+		return fmt.Errorf("укажите диапазон страниц флагом -pages")
+	}
+	first, last, err := parsePageRange(*pages)
+	if err != nil {
+		return err
+	}
+	// Слайды сопоставляются по страницам, на которые ссылаются, а не по позиции в сценарии
+	if err := s.Merge(src, *input, first, last); err != nil {
+		return err
+	}
+	return saveScenario(s, outputPath(*out, path))
+}
+
+func scenarioSplit(args []string) error {
+	fs := flag.NewFlagSet("pdf2video scenario split", flag.ContinueOnError)
+	at := fs.Int("at", 0, "Номер слайда, с которого начинается вторая часть")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 3 {
+		return fmt.Errorf("ожидается: split -at N scenario.yaml first.yaml second.yaml")
+	}
+	s, err := director.ReadScenario(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("ошибка чтения сценария %s: %v", fs.Arg(0), err)
+	}
+	first, second, err := s.Split(*at)
+	if err != nil {
+		return err
+	}
+	if err := saveScenario(first, fs.Arg(1)); err != nil {
+		return err
+	}
+	return saveScenario(second, fs.Arg(2))
+}
+
+func scenarioDiff(args []string) (int, error) {
+	fs := flag.NewFlagSet("pdf2video scenario diff", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 0, err
+	}
+	if fs.NArg() != 2 {
+		return 0, fmt.Errorf("ожидается: diff old.yaml new.yaml")
+	}
+	a, err := director.ReadScenario(fs.Arg(0))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка чтения сценария %s: %v", fs.Arg(0), err)
+	}
+	b, err := director.ReadScenario(fs.Arg(1))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка чтения сценария %s: %v", fs.Arg(1), err)
+	}
+
+	changes := director.Diff(a, b)
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	if len(changes) > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// parsePageRange разбирает "5" или "3-8"
+func parsePageRange(s string) (int, int, error) {
+	lo, hi, found := strings.Cut(s, "-")
+	first, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return 0, 0, fmt.Errorf("некорректный диапазон страниц %q", s)
+	}
+	if !found {
+		return first, first, nil
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(hi))
+	if err != nil {
+		return 0, 0, fmt.Errorf("некорректный диапазон страниц %q", s)
+	}
+	return first, last, nil
+}
@@ -21,4 +21,5 @@ require (
 	github.com/tklauser/numcpus v0.6.1 // indirect
 	github.com/yusufpapurcu/wmi v1.2.4 // indirect
 	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.34.0 // indirect
 )
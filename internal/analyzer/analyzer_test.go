@@ -55,7 +55,9 @@ func TestDetectorRegistry(t *testing.T) {
 	}{
 		{"contrast", false},
 		{"", false}, // default
-		{"ocr", true},
+		{"ocr", false},
+		{"panel", false},
+		{"manga", false},
 		{"ai", true},
 		{"invalid", true},
 	}
@@ -79,3 +81,25 @@ func TestDetectorRegistry(t *testing.T) {
 		})
 	}
 }
+
+type textLayerStub struct{ dpi int }
+
+func (s *textLayerStub) GetTextBlocks(index int, dpi int) ([]Block, error) {
+	s.dpi = dpi
+	return []Block{{Rect: image.Rect(0, 0, dpi, dpi)}}, nil
+}
+
+func TestOCRDetectorRenderDPI(t *testing.T) {
+	stub := &textLayerStub{}
+	detector := NewOCRDetector(stub, 0)
+	detector.DPI = 450
+
+	// The text layer is requested at the resolution of the analyzed render
+	blocks, err := detector.Detect(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stub.dpi != 450 || blocks[0].Rect.Dx() != 450 {
+		t.Errorf("expected blocks at 450 DPI, got %d (%v)", stub.dpi, blocks)
+	}
+}
@@ -32,6 +32,11 @@ type Block struct {
 	Score      float64 // Informational importance score (0.0-1.0)
 	Density    float64 // Content density
 	Priority   float64 // Execution priority
+	WordCount  int     // Words of the text layer inside the block (0 if unknown)
+
+	// Text layer of the block, set by detectors that read it (empty otherwise)
+	Lines []TextLine
+	Style TextStyle // Style of most of the block characters
 
 	Metrics BlockMetrics
 }
@@ -39,6 +39,11 @@ func NewEnhancedDetector() *EnhancedDetector {
 	}
 }
 
+// SetPrioritizer replaces the prioritizer used for semantic ranking of blocks
+func (d *EnhancedDetector) SetPrioritizer(p *BlockPrioritizer) {
+	d.prioritizer = p
+}
+
 func (d *EnhancedDetector) Detect(img image.Image) ([]Block, error) {
 	bounds := img.Bounds()
 	gray := toGrayscale(img)
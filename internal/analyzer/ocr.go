@@ -6,15 +6,17 @@ import (
 
 // OCRDetector is a specialized detector that uses the Source's structured text extraction
 // capabilities (like MuPDF/fitz) instead of pure computer vision.
+// DPI is the resolution the analyzed image was rendered at, blocks come in its pixels.
 type OCRDetector struct {
 	Source interface {
-		GetTextBlocks(index int) ([]Block, error)
+		GetTextBlocks(index int, dpi int) ([]Block, error)
 	}
 	PageIndex int
+	DPI       int
 }
 
 func NewOCRDetector(source interface {
-	GetTextBlocks(index int) ([]Block, error)
+	GetTextBlocks(index int, dpi int) ([]Block, error)
 }, pageIndex int) *OCRDetector {
 	return &OCRDetector{
 		Source:    source,
@@ -26,5 +28,5 @@ func (d *OCRDetector) Detect(img image.Image) ([]Block, error) {
 	if d.Source == nil {
 		return []Block{}, nil
 	}
-	return d.Source.GetTextBlocks(d.PageIndex)
+	return d.Source.GetTextBlocks(d.PageIndex, d.DPI)
 }
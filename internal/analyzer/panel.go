@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"image"
+	"sort"
+)
+
+// PanelDetector finds the panels of a comic page: regions separated by gutters, the
+// page-coloured bands between them. The page is cut recursively along gutters that
+// cross the whole region (tiers first, then panels inside a tier), which also gives
+// the reading order: tiers top to bottom, panels left to right, or right to left for manga.
+type PanelDetector struct {
+	RightToLeft     bool    // Manga reading order
+	GutterTolerance int     // Max brightness difference from the page colour for gutter pixels
+	GutterCoverage  float64 // Share of gutter pixels a row or column needs to count as gutter
+	MinGutterShare  float64 // Narrowest gutter as a share of the page's shorter side
+	MinPanelShare   float64 // Smaller regions (page numbers, stray marks) are not panels
+	MaxDepth        int     // Limit of nested cuts
+}
+
+// NewPanelDetector creates a panel detector for Western or manga (right-to-left) pages
+func NewPanelDetector(rightToLeft bool) *PanelDetector {
+	return &PanelDetector{
+		RightToLeft:     rightToLeft,
+		GutterTolerance: 40,
+		GutterCoverage:  0.97, // Scans have specks and JPEG noise in the gutters
+		MinGutterShare:  0.005,
+		MinPanelShare:   0.01,
+		MaxDepth:        8,
+	}
+}
+
+// panelPage is the gutter mask of a page
+type panelPage struct {
+	bounds image.Rectangle
+	gutter []bool
+	stride int
+}
+
+func (p *panelPage) at(x, y int) bool {
+	return p.gutter[(y-p.bounds.Min.Y)*p.stride+(x-p.bounds.Min.X)]
+}
+
+// Detect returns the panels in reading order
+func (d *PanelDetector) Detect(img image.Image) ([]Block, error) {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return []Block{}, nil
+	}
+	gray := toGrayscale(img)
+	page := d.gutterMask(gray)
+
+	minGutter := max(2, int(d.MinGutterShare*float64(min(bounds.Dx(), bounds.Dy()))))
+	rects := d.cut(page, bounds, minGutter, 0)
+
+	pageArea := float64(bounds.Dx() * bounds.Dy())
+	blocks := []Block{}
+	for _, r := range rects {
+		share := float64(r.Dx()*r.Dy()) / pageArea
+		if share < d.MinPanelShare {
+			continue
+		}
+		blocks = append(blocks, Block{
+			Rect:       r,
+			Type:       BlockTypeImage,
+			Confidence: 0.8,
+			Score:      1.0,
+			Metrics: BlockMetrics{
+				AspectRatio:  float64(r.Dx()) / float64(r.Dy()),
+				RelativeSize: share,
+			},
+		})
+	}
+	return blocks, nil
+}
+
+// gutterMask marks the pixels of the page colour, taken as the median brightness of
+// the page border (white paper, or black on dark pages)
+func (d *PanelDetector) gutterMask(gray *image.Gray) *panelPage {
+	b := gray.Bounds()
+	var border []int
+	for x := b.Min.X; x < b.Max.X; x++ {
+		border = append(border, int(gray.GrayAt(x, b.Min.Y).Y), int(gray.GrayAt(x, b.Max.Y-1).Y))
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		border = append(border, int(gray.GrayAt(b.Min.X, y).Y), int(gray.GrayAt(b.Max.X-1, y).Y))
+	}
+	sort.Ints(border)
+	paper := border[len(border)/2]
+
+	page := &panelPage{bounds: b, stride: b.Dx(), gutter: make([]bool, b.Dx()*b.Dy())}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			diff := int(gray.GrayAt(x, y).Y) - paper
+			page.gutter[(y-b.Min.Y)*page.stride+(x-b.Min.X)] = diff <= d.GutterTolerance && diff >= -d.GutterTolerance
+		}
+	}
+	return page
+}
+
+// cut trims the region to its content and splits it along full-width gutters into
+// tiers, or along full-height gutters into panels; a region without gutters is a panel
+func (d *PanelDetector) cut(page *panelPage, r image.Rectangle, minGutter, depth int) []image.Rectangle {
+	r = d.trim(page, r)
+	if r.Empty() {
+		return nil
+	}
+	if depth >= d.MaxDepth {
+		return []image.Rectangle{r}
+	}
+
+	for _, horizontal := range []bool{true, false} {
+		parts := d.split(page, r, horizontal, minGutter)
+		if len(parts) < 2 {
+			continue
+		}
+		if !horizontal && d.RightToLeft {
+			for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+				parts[i], parts[j] = parts[j], parts[i]
+			}
+		}
+		var panels []image.Rectangle
+		for _, part := range parts {
+			panels = append(panels, d.cut(page, part, minGutter, depth+1)...)
+		}
+		return panels
+	}
+	return []image.Rectangle{r}
+}
+
+// split cuts the region at gutter runs of at least minGutter rows (horizontal) or columns
+func (d *PanelDetector) split(page *panelPage, r image.Rectangle, horizontal bool, minGutter int) []image.Rectangle {
+	lo, hi := r.Min.X, r.Max.X
+	if horizontal {
+		lo, hi = r.Min.Y, r.Max.Y
+	}
+
+	var parts []image.Rectangle
+	start, run := lo, 0
+	for pos := lo; pos <= hi; pos++ {
+		if pos < hi && d.isGutter(page, r, pos, horizontal) {
+			run++
+			continue
+		}
+		// A content line (or the end) after a wide enough gutter closes the part before it
+		if pos == hi || run >= minGutter {
+			if end := pos - run; end > start {
+				if horizontal {
+					parts = append(parts, image.Rect(r.Min.X, start, r.Max.X, end))
+				} else {
+					parts = append(parts, image.Rect(start, r.Min.Y, end, r.Max.Y))
+				}
+			}
+			start = pos
+		}
+		run = 0
+	}
+	return parts
+}
+
+// isGutter reports whether the row (horizontal) or column at pos is gutter across the region
+func (d *PanelDetector) isGutter(page *panelPage, r image.Rectangle, pos int, horizontal bool) bool {
+	count, total := 0, 0
+	if horizontal {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if page.at(x, pos) {
+				count++
+			}
+		}
+		total = r.Dx()
+	} else {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			if page.at(pos, y) {
+				count++
+			}
+		}
+		total = r.Dy()
+	}
+	return total > 0 && float64(count) >= d.GutterCoverage*float64(total)
+}
+
+// trim removes the gutter rows and columns around the region content
+func (d *PanelDetector) trim(page *panelPage, r image.Rectangle) image.Rectangle {
+	for r.Min.Y < r.Max.Y && d.isGutter(page, r, r.Min.Y, true) {
+		r.Min.Y++
+	}
+	for r.Max.Y > r.Min.Y && d.isGutter(page, r, r.Max.Y-1, true) {
+		r.Max.Y--
+	}
+	for r.Min.X < r.Max.X && d.isGutter(page, r, r.Min.X, false) {
+		r.Min.X++
+	}
+	for r.Max.X > r.Min.X && d.isGutter(page, r, r.Max.X-1, false) {
+		r.Max.X--
+	}
+	return r
+}
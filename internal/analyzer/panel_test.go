@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// comicPage draws a 600x900 page: one wide panel, a tier of two and a tier of three,
+// plus a page number in the bottom margin
+func comicPage(paper, ink color.Gray) (*image.Gray, []image.Rectangle) {
+	img := image.NewGray(image.Rect(0, 0, 600, 900))
+	fill := func(r image.Rectangle, c color.Gray) {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				img.SetGray(x, y, c)
+			}
+		}
+	}
+	fill(img.Bounds(), paper)
+
+	panels := []image.Rectangle{
+		image.Rect(20, 20, 580, 300),
+		image.Rect(20, 320, 290, 580), image.Rect(310, 320, 580, 580),
+		image.Rect(20, 600, 200, 860), image.Rect(210, 600, 390, 860), image.Rect(400, 600, 580, 860),
+	}
+	for _, p := range panels {
+		fill(p, ink)
+		// Art inside the panel: a light area that must not be taken for a gutter
+		fill(image.Rect(p.Min.X+10, p.Min.Y+10, p.Max.X-10, p.Min.Y+40), paper)
+	}
+	fill(image.Rect(295, 875, 305, 885), ink) // Page number
+	return img, panels
+}
+
+func TestPanelDetector_ReadingOrder(t *testing.T) {
+	img, panels := comicPage(color.Gray{Y: 255}, color.Gray{Y: 60})
+
+	tests := []struct {
+		name  string
+		det   *PanelDetector
+		order []int
+	}{
+		{"western", NewPanelDetector(false), []int{0, 1, 2, 3, 4, 5}},
+		{"manga", NewPanelDetector(true), []int{0, 2, 1, 5, 4, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := tt.det.Detect(img)
+			if err != nil {
+				t.Fatalf("Detect failed: %v", err)
+			}
+			if len(blocks) != len(panels) {
+				t.Fatalf("expected %d panels, got %d: %v", len(panels), len(blocks), blocks)
+			}
+			for i, b := range blocks {
+				if want := panels[tt.order[i]]; b.Rect != want {
+					t.Errorf("panel %d: expected %v, got %v", i, want, b.Rect)
+				}
+				if b.Type != BlockTypeImage {
+					t.Errorf("panel %d: expected image block, got %s", i, b.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestPanelDetector_DarkGutters(t *testing.T) {
+	img, panels := comicPage(color.Gray{Y: 0}, color.Gray{Y: 200})
+
+	blocks, err := NewPanelDetector(false).Detect(img)
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+	if len(blocks) != len(panels) {
+		t.Fatalf("expected %d panels on a black page, got %d", len(panels), len(blocks))
+	}
+}
+
+func TestPanelDetector_BlankPage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 100, 100))
+	blocks, err := NewPanelDetector(false).Detect(img)
+	if err != nil || len(blocks) != 0 {
+		t.Errorf("expected no panels on a blank page, got %v (%v)", blocks, err)
+	}
+}
@@ -13,6 +13,10 @@ func NewDetector(variant string) (Detector, error) {
 		return NewContrastDetector(), nil
 	case "ocr":
 		return NewOCRDetector(nil, 0), nil
+	case "panel":
+		return NewPanelDetector(false), nil
+	case "manga":
+		return NewPanelDetector(true), nil
 	case "ai":
 		return nil, fmt.Errorf("ai detector not yet implemented")
 	default:
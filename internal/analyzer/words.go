@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"image"
+	"strings"
+	"unicode"
+)
+
+// TextStyle is the font of a run of text
+type TextStyle struct {
+	Font   string  // Font name without the subset prefix
+	Size   float64 // Font size in points
+	Bold   bool
+	Italic bool
+}
+
+// TextWord is a word of a text line, Rect bounds its characters (render-DPI pixels)
+type TextWord struct {
+	Rect  image.Rectangle
+	Text  string
+	Style TextStyle
+}
+
+// TextLine is a single line of the page text layer (render-DPI pixels)
+type TextLine struct {
+	Rect  image.Rectangle
+	Text  string
+	Style TextStyle  // Style of most of the line characters
+	Words []TextWord // Empty if the source has no word geometry
+}
+
+// CountWords counts the words a reader has to read: tokens with at least one letter or digit
+func CountWords(text string) int {
+	count := 0
+	for _, token := range strings.Fields(text) {
+		for _, r := range token {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// AssignWordCounts sets Block.WordCount from the text lines. Each line is counted
+// once, for the block that covers the largest part of it.
+func AssignWordCounts(blocks []Block, lines []TextLine) []Block {
+	result := make([]Block, len(blocks))
+	copy(result, blocks)
+	for i := range result {
+		result[i].WordCount = 0
+	}
+
+	for _, line := range lines {
+		best, bestArea := -1, 0
+		for i, b := range result {
+			overlap := b.Rect.Intersect(line.Rect)
+			if area := overlap.Dx() * overlap.Dy(); area > bestArea {
+				best, bestArea = i, area
+			}
+		}
+		if best >= 0 {
+			result[best].WordCount += CountWords(line.Text)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"Hello, world!", 2},
+		{"  • A standard way — 2 items  ", 5},
+		{"Привет, мир", 2},
+		{"- -- —", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CountWords(tt.text); got != tt.want {
+			t.Errorf("CountWords(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAssignWordCounts(t *testing.T) {
+	blocks := []Block{
+		{Rect: image.Rect(0, 0, 500, 100)},   // Caption
+		{Rect: image.Rect(0, 120, 500, 400)}, // Paragraph
+		{Rect: image.Rect(600, 0, 900, 400)}, // Image without text
+	}
+	lines := []TextLine{
+		{Rect: image.Rect(10, 10, 200, 30), Text: "Short caption"},
+		{Rect: image.Rect(10, 130, 480, 150), Text: "The first line of a long paragraph"},
+		{Rect: image.Rect(10, 160, 480, 180), Text: "and the second line of it"},
+		{Rect: image.Rect(10, 95, 480, 135), Text: "mostly paragraph"}, // Straddles both, more inside the paragraph
+		{Rect: image.Rect(1000, 0, 1100, 20), Text: "page number"},     // Outside of all blocks
+	}
+
+	result := AssignWordCounts(blocks, lines)
+
+	want := []int{2, 15, 0}
+	for i, w := range want {
+		if result[i].WordCount != w {
+			t.Errorf("block %d: expected %d words, got %d", i, w, result[i].WordCount)
+		}
+	}
+	if blocks[1].WordCount != 0 {
+		t.Error("AssignWordCounts should not modify the input blocks")
+	}
+}
@@ -17,7 +17,7 @@ type Builder struct {
 	cfg   *Config
 
 	// Временные переменные для флагов
-	inputPtr            *string
+	inputs              pathList
 	outputPtr           *string
 	durationPtr         *float64
 	pageDurationPtr     *float64
@@ -55,6 +55,19 @@ type Builder struct {
 	qrSizePtr           *int
 	qrMarginRightPtr    *int
 	qrMarginBottomPtr   *int
+	renderModePtr       *string
+	stylePtr            *string
+	compositionPtr      *string
+	adaptivePaddingPtr  *bool
+	readingWPMPtr       *float64
+	orderingPtr         *string
+	reframePtr          *bool
+	scrollStopsPtr      *bool
+	emphasisPtr         *string
+	safeAreaPtr         *string
+	exclusionsPtr       *string
+	reflowSizePtr       *string
+	pagesPtr            *string
 	version             string
 }
 
@@ -70,7 +83,7 @@ func NewBuilder(version string) *Builder {
 }
 
 func (b *Builder) defineFlags() {
-	b.inputPtr = b.flags.String("input", "", "Путь к PDF или папке с изображениями (по умолчанию: самый свежий файл в input/pdf/)")
+	b.flags.Var(&b.inputs, "input", "Путь к PDF или папке с изображениями (по умолчанию: самый свежий файл в input/pdf/). Флаг можно повторить: страницы источников идут подряд в одном видео")
 	b.outputPtr = b.flags.String("output", "", "Путь к видео (если пусто, генерируется автоматически в output/)")
 	b.durationPtr = b.flags.Float64("duration", 0, "Общая длительность видео (если 0, рассчитывается из -page-duration)")
 	b.pageDurationPtr = b.flags.Float64("page-duration", 0.3, "Длительность показа одной страницы/изображения в секундах")
@@ -80,7 +93,8 @@ func (b *Builder) defineFlags() {
 	b.workersPtr = b.flags.Int("workers", runtime.NumCPU(), "Потоки")
 	b.fadePtr = b.flags.Float64("fade", 0.5, "Длительность перехода (сек)")
 	b.transitionPtr = b.flags.String("transition", "fade", "Тип перехода xfade: fade, wipeleft, slideup, pixelize, circlecrop, dissolve, none")
-	b.zoomPtr = b.flags.String("zoom-mode", "center", "Зум: center, top-left, top-right, bottom-left, bottom-right, random, out-center, out-random")
+	b.zoomPtr = b.flags.String("zoom-mode", "center", "Зум: center, top-left, top-right, bottom-left, bottom-right, random, out-center, out-random, scroll (прокрутка высоких страниц)")
+	b.scrollStopsPtr = b.flags.Bool("scroll-stops", false, "Останавливать прокрутку (-zoom-mode scroll) на блоках, найденных детектором -analyze-mode")
 	b.zoomSpeedPtr = b.flags.Float64("zoom-speed", 0.001, "Скорость зума (например, 0.001)")
 	b.dpiPtr = b.flags.Int("dpi", 300, "DPI рендеринга PDF (300 по умолчанию, 0 для автоподбора)")
 	b.audioPtr = b.flags.String("audio", "", "Путь к аудио (по умолчанию: самый свежий файл в input/audio/)")
@@ -88,7 +102,7 @@ func (b *Builder) defineFlags() {
 	b.presetPtr = b.flags.String("preset", "", "Пресет формата: 16:9, 9:16 (Shorts/TikTok), 4:5 (Instagram)")
 	b.qualityPtr = b.flags.Int("quality", 0, "Качество видео (0 - авто, x264: CRF 1-51, VideoToolbox: битрейт = Q*100кбит/с)")
 	b.statsPtr = b.flags.Bool("stats", false, "Вывести статистику производительности и записать в benchmark.log")
-	b.analyzeModePtr = b.flags.String("analyze-mode", "auto", "Режим анализа: auto (умный выбор), contrast (границы), ocr (текст)")
+	b.analyzeModePtr = b.flags.String("analyze-mode", "auto", "Режим анализа: auto (умный выбор), contrast (границы), ocr (текст), panel (кадры комикса слева направо), manga (кадры справа налево)")
 	b.minBlockAreaPtr = b.flags.Int("min-block-area", 500, "Минимальная площадь блока для детекции (в пикселях²)")
 	b.edgeThresholdPtr = b.flags.Float64("edge-threshold", 30.0, "Порог чувствительности детектора границ (Sobel)")
 	b.generateScenarioPtr = b.flags.Bool("generate-scenario", false, "Анализировать PDF и сгенерировать YAML-сценарий вместо видео")
@@ -108,6 +122,18 @@ func (b *Builder) defineFlags() {
 	b.qrSizePtr = b.flags.Int("qr-size", 300, "Размер сквозного QR-кода (px)")
 	b.qrMarginRightPtr = b.flags.Int("qr-margin-right", 20, "Отступ QR-кода от правого края (px)")
 	b.qrMarginBottomPtr = b.flags.Int("qr-margin-bottom", 20, "Отступ QR-кода от нижнего края (px)")
+	b.stylePtr = b.flags.String("style", "", "Стиль режиссуры для -generate-scenario: lecture, promo, gallery или путь к YAML-файлу стиля")
+	b.compositionPtr = b.flags.String("composition", "", "Композиция кадра при генерации сценария: center, thirds (правило третей), leading (запас в направлении движения). Пусто — из стиля")
+	b.adaptivePaddingPtr = b.flags.Bool("adaptive-padding", false, "Подбирать отступы вокруг блока по свободному месту рядом с ним. Без флага — из стиля, -adaptive-padding=false выключает подбор стиля")
+	b.reframePtr = b.flags.Bool("reframe", false, "Кадрирование без полей: камера показывает страницу на всю высоту кадра и панорамирует по блокам (альбомные слайды в 9:16)")
+	b.orderingPtr = b.flags.String("ordering", "", "Порядок обхода блоков: reading (сверху вниз), greedy (приоритет и близость), tsp (глобальный маршрут 2-opt), detector (как нашел детектор). Пусто — из стиля")
+	b.readingWPMPtr = b.flags.Float64("reading-wpm", 0, "Скорость чтения (слов в минуту) для расчета времени на текстовых блоках по слою текста PDF. 0 — из стиля")
+	b.emphasisPtr = b.flags.String("emphasis", "none", "Выделение фокуса при рендеринге по сценарию: none, dim (затемнение), desaturate (обесцвечивание), blur (размытие), spotlight (прожектор) всего вне области кадра")
+	b.safeAreaPtr = b.flags.String("safe-area", "", "Края кадра под интерфейсом площадки при генерации сценария: верх,право,низ,лево в долях кадра (как в CSS: 1, 2 или 4 значения), например 0.1,0.15,0.25,0.05")
+	b.exclusionsPtr = b.flags.String("exclude", "", "Зоны кадра, куда не должен попадать контент блоков: x,y,w,h в долях кадра через ';'. Зона QR-кода учитывается автоматически")
+	b.reflowSizePtr = b.flags.String("reflow-size", "", "Размер страницы для EPUB, FB2 и MOBI в пунктах (ШxВ), например 480x270 под кадр 16:9. Пусто — 450x600 (по умолчанию MuPDF)")
+	b.pagesPtr = b.flags.String("pages", "", "Страницы для видео: номера и диапазоны через запятую, \"!\" исключает (например, 1-5,8,10- или !3). Пусто — все страницы")
+	b.renderModePtr = b.flags.String("render-mode", "zoompan", "Рендеринг движения камеры: zoompan (фильтр FFmpeg), native (покадровый рендер в Go с субпиксельной точностью)")
 }
 
 // Build парсит флаги и собирает итоговую конфигурацию
@@ -132,14 +158,15 @@ func (b *Builder) Build(args []string) (*Config, error) {
 	c.Preset = *b.presetPtr
 
 	// Input handling
-	c.InputPath = *b.inputPtr
-	if c.InputPath == "" {
+	c.InputPaths = b.inputs
+	if len(c.InputPaths) == 0 {
 		latest, err := system.FindLatestPDF("input/pdf")
 		if err != nil {
 			return nil, fmt.Errorf("ошибка: %v. Положите PDF в input/pdf/", err)
 		}
-		c.InputPath = latest
+		c.InputPaths = []string{latest}
 	}
+	c.InputPath = c.InputPaths[0]
 
 	// Audio handling
 	c.AudioPath = *b.audioPtr
@@ -218,6 +245,22 @@ func (b *Builder) Build(args []string) (*Config, error) {
 	c.QRSize = *b.qrSizePtr
 	c.QRMarginRight = *b.qrMarginRightPtr
 	c.QRMarginBottom = *b.qrMarginBottomPtr
+	c.RenderMode = *b.renderModePtr
+	c.Style = *b.stylePtr
+	c.Composition = *b.compositionPtr
+	if b.isSet("adaptive-padding") {
+		// Флаг переопределяет стиль в обе стороны, поэтому учитываем только явно заданный
+		c.AdaptivePadding = b.adaptivePaddingPtr
+	}
+	c.ReadingWPM = *b.readingWPMPtr
+	c.Ordering = *b.orderingPtr
+	c.Reframe = *b.reframePtr
+	c.ScrollStops = *b.scrollStopsPtr
+	c.Emphasis = *b.emphasisPtr
+	c.SafeArea = *b.safeAreaPtr
+	c.Exclusions = *b.exclusionsPtr
+	c.ReflowSize = *b.reflowSizePtr
+	c.Pages = *b.pagesPtr
 
 	// Handle -auto shortcut
 	if *b.autoPtr {
@@ -236,9 +279,44 @@ func (b *Builder) Build(args []string) (*Config, error) {
 	return c, nil
 }
 
+// isSet сообщает, был ли флаг явно указан в командной строке
+func (b *Builder) isSet(name string) bool {
+	set := false
+	b.flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// pathList — значение повторяемого флага: пути в порядке указания
+type pathList []string
+
+func (l *pathList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *pathList) Set(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("пустой путь")
+	}
+	*l = append(*l, path)
+	return nil
+}
+
+// isDocumentPath отличает документ (PDF, EPUB, XPS и т.д.) от изображения или папки с ними
+func isDocumentPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case "", ".png", ".jpg", ".jpeg":
+		return false
+	}
+	return true
+}
+
 func (b *Builder) generateDefaultOutputPath(c *Config) string {
 	var nameSource string
-	if strings.HasSuffix(strings.ToLower(c.InputPath), ".pdf") {
+	if isDocumentPath(c.InputPath) {
 		nameSource = c.InputPath
 	} else {
 		if c.AudioPath != "" {
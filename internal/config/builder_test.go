@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -85,3 +87,157 @@ func TestConfigBuilder_Stats(t *testing.T) {
 		t.Errorf("Expected ShowStats to be true, got false")
 	}
 }
+
+func TestParseSafeArea(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    [4]float64
+		wantErr bool
+	}{
+		{"", [4]float64{}, false},
+		{"0.1", [4]float64{0.1, 0.1, 0.1, 0.1}, false},
+		{"0.1, 0.05", [4]float64{0.1, 0.05, 0.1, 0.05}, false},
+		{"0.1,0.15,0.25,0", [4]float64{0.1, 0.15, 0.25, 0}, false},
+		{"0.1,0.2,0.3", [4]float64{}, true},
+		{"0.6,0,0.5,0", [4]float64{}, true},
+		{"top", [4]float64{}, true},
+		{"1.5", [4]float64{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSafeArea(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: unexpected error state: %v", tt.in, err)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestParseExclusions(t *testing.T) {
+	zones, err := ParseExclusions("0.8,0.8,0.2,0.2; 0,0,0.3,0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 2 || zones[1] != [4]float64{0, 0, 0.3, 0.1} {
+		t.Errorf("unexpected zones %v", zones)
+	}
+	for _, bad := range []string{"0.8,0.8,0.2", "0.1,0.1,0,0.2", "a,b,c,d"} {
+		if _, err := ParseExclusions(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+
+	b := NewBuilder("test")
+	cfg, err := b.Build([]string{"-input", "test.pdf", "-safe-area", "0.1,0.2", "-exclude", "0,0,0.2,0.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SafeArea != "0.1,0.2" || cfg.Exclusions != "0,0,0.2,0.2" {
+		t.Errorf("flags not applied: %q %q", cfg.SafeArea, cfg.Exclusions)
+	}
+}
+
+func TestParseReflowSize(t *testing.T) {
+	w, h, err := ParseReflowSize("480x270")
+	if err != nil || w != 480 || h != 270 {
+		t.Errorf("ParseReflowSize(480x270) = %v, %v, %v", w, h, err)
+	}
+	if w, h, err := ParseReflowSize(""); err != nil || w != 0 || h != 0 {
+		t.Errorf("empty size must keep the default layout, got %v, %v, %v", w, h, err)
+	}
+	for _, bad := range []string{"480", "480x", "ax270", "10x270", "480x-1"} {
+		if _, _, err := ParseReflowSize(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+
+	if _, err := NewBuilder("test").Build([]string{"-input", "book.epub", "-reflow-size", "wide"}); err == nil {
+		t.Error("invalid -reflow-size must fail validation")
+	}
+}
+
+func TestConfigBuilder_MultipleInputs(t *testing.T) {
+	cfg, err := NewBuilder("test").Build([]string{"-input", "intro.pdf", "-input", "shots", "-input", "outro.pdf"})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(cfg.InputPaths) != 3 || cfg.InputPaths[1] != "shots" || cfg.InputPath != "intro.pdf" {
+		t.Errorf("expected inputs in order with the first as InputPath, got %q (%q)", cfg.InputPaths, cfg.InputPath)
+	}
+}
+
+func TestParsePages(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []int // 0-based pages of a 12-page document
+	}{
+		{"", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+		{"1-5,8,10-", []int{0, 1, 2, 3, 4, 7, 9, 10, 11}},
+		{"!3", []int{0, 1, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+		{"1-5, !3, 11-", []int{0, 1, 3, 4, 10, 11}},
+		{"-2,!10-", []int{0, 1}},
+		{"12-20", []int{11}},
+	}
+	for _, tt := range tests {
+		sel, err := ParsePages(tt.spec)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if got := sel.Indices(12); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%q: pages = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"0", "5-3", "a-b", "!", "1;2"} {
+		if _, err := ParsePages(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+	if _, err := NewBuilder("test").Build([]string{"-input", "deck.pdf", "-pages", "3-1"}); err == nil {
+		t.Error("invalid -pages must fail validation")
+	}
+}
+
+func TestConfigBuilder_AdaptivePadding(t *testing.T) {
+	cases := []struct {
+		args []string
+		want *bool
+	}{
+		{nil, nil}, // From the style
+		{[]string{"-adaptive-padding"}, boolPtr(true)},
+		{[]string{"-adaptive-padding=false"}, boolPtr(false)}, // Overrides a style that enables it
+	}
+	for _, tc := range cases {
+		cfg, err := NewBuilder("test").Build(append([]string{"-input", "test.pdf"}, tc.args...))
+		if err != nil {
+			t.Fatalf("%v: Build failed: %v", tc.args, err)
+		}
+		if (cfg.AdaptivePadding == nil) != (tc.want == nil) || (tc.want != nil && *cfg.AdaptivePadding != *tc.want) {
+			t.Errorf("%v: expected %v, got %v", tc.args, tc.want, cfg.AdaptivePadding)
+		}
+	}
+
+	if _, err := NewBuilder("test").Build([]string{"-input", "test.pdf", "-composition", "golden"}); err == nil {
+		t.Error("unknown -composition must fail validation")
+	}
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func TestBuilder_Ordering(t *testing.T) {
+	for _, ordering := range SupportedOrderings {
+		cfg, err := NewBuilder("test").Build([]string{"-input", "test.pdf", "-ordering", ordering})
+		if err != nil || cfg.Ordering != ordering {
+			t.Errorf("-ordering %s: got %q (%v)", ordering, cfg.Ordering, err)
+		}
+	}
+	_, err := NewBuilder("test").Build([]string{"-input", "test.pdf", "-ordering", "spiral"})
+	if err == nil || !strings.Contains(err.Error(), "Supported: [reading greedy tsp detector]") {
+		t.Errorf("unknown -ordering must fail validation with the supported list, got %v", err)
+	}
+}
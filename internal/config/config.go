@@ -1,9 +1,15 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+)
 
 type Config struct {
-	InputPath             string
+	InputPath             string   // Первый источник: по нему называется видео и ищутся страницы сценария
+	InputPaths            []string // Все источники по порядку, их страницы идут подряд
 	OutputVideo           string
 	TotalDuration         float64
 	Width                 int
@@ -47,6 +53,19 @@ type Config struct {
 	QRSize                int
 	QRMarginRight         int
 	QRMarginBottom        int
+	RenderMode            string
+	Style                 string  // Стиль режиссуры (имя встроенного стиля или путь к YAML)
+	Composition           string  // Композиция кадра (пусто — из стиля)
+	AdaptivePadding       *bool   // Подбор отступов по свободному месту (nil — из стиля)
+	ReadingWPM            float64 // Скорость чтения, слов в минуту (0 — из стиля)
+	Ordering              string  // Порядок обхода блоков (пусто — из стиля)
+	Reframe               bool    // Кадрирование под формат кадра без полей (9:16 из альбомных страниц)
+	ScrollStops           bool    // Остановки на найденных блоках в режиме прокрутки
+	Emphasis              string  // Выделение фокуса по умолчанию для кадров сценария (none — выключено)
+	SafeArea              string  // Края кадра под интерфейсом площадки: "верх,право,низ,лево" в долях кадра
+	Exclusions            string  // Зоны кадра без контента: "x,y,w,h;..." в долях кадра
+	ReflowSize            string  // Размер страницы EPUB/FB2/MOBI в пунктах: "ШxВ" (пусто — по умолчанию MuPDF)
+	Pages                 string  // Выбор страниц: "1-5,8,10-", исключения "!3" (пусто — все)
 }
 
 type VideoSegment struct {
@@ -70,6 +89,11 @@ type SegmentParams struct {
 	Trace         bool
 	TraceColor    string
 	QRCodePath    string
+	SourceWidth   int // Размер отрендеренной страницы (пиксели)
+	SourceHeight  int
+	Reframe       bool              // Кадрирование по высоте страницы без полей
+	Regions       []image.Rectangle // Найденные блоки страницы (пиксели) для остановок прокрутки
+	Emphasis      string            // Выделение фокуса для кадров сценария без своего emphasis
 }
 
 var SupportedTransitions = []string{
@@ -79,11 +103,28 @@ var SupportedTransitions = []string{
 	"radial", "smoothstep", "circularreveal", "pixelize", "dissolve", "none",
 }
 
+var SupportedRenderModes = []string{"zoompan", "native"}
+
 var SupportedZoomModes = []string{
 	"center", "top-left", "top-right", "bottom-left", "bottom-right",
-	"random", "out-center", "out-random",
+	"random", "out-center", "out-random", "scroll",
 }
 
+// SupportedCompositions — композиции кадра режиссера (director.Composition*)
+var SupportedCompositions = []string{"center", "thirds", "leading"}
+
+// SupportedOrderings — порядки обхода блоков режиссера (director.Ordering*)
+var SupportedOrderings = []string{"reading", "greedy", "tsp", "detector"}
+
+// SupportedEmphasisModes — режимы выделения области фокуса (все, что вне блока в фокусе кадра)
+var SupportedEmphasisModes = []string{"none", "dim", "desaturate", "blur", "spotlight"}
+
+// Границы стороны страницы при перекомпоновке (пункты): от дюйма до предела MuPDF
+const (
+	minReflowSide = 72.0
+	maxReflowSide = 14400.0
+)
+
 func (c *Config) Validate() error {
 	if c.Width <= 0 || c.Width%2 != 0 {
 		return fmt.Errorf("width must be positive and even (got %d)", c.Width)
@@ -134,10 +175,239 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("unsupported zoom mode: %s. Supported: %v", c.ZoomMode, SupportedZoomModes)
 	}
 
+	// Validate RenderMode
+	foundRender := false
+	for _, r := range SupportedRenderModes {
+		if c.RenderMode == r {
+			foundRender = true
+			break
+		}
+	}
+	if !foundRender {
+		return fmt.Errorf("unsupported render mode: %s. Supported: %v", c.RenderMode, SupportedRenderModes)
+	}
+
+	// Validate Emphasis
+	foundEmphasis := false
+	for _, e := range SupportedEmphasisModes {
+		if c.Emphasis == e {
+			foundEmphasis = true
+			break
+		}
+	}
+	if !foundEmphasis {
+		return fmt.Errorf("unsupported emphasis: %s. Supported: %v", c.Emphasis, SupportedEmphasisModes)
+	}
+
+	// Validate Composition
+	if c.Composition != "" {
+		foundComposition := false
+		for _, m := range SupportedCompositions {
+			if c.Composition == m {
+				foundComposition = true
+				break
+			}
+		}
+		if !foundComposition {
+			return fmt.Errorf("unsupported composition: %s. Supported: %v", c.Composition, SupportedCompositions)
+		}
+	}
+
+	// Validate Ordering
+	if c.Ordering != "" {
+		foundOrdering := false
+		for _, o := range SupportedOrderings {
+			if c.Ordering == o {
+				foundOrdering = true
+				break
+			}
+		}
+		if !foundOrdering {
+			return fmt.Errorf("unsupported ordering: %s. Supported: %v", c.Ordering, SupportedOrderings)
+		}
+	}
+
+	if c.ReadingWPM < 0 {
+		return fmt.Errorf("reading speed must not be negative: %.0f", c.ReadingWPM)
+	}
+
+	if _, err := ParseSafeArea(c.SafeArea); err != nil {
+		return err
+	}
+	if _, err := ParseExclusions(c.Exclusions); err != nil {
+		return err
+	}
+	if _, _, err := ParseReflowSize(c.ReflowSize); err != nil {
+		return err
+	}
+	if _, err := ParsePages(c.Pages); err != nil {
+		return err
+	}
+
 	// Validate AnalyzeMode
-	if c.AnalyzeMode != "contrast" && c.AnalyzeMode != "ocr" && c.AnalyzeMode != "enhanced" && c.AnalyzeMode != "auto" {
-		return fmt.Errorf("unsupported analyze mode: %s. Use 'auto', 'enhanced', 'contrast' or 'ocr'", c.AnalyzeMode)
+	if c.AnalyzeMode != "contrast" && c.AnalyzeMode != "ocr" && c.AnalyzeMode != "enhanced" && c.AnalyzeMode != "auto" &&
+		c.AnalyzeMode != "panel" && c.AnalyzeMode != "manga" {
+		return fmt.Errorf("unsupported analyze mode: %s. Use 'auto', 'enhanced', 'contrast', 'ocr', 'panel' or 'manga'", c.AnalyzeMode)
 	}
 
 	return nil
 }
+
+// ParseSafeArea разбирает отступы безопасной зоны в долях кадра, как в CSS: одно значение
+// для всех краев, два (верх и низ, лево и право) или четыре (верх, право, низ, лево).
+// Пустая строка — без отступов.
+func ParseSafeArea(s string) ([4]float64, error) {
+	var insets [4]float64
+	if strings.TrimSpace(s) == "" {
+		return insets, nil
+	}
+	values, err := parseFractions(s)
+	if err != nil {
+		return insets, fmt.Errorf("invalid safe area %q: %v", s, err)
+	}
+	switch len(values) {
+	case 1:
+		insets = [4]float64{values[0], values[0], values[0], values[0]}
+	case 2:
+		insets = [4]float64{values[0], values[1], values[0], values[1]}
+	case 4:
+		copy(insets[:], values)
+	default:
+		return insets, fmt.Errorf("invalid safe area %q: use 1, 2 or 4 values", s)
+	}
+	if insets[0]+insets[2] >= 1 || insets[1]+insets[3] >= 1 {
+		return insets, fmt.Errorf("invalid safe area %q: insets leave no room in the frame", s)
+	}
+	return insets, nil
+}
+
+// ParseReflowSize разбирает размер страницы для перекомпоновки документов "ШxВ" в пунктах.
+// Пустая строка дает нули: документ раскладывается по умолчанию MuPDF.
+func ParseReflowSize(s string) (float64, float64, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, 0, nil
+	}
+	ws, hs, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid reflow size %q: use WIDTHxHEIGHT in points, e.g. 480x270", s)
+	}
+	w, errW := strconv.ParseFloat(strings.TrimSpace(ws), 64)
+	h, errH := strconv.ParseFloat(strings.TrimSpace(hs), 64)
+	if errW != nil || errH != nil {
+		return 0, 0, fmt.Errorf("invalid reflow size %q: use WIDTHxHEIGHT in points, e.g. 480x270", s)
+	}
+	if w < minReflowSide || h < minReflowSide || w > maxReflowSide || h > maxReflowSide {
+		return 0, 0, fmt.Errorf("invalid reflow size %q: each side must be between %.0f and %.0f points", s, minReflowSide, maxReflowSide)
+	}
+	return w, h, nil
+}
+
+// PageSelection — выбор страниц -pages: диапазоны номеров с 1, конец 0 — до последней страницы
+type PageSelection struct {
+	Include [][2]int // Пусто — все страницы
+	Exclude [][2]int
+}
+
+// ParsePages разбирает выбор страниц: номера и диапазоны через запятую ("1-5,8,10-"),
+// элементы с "!" исключают страницы ("!3"). Пустая строка выбирает все страницы.
+func ParsePages(s string) (PageSelection, error) {
+	var sel PageSelection
+	for _, part := range strings.Split(s, ",") {
+		item := strings.TrimSpace(part)
+		if item == "" {
+			continue
+		}
+		exclude := strings.HasPrefix(item, "!")
+		span, err := parsePageSpan(strings.TrimSpace(strings.TrimPrefix(item, "!")))
+		if err != nil {
+			return sel, fmt.Errorf("invalid page selection %q: %v", item, err)
+		}
+		if exclude {
+			sel.Exclude = append(sel.Exclude, span)
+		} else {
+			sel.Include = append(sel.Include, span)
+		}
+	}
+	return sel, nil
+}
+
+// parsePageSpan разбирает номер "8" или диапазон "1-5", "10-" (до конца), "-5" (с первой)
+func parsePageSpan(s string) ([2]int, error) {
+	from, to, isRange := strings.Cut(s, "-")
+	if !isRange {
+		to = from
+	}
+	var span [2]int
+	for i, field := range []string{from, to} {
+		field = strings.TrimSpace(field)
+		if field == "" && isRange {
+			continue // Открытый конец диапазона
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 {
+			return span, fmt.Errorf("use page numbers from 1, ranges like 1-5 or 10- and ! to exclude")
+		}
+		span[i] = n
+	}
+	if span[0] == 0 {
+		span[0] = 1
+	}
+	if span[1] != 0 && span[1] < span[0] {
+		return span, fmt.Errorf("range ends before it starts")
+	}
+	return span, nil
+}
+
+// Indices возвращает выбранные страницы документа из count страниц (индексы с 0) по порядку
+func (sel PageSelection) Indices(count int) []int {
+	in := func(spans [][2]int, page int) bool {
+		for _, s := range spans {
+			if page >= s[0] && (s[1] == 0 || page <= s[1]) {
+				return true
+			}
+		}
+		return false
+	}
+	var indices []int
+	for page := 1; page <= count; page++ {
+		if (len(sel.Include) == 0 || in(sel.Include, page)) && !in(sel.Exclude, page) {
+			indices = append(indices, page-1)
+		}
+	}
+	return indices
+}
+
+// ParseExclusions разбирает зоны исключения "x,y,w,h" в долях кадра, разделенные ";"
+func ParseExclusions(s string) ([][4]float64, error) {
+	var zones [][4]float64
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		values, err := parseFractions(part)
+		if err != nil || len(values) != 4 {
+			return nil, fmt.Errorf("invalid exclusion zone %q: use x,y,w,h as shares of the frame", strings.TrimSpace(part))
+		}
+		if values[2] <= 0 || values[3] <= 0 {
+			return nil, fmt.Errorf("invalid exclusion zone %q: width and height must be positive", strings.TrimSpace(part))
+		}
+		zones = append(zones, [4]float64{values[0], values[1], values[2], values[3]})
+	}
+	return zones, nil
+}
+
+// parseFractions разбирает список долей кадра (0..1) через запятую
+func parseFractions(s string) ([]float64, error) {
+	var values []float64
+	for _, field := range strings.Split(s, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", strings.TrimSpace(field))
+		}
+		if v < 0 || v > 1 {
+			return nil, fmt.Errorf("%g is not a share of the frame (0..1)", v)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
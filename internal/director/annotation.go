@@ -0,0 +1,149 @@
+package director
+
+import "math"
+
+// Annotation types
+const (
+	AnnotationBox    = "box"    // Outline of Rect
+	AnnotationCircle = "circle" // Ellipse inscribed into Rect
+	AnnotationArrow  = "arrow"  // Arrow from From to To
+	AnnotationLabel  = "label"  // Text with its top-left corner at At
+)
+
+// AnnotationTypes lists the supported annotation types
+var AnnotationTypes = []string{AnnotationBox, AnnotationCircle, AnnotationArrow, AnnotationLabel}
+
+// Annotation defaults
+const (
+	DefaultAnnotationColor  = "#FF3B30"
+	DefaultAnnotationStroke = 4.0  // Pixels of a 1:1 view
+	DefaultAnnotationFade   = 0.3  // Seconds
+	DefaultLabelSize        = 0.04 // Fraction of the page height
+)
+
+// Annotation is an overlay callout attached to a keyframe. Its geometry uses the
+// coordinates of the keyframe rectangles (page-normalized in a 2.0 scenario), so it
+// is drawn on the page and moves with the camera. Times are relative to the keyframe.
+type Annotation struct {
+	Type       string    `yaml:"type"`                 // box, circle, arrow or label
+	Rect       Rectangle `yaml:"rect,omitempty"`       // box, circle: the marked region
+	From       Point     `yaml:"from,omitempty"`       // arrow: tail
+	To         Point     `yaml:"to,omitempty"`         // arrow: head
+	At         Point     `yaml:"at,omitempty"`         // label: top-left corner of the text
+	Text       string    `yaml:"text,omitempty"`       // label: the text
+	Size       float64   `yaml:"size,omitempty"`       // label: text height as a fraction of the page height
+	Color      string    `yaml:"color,omitempty"`      // #RRGGBB
+	Background string    `yaml:"background,omitempty"` // label: #RRGGBB plate behind the text (none by default)
+	Stroke     float64   `yaml:"stroke,omitempty"`     // Line width in pixels of a 1:1 view, scales with the zoom
+	Appear     float64   `yaml:"appear,omitempty"`     // Seconds after the keyframe time
+	Disappear  float64   `yaml:"disappear,omitempty"`  // Seconds after the keyframe time, 0 = until the slide ends
+	Fade       *float64  `yaml:"fade,omitempty"`       // Fade in and out duration, 0 = pop in and out
+}
+
+// Point is a position in the same coordinates as Rectangle
+type Point struct {
+	X float64 `yaml:"x"`
+	Y float64 `yaml:"y"`
+}
+
+// Normalize converts a viewport point into page coordinates
+func (p Point) Normalize(page Rectangle) Point {
+	if page.W <= 0 || page.H <= 0 {
+		return p
+	}
+	return Point{X: (p.X - page.X) / page.W, Y: (p.Y - page.Y) / page.H}
+}
+
+// Denormalize converts a page point into viewport pixels
+func (p Point) Denormalize(page Rectangle) Point {
+	return Point{X: page.X + p.X*page.W, Y: page.Y + p.Y*page.H}
+}
+
+// Normalize converts the geometry of a viewport annotation into page coordinates
+func (a Annotation) Normalize(page Rectangle) Annotation {
+	a.Rect = a.Rect.Normalize(page)
+	a.From = a.From.Normalize(page)
+	a.To = a.To.Normalize(page)
+	a.At = a.At.Normalize(page)
+	if page.H > 0 {
+		a.Size /= page.H
+	}
+	return a
+}
+
+// Denormalize converts the geometry of a page annotation into viewport pixels
+func (a Annotation) Denormalize(page Rectangle) Annotation {
+	a.Rect = a.Rect.Denormalize(page)
+	a.From = a.From.Denormalize(page)
+	a.To = a.To.Denormalize(page)
+	a.At = a.At.Denormalize(page)
+	a.Size = a.labelSize() * page.H
+	return a
+}
+
+// labelSize returns the label text height with the default applied
+func (a Annotation) labelSize() float64 {
+	if a.Size > 0 {
+		return a.Size
+	}
+	return DefaultLabelSize
+}
+
+// FadeDuration returns the fade in and out duration with the default applied
+func (a Annotation) FadeDuration() float64 {
+	if a.Fade != nil {
+		return math.Max(*a.Fade, 0)
+	}
+	return DefaultAnnotationFade
+}
+
+// StrokeWidth returns the line width with the default applied
+func (a Annotation) StrokeWidth() float64 {
+	if a.Stroke > 0 {
+		return a.Stroke
+	}
+	return DefaultAnnotationStroke
+}
+
+// ColorOrDefault returns the annotation color with the default applied
+func (a Annotation) ColorOrDefault() string {
+	if a.Color != "" {
+		return a.Color
+	}
+	return DefaultAnnotationColor
+}
+
+// TimedAnnotation is an annotation placed on the slide timeline
+type TimedAnnotation struct {
+	Annotation
+	Start float64 // Slide time when the annotation starts to fade in
+	End   float64 // Slide time when it is gone; +Inf keeps it until the slide ends
+}
+
+// Annotations collects the annotations of all keyframes of the slide with their
+// absolute visibility interval
+func (s Slide) Annotations() []TimedAnnotation {
+	var out []TimedAnnotation
+	for _, kf := range s.Keyframes {
+		for _, a := range kf.Annotations {
+			ta := TimedAnnotation{Annotation: a, Start: kf.Time + a.Appear, End: math.Inf(1)}
+			if a.Disappear > 0 {
+				ta.End = kf.Time + a.Disappear
+			}
+			out = append(out, ta)
+		}
+	}
+	return out
+}
+
+// Opacity returns how visible the annotation is at slide time t (0..1)
+func (a TimedAnnotation) Opacity(t float64) float64 {
+	if t < a.Start || t >= a.End {
+		return 0
+	}
+	fade := a.FadeDuration()
+	if fade <= 0 {
+		return 1
+	}
+	return math.Min(1, math.Min((t-a.Start)/fade, (a.End-t)/fade))
+}
@@ -0,0 +1,73 @@
+package director
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSlideAnnotationsTiming(t *testing.T) {
+	noFade := 0.0
+	slide := Slide{Duration: 8, Keyframes: []Keyframe{
+		{Time: 0},
+		{Time: 2, Annotations: []Annotation{
+			{Type: AnnotationBox, Appear: 0.5, Disappear: 3},
+			{Type: AnnotationLabel, Text: "Q3", Fade: &noFade},
+		}},
+	}}
+
+	annotations := slide.Annotations()
+	if len(annotations) != 2 {
+		t.Fatalf("expected 2 annotations, got %d", len(annotations))
+	}
+	box, label := annotations[0], annotations[1]
+	if box.Start != 2.5 || box.End != 5 {
+		t.Errorf("expected box on screen 2.5-5, got %.2f-%.2f", box.Start, box.End)
+	}
+	if !math.IsInf(label.End, 1) {
+		t.Errorf("label without disappear should stay until the slide ends, got %.2f", label.End)
+	}
+
+	tests := []struct {
+		a    TimedAnnotation
+		t    float64
+		want float64
+	}{
+		{box, 2.4, 0},
+		{box, 2.65, 0.5}, // Default fade 0.3s
+		{box, 4, 1},
+		{box, 4.85, 0.5},
+		{box, 5, 0},
+		{label, 2, 1}, // fade: 0 pops in
+		{label, 100, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Opacity(tt.t); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s at %.2f: expected opacity %.2f, got %.2f", tt.a.Type, tt.t, tt.want, got)
+		}
+	}
+}
+
+func TestAnnotationNormalizeRoundTrip(t *testing.T) {
+	page := PageRect(1000, 500, 1280, 720) // {0, 40, 1280, 640}
+	a := Annotation{
+		Type: AnnotationArrow,
+		From: Point{X: 320, Y: 200},
+		To:   Point{X: 640, Y: 360},
+		At:   Point{X: 0, Y: 40},
+		Size: 32,
+	}
+
+	n := a.Normalize(page)
+	if n.From != (Point{X: 0.25, Y: 0.25}) || n.At != (Point{}) || math.Abs(n.Size-0.05) > 1e-9 {
+		t.Errorf("unexpected normalized annotation %+v", n)
+	}
+	back := n.Denormalize(page)
+	if back.From != a.From || back.To != a.To || math.Abs(back.Size-a.Size) > 1e-9 {
+		t.Errorf("round trip changed the annotation: %+v -> %+v", a, back)
+	}
+
+	// The default label size is a fraction of the page height
+	if got := (Annotation{Type: AnnotationLabel}).Denormalize(page).Size; math.Abs(got-DefaultLabelSize*640) > 1e-9 {
+		t.Errorf("expected default label size %.1f, got %.1f", DefaultLabelSize*640, got)
+	}
+}
@@ -0,0 +1,273 @@
+package director
+
+import (
+	"image"
+	"math"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+	"github.com/ivlev/pdf2video/internal/config"
+)
+
+// Supported composition modes
+const (
+	CompositionCenter  = "center"  // Block in the middle of the frame (legacy)
+	CompositionThirds  = "thirds"  // Block on the thirds line closest to its page position
+	CompositionLeading = "leading" // Free space in front of the block in the direction of the next flight
+)
+
+// SupportedCompositions lists the composition modes accepted by the Director;
+// the list lives in config, which validates -composition against it
+var SupportedCompositions = config.SupportedCompositions
+
+const (
+	minAdaptivePadding = 0.6  // Lots of whitespace: show the air around the block
+	maxAdaptivePadding = 0.95 // Tight neighbours: crop close to keep them out
+	thirdsDeadZone     = 0.1  // Blocks this close to the page center (share of page) stay centered
+	safeZoomStep       = 0.9  // Zoom change per step when the page edge pushes a block out of the safe area
+	safeZoomSteps      = 20   // Steps tried each way, down to about an eighth of the fitted zoom
+)
+
+// Shot is the framing of a single block
+type Shot struct {
+	Rect  Rectangle // Keyframe target: the block itself (center) or the camera window
+	Block Rectangle // The framed block (viewport pixels)
+	Zoom  float64
+	X, Y  float64 // Camera center (viewport pixels)
+
+	Covered bool // No zoom keeps the block clear of the covered parts of the frame
+}
+
+// focusRect is the block for the keyframe focus; it is left out when Rect is the block itself
+func (s Shot) focusRect() Rectangle {
+	if s.Rect == s.Block {
+		return Rectangle{}
+	}
+	return s.Block
+}
+
+// composeShots frames every block in visiting order
+func (d *Director) composeShots(blocks []analyzer.Block) []Shot {
+	shots := make([]Shot, len(blocks))
+	for i := range blocks {
+		shots[i] = d.composeShot(blocks, i)
+		if d.Reframe {
+			shots[i] = d.reframeShot(shots[i])
+		}
+	}
+	return shots
+}
+
+// composeShot frames block i. The centered composition keeps the legacy keyframe
+// (block rectangle, camera on its center); other compositions and framing around
+// a safe area write the camera window, whose center is where the renderer points the camera.
+func (d *Director) composeShot(blocks []analyzer.Block, i int) Shot {
+	b := blocks[i].Rect
+	vw, vh := float64(d.ViewportWidth), float64(d.ViewportHeight)
+
+	// The block is fitted into the safe part of the frame rather than the whole frame
+	safe := Rectangle{X: 0, Y: 0, W: vw, H: vh}
+	if d.hasSafeArea() {
+		safe = d.safeFrame(b)
+	}
+	shareX, shareY := safe.W/vw, safe.H/vh
+
+	padX, padY := d.padding(), d.padding()
+	if d.AdaptivePadding {
+		padX, padY = d.adaptivePadding(blocks, i)
+	}
+	zoom := d.zoomToFit(b, padX*shareX, padY*shareY)
+	if d.hasSafeArea() && b.Dx() > 0 && b.Dy() > 0 {
+		// Never zoom in so far that the block spills out of the safe part
+		zoom = math.Min(zoom, math.Max(1.0, math.Min(safe.W/float64(b.Dx()), safe.H/float64(b.Dy()))))
+	}
+
+	if (d.Composition == "" || d.Composition == CompositionCenter) && !d.AdaptivePadding && !d.hasSafeArea() {
+		center := d.calculateCenter(b)
+		return Shot{
+			Rect:  rectFromImage(b),
+			Block: rectFromImage(b),
+			Zoom:  zoom,
+			X:     float64(center.X),
+			Y:     float64(center.Y),
+		}
+	}
+
+	fx, fy := d.compositionAnchor(blocks, i)
+	shot := d.placeShot(b, zoom, safe, fx, fy)
+
+	// Keeping the window on the page can push the block out of the safe part of the frame
+	// again (into the insets or under an exclusion zone). Look for the zoom closest to the
+	// fitted one, between 1:1 and the cap, at which the block lands clear; when there is
+	// none, keep the one that leaves the least of it covered and mark the shot.
+	if !d.hasSafeArea() {
+		return shot
+	}
+	limit := math.Max(zoom, d.MaxZoom)
+	if b.Dx() > 0 && b.Dy() > 0 {
+		limit = math.Max(zoom, math.Min(limit, math.Min(safe.W/float64(b.Dx()), safe.H/float64(b.Dy()))))
+	}
+	best, bestSpill := shot, spill(shot, b, safe, vw, vh)
+	for step := 1; bestSpill > 0 && step <= safeZoomSteps; step++ {
+		k := math.Pow(safeZoomStep, float64(step))
+		for _, z := range []float64{zoom * k, zoom / k} {
+			s := d.placeShot(b, clampRange(z, 1.0, limit), safe, fx, fy)
+			if sp := spill(s, b, safe, vw, vh); sp < bestSpill {
+				best, bestSpill = s, sp
+			}
+		}
+	}
+	best.Covered = bestSpill > 0
+	return best
+}
+
+// placeShot puts the camera window at the given zoom so that block b lands on the anchor
+// (fx, fy) inside the safe part of the frame, and keeps the window on the page
+func (d *Director) placeShot(b image.Rectangle, zoom float64, safe Rectangle, fx, fy float64) Shot {
+	vw, vh := float64(d.ViewportWidth), float64(d.ViewportHeight)
+	shareX, shareY := safe.W/vw, safe.H/vh
+	winW := vw / zoom
+	winH := vh / zoom
+
+	// Shift the camera from the block center so the block lands on the anchor inside the
+	// safe part of the frame, as far as that part has room around the block
+	bcx := float64(b.Min.X+b.Max.X) / 2
+	bcy := float64(b.Min.Y+b.Max.Y) / 2
+	sx, sy := (safe.X+safe.W/2)/vw, (safe.Y+safe.H/2)/vh
+	camX := bcx + (0.5-sx)*winW + clampAbs((0.5-fx)*shareX*winW, math.Max(0, (shareX*winW-float64(b.Dx()))/2))
+	camY := bcy + (0.5-sy)*winH + clampAbs((0.5-fy)*shareY*winH, math.Max(0, (shareY*winH-float64(b.Dy()))/2))
+
+	// Keep the window on the page
+	x0 := clampRange(camX-winW/2, 0, vw-winW)
+	y0 := clampRange(camY-winH/2, 0, vh-winH)
+
+	return Shot{
+		Rect:  Rectangle{X: x0, Y: y0, W: winW, H: winH},
+		Block: rectFromImage(b),
+		Zoom:  zoom,
+		X:     x0 + winW/2,
+		Y:     y0 + winH/2,
+	}
+}
+
+// spill is how far block b, seen through the shot, sticks out of the frame area on all
+// sides together (frame pixels); anything under half a pixel is rounding
+func spill(shot Shot, b image.Rectangle, area Rectangle, vw, vh float64) float64 {
+	sx, sy := vw/shot.Rect.W, vh/shot.Rect.H
+	out := math.Max(0, area.X-(float64(b.Min.X)-shot.Rect.X)*sx) +
+		math.Max(0, area.Y-(float64(b.Min.Y)-shot.Rect.Y)*sy) +
+		math.Max(0, (float64(b.Max.X)-shot.Rect.X)*sx-(area.X+area.W)) +
+		math.Max(0, (float64(b.Max.Y)-shot.Rect.Y)*sy-(area.Y+area.H))
+	if out < 0.5 {
+		return 0
+	}
+	return out
+}
+
+// compositionAnchor returns where the block center should land inside the frame
+// (fractions of the frame size, 0.5 = middle)
+func (d *Director) compositionAnchor(blocks []analyzer.Block, i int) (float64, float64) {
+	b := blocks[i].Rect
+	bcx := float64(b.Min.X+b.Max.X) / 2
+	bcy := float64(b.Min.Y+b.Max.Y) / 2
+
+	switch d.Composition {
+	case CompositionThirds:
+		return thirdsLine(bcx, float64(d.ViewportWidth)), thirdsLine(bcy, float64(d.ViewportHeight))
+
+	case CompositionLeading:
+		// Direction of the next flight; the last block looks along the reading direction
+		dx, dy := 1.0, 0.0
+		if i+1 < len(blocks) {
+			n := blocks[i+1].Rect
+			dx = float64(n.Min.X+n.Max.X)/2 - bcx
+			dy = float64(n.Min.Y+n.Max.Y)/2 - bcy
+		}
+		length := math.Hypot(dx, dy)
+		if length == 0 {
+			return 0.5, 0.5
+		}
+		// Full-strength lead puts the block on the back thirds line
+		return 0.5 - dx/length/6, 0.5 - dy/length/6
+	}
+
+	return 0.5, 0.5
+}
+
+func isComposition(mode string) bool {
+	for _, m := range SupportedCompositions {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// thirdsLine picks the thirds line on the same side of the page as the position
+func thirdsLine(pos, size float64) float64 {
+	if size <= 0 || math.Abs(pos-size/2) < size*thirdsDeadZone {
+		return 0.5
+	}
+	if pos < size/2 {
+		return 1.0 / 3
+	}
+	return 2.0 / 3
+}
+
+// adaptivePadding derives the padding from the whitespace around block i:
+// half of the gap to the nearest neighbour (or page edge) is shown on each side.
+func (d *Director) adaptivePadding(blocks []analyzer.Block, i int) (float64, float64) {
+	b := blocks[i].Rect
+	left := float64(b.Min.X)
+	right := float64(d.ViewportWidth - b.Max.X)
+	top := float64(b.Min.Y)
+	bottom := float64(d.ViewportHeight - b.Max.Y)
+
+	for j, other := range blocks {
+		if j == i {
+			continue
+		}
+		o := other.Rect
+		if o.Min.Y < b.Max.Y && o.Max.Y > b.Min.Y { // Same band: horizontal neighbour
+			if o.Max.X <= b.Min.X {
+				left = math.Min(left, float64(b.Min.X-o.Max.X))
+			} else if o.Min.X >= b.Max.X {
+				right = math.Min(right, float64(o.Min.X-b.Max.X))
+			}
+		}
+		if o.Min.X < b.Max.X && o.Max.X > b.Min.X { // Same column: vertical neighbour
+			if o.Max.Y <= b.Min.Y {
+				top = math.Min(top, float64(b.Min.Y-o.Max.Y))
+			} else if o.Min.Y >= b.Max.Y {
+				bottom = math.Min(bottom, float64(o.Min.Y-b.Max.Y))
+			}
+		}
+	}
+
+	gapX := math.Max(0, math.Min(left, right))
+	gapY := math.Max(0, math.Min(top, bottom))
+	return paddingForGap(float64(b.Dx()), gapX), paddingForGap(float64(b.Dy()), gapY)
+}
+
+func paddingForGap(size, gap float64) float64 {
+	if size <= 0 {
+		return maxAdaptivePadding
+	}
+	return clampRange(size/(size+gap), minAdaptivePadding, maxAdaptivePadding)
+}
+
+func rectFromImage(r image.Rectangle) Rectangle {
+	return Rectangle{X: float64(r.Min.X), Y: float64(r.Min.Y), W: float64(r.Dx()), H: float64(r.Dy())}
+}
+
+// clampAbs limits the magnitude of v to limit
+func clampAbs(v, limit float64) float64 {
+	return math.Max(-limit, math.Min(limit, v))
+}
+
+// clampRange limits v to [lo, hi]; if the range is empty it returns its middle
+func clampRange(v, lo, hi float64) float64 {
+	if hi < lo {
+		return (lo + hi) / 2
+	}
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,220 @@
+package director
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+func TestComposeShot_CenterKeepsLegacyKeyframe(t *testing.T) {
+	d := NewDirector(1280, 720)
+	blocks := []analyzer.Block{{Rect: image.Rect(100, 100, 300, 200)}}
+
+	shot := d.composeShot(blocks, 0)
+	if shot.Rect != (Rectangle{X: 100, Y: 100, W: 200, H: 100}) {
+		t.Errorf("expected block rectangle, got %+v", shot.Rect)
+	}
+	if shot.Zoom != d.calculateZoom(blocks[0].Rect) {
+		t.Errorf("expected legacy zoom %f, got %f", d.calculateZoom(blocks[0].Rect), shot.Zoom)
+	}
+}
+
+func TestComposeShot_Thirds(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.Composition = CompositionThirds
+	d.MaxZoom = 2.0
+
+	// Small block in the upper-left part of the page
+	blocks := []analyzer.Block{{Rect: image.Rect(280, 150, 340, 190)}}
+	shot := d.composeShot(blocks, 0)
+
+	// Window matches the viewport aspect at the shot zoom
+	if math.Abs(shot.Rect.W-1280/shot.Zoom) > 1 || math.Abs(shot.Rect.H-720/shot.Zoom) > 1 {
+		t.Fatalf("expected camera window for zoom %.2f, got %+v", shot.Zoom, shot.Rect)
+	}
+
+	// Block center lands on the left/top thirds lines of the window
+	fx := (310 - shot.Rect.X) / shot.Rect.W
+	fy := (170 - shot.Rect.Y) / shot.Rect.H
+	if math.Abs(fx-1.0/3) > 0.01 || math.Abs(fy-1.0/3) > 0.01 {
+		t.Errorf("expected block on thirds (0.33, 0.33), got (%.2f, %.2f)", fx, fy)
+	}
+
+	// The keyframes keep the block itself as the focus, next to the camera window
+	scenario, err := d.GenerateScenario(blocks, "#1", 10, 0.5, 1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var region Keyframe
+	for _, kf := range scenario.Slides[0].Keyframes {
+		if kf.Focus == "region_1" {
+			region = kf
+		}
+	}
+	want := (Rectangle{X: 280, Y: 150, W: 60, H: 40}).Normalize(d.pageRect())
+	if region.FocusRect != want || region.FocusArea() == region.Rect {
+		t.Errorf("expected the block %+v as the focus of %+v", want, region)
+	}
+}
+
+func TestComposeShot_BlockStaysInFrame(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.Composition = CompositionThirds
+
+	// Wide block: there is little room to offset it
+	blocks := []analyzer.Block{{Rect: image.Rect(50, 100, 600, 250)}}
+	shot := d.composeShot(blocks, 0)
+
+	b := rectFromImage(blocks[0].Rect)
+	if b.X < shot.Rect.X || b.X+b.W > shot.Rect.X+shot.Rect.W+1 ||
+		b.Y < shot.Rect.Y || b.Y+b.H > shot.Rect.Y+shot.Rect.H+1 {
+		t.Errorf("block %v is cut by the window %+v", b, shot.Rect)
+	}
+	if shot.Rect.X < 0 || shot.Rect.Y < 0 || shot.Rect.X+shot.Rect.W > 1280 || shot.Rect.Y+shot.Rect.H > 720 {
+		t.Errorf("window %+v leaves the page", shot.Rect)
+	}
+}
+
+func TestComposeShot_LeadingSpace(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.Composition = CompositionLeading
+	d.MaxZoom = 2.0
+
+	// Next block is to the right: free space should be on the right of the first block
+	blocks := []analyzer.Block{
+		{Rect: image.Rect(400, 340, 460, 380)},
+		{Rect: image.Rect(900, 340, 960, 380)},
+	}
+	shot := d.composeShot(blocks, 0)
+	if shot.X <= 430 {
+		t.Errorf("expected camera ahead of the block (x > 430), got %.1f", shot.X)
+	}
+	if math.Abs(shot.Y-360) > 1 {
+		t.Errorf("expected no vertical offset for a horizontal flight, got y=%.1f", shot.Y)
+	}
+}
+
+func TestAdaptivePadding(t *testing.T) {
+	d := NewDirector(1280, 720)
+
+	crowded := []analyzer.Block{
+		{Rect: image.Rect(300, 300, 500, 400)},
+		{Rect: image.Rect(505, 300, 700, 400)}, // 5px gap to the right
+		{Rect: image.Rect(300, 405, 500, 450)}, // 5px gap below
+	}
+	padX, padY := d.adaptivePadding(crowded, 0)
+	if padX < 0.9 || padY < 0.9 {
+		t.Errorf("expected tight padding with close neighbours, got (%.2f, %.2f)", padX, padY)
+	}
+
+	alone := []analyzer.Block{{Rect: image.Rect(540, 310, 740, 410)}}
+	padX, padY = d.adaptivePadding(alone, 0)
+	if padX > 0.7 || padY > 0.7 {
+		t.Errorf("expected generous padding with whitespace around, got (%.2f, %.2f)", padX, padY)
+	}
+
+	// Same block size, different surroundings
+	d.AdaptivePadding = true
+	isolated := []analyzer.Block{{Rect: image.Rect(340, 210, 940, 510)}}
+	packed := []analyzer.Block{
+		{Rect: image.Rect(340, 210, 940, 510)},
+		{Rect: image.Rect(950, 210, 1270, 510)},
+		{Rect: image.Rect(340, 520, 940, 710)},
+		{Rect: image.Rect(0, 210, 330, 510)},
+		{Rect: image.Rect(340, 0, 940, 200)},
+	}
+	if wide, tight := d.composeShot(isolated, 0).Zoom, d.composeShot(packed, 0).Zoom; wide >= tight {
+		t.Errorf("expected more air (lower zoom) for the isolated block, got %.2f vs %.2f", wide, tight)
+	}
+}
+
+// frameRect maps a block into the output frame of a shot
+func frameRect(shot Shot, b Rectangle, vw, vh float64) Rectangle {
+	return Rectangle{
+		X: (b.X-shot.X)*shot.Zoom + vw/2,
+		Y: (b.Y-shot.Y)*shot.Zoom + vh/2,
+		W: b.W * shot.Zoom,
+		H: b.H * shot.Zoom,
+	}
+}
+
+func TestComposeShot_SafeArea(t *testing.T) {
+	d := NewDirector(1080, 1920)
+	d.MaxZoom = 4
+	d.SafeArea = Insets{Top: 0.1, Right: 0.15, Bottom: 0.25}
+	qr := Rectangle{X: 1080 - 20 - 300, Y: 1920 - 20 - 300, W: 300, H: 300}
+	d.Exclusions = []Rectangle{qr}
+
+	// A block near the bottom-right corner, where both the UI and the QR code are
+	blocks := []analyzer.Block{{Rect: image.Rect(700, 1500, 1000, 1700)}}
+	shot := d.composeShot(blocks, 0)
+
+	f := frameRect(shot, rectFromImage(blocks[0].Rect), 1080, 1920)
+	if f.X < -1e-6 || f.Y < 192-1e-6 || f.X+f.W > 1080*0.85+1e-6 || f.Y+f.H > 1920*0.75+1e-6 {
+		t.Errorf("block lands outside the safe area: %+v (zoom %.2f)", f, shot.Zoom)
+	}
+	if overlaps(f, qr) {
+		t.Errorf("block lands under the QR code: %+v", f)
+	}
+
+	// The zoom is capped by the safe part of the frame, not the whole frame
+	unsafe := NewDirector(1080, 1920)
+	unsafe.MaxZoom = 4
+	if full := unsafe.composeShot(blocks, 0); shot.Zoom >= full.Zoom {
+		t.Errorf("expected a smaller zoom with the safe area, got %.2f vs %.2f", shot.Zoom, full.Zoom)
+	}
+}
+
+func TestComposeShot_SafeAreaAtPageEdge(t *testing.T) {
+	d := NewDirector(1080, 1920)
+	d.MaxZoom = 6
+	d.Padding = 0.5
+	d.SafeArea = Insets{Bottom: 0.25}
+
+	// Near the page bottom the window is held on the page, which pushes the block under
+	// the bottom inset at the fitted zoom; a closer shot lifts it clear
+	blocks := []analyzer.Block{{Rect: image.Rect(440, 1700, 640, 1800)}}
+	shot := d.composeShot(blocks, 0)
+	f := frameRect(shot, rectFromImage(blocks[0].Rect), 1080, 1920)
+	if f.Y < -1e-6 || f.Y+f.H > 1920*0.75+0.5 || shot.Covered {
+		t.Errorf("block lands under the bottom inset: %+v (zoom %.2f, covered %v)", f, shot.Zoom, shot.Covered)
+	}
+	if fitted := d.zoomToFit(blocks[0].Rect, 0.5, 0.5*0.75); shot.Zoom <= fitted {
+		t.Errorf("expected the zoom to change from the fitted %.2f, got %.2f", fitted, shot.Zoom)
+	}
+
+	// A block on the very edge stays covered at any zoom and is reported
+	blocks = []analyzer.Block{{Rect: image.Rect(440, 1800, 640, 1910)}}
+	if shot := d.composeShot(blocks, 0); !shot.Covered {
+		t.Errorf("expected the edge block to be marked covered (zoom %.2f)", shot.Zoom)
+	}
+	if _, err := d.GenerateScenario(blocks, "#1", 5, 0.5, 1); err != nil || d.Covered != 1 {
+		t.Errorf("expected one covered block, got %d (%v)", d.Covered, err)
+	}
+}
+
+func TestSafeFrame_CutsAroundZones(t *testing.T) {
+	d := NewDirector(1920, 1080)
+	d.Exclusions = []Rectangle{{X: 1600, Y: 760, W: 300, H: 300}}
+
+	// A wide block keeps the full width above the zone, a tall one the full height beside it
+	if got := d.safeFrame(image.Rect(0, 0, 800, 100)); got != (Rectangle{X: 0, Y: 0, W: 1920, H: 760}) {
+		t.Errorf("wide block: unexpected safe frame %+v", got)
+	}
+	if got := d.safeFrame(image.Rect(0, 0, 100, 800)); got != (Rectangle{X: 0, Y: 0, W: 1600, H: 1080}) {
+		t.Errorf("tall block: unexpected safe frame %+v", got)
+	}
+}
+
+func TestSupportedCompositions(t *testing.T) {
+	for _, mode := range []string{CompositionCenter, CompositionThirds, CompositionLeading} {
+		if !isComposition(mode) {
+			t.Errorf("composition %q is missing from the supported list", mode)
+		}
+	}
+	if len(SupportedCompositions) != 3 {
+		t.Errorf("expected 3 supported compositions, got %v", SupportedCompositions)
+	}
+}
@@ -11,19 +11,48 @@ import (
 
 // SlideTimings holds the calculated durations for all parts of a slide
 type SlideTimings struct {
-	Intro    float64
-	Outro    float64
-	Fade     float64
-	Dwell    []float64 // Indexed same as blocks
-	Total    float64
+	Intro  float64
+	Outro  float64
+	Fade   float64
+	Travel []float64 // Flight time to each block, indexed same as blocks
+	Dwell  []float64 // Hold time on each block, indexed same as blocks
+	Hold   float64   // Time left over by dwell clamping, spent on the last block before the outro
+	Total  float64
 }
 
+// maxTravelShare limits the part of the block budget that may be spent on flights
+const maxTravelShare = 0.5
+
 // Director generates camera path scenarios from detected blocks
 type Director struct {
-	ViewportWidth  int
-	ViewportHeight int
-	MinDwell       float64 // Minimum time per block (seconds)
-	MaxDwell       float64 // Maximum time per block (seconds)
+	ViewportWidth   int
+	ViewportHeight  int
+	MinDwell        float64 // Minimum time per block (seconds)
+	MaxDwell        float64 // Maximum time per block (seconds)
+	CameraSpeed     float64 // Comfortable pan speed (viewport diagonals per second)
+	ZoomRate        float64 // Comfortable zoom speed (zoom doublings per second)
+	MinTravel       float64 // Minimum flight time between blocks (seconds)
+	MaxTravel       float64 // Maximum flight time between blocks (seconds)
+	MinZoom         float64 // Lower zoom clamp for focused blocks
+	MaxZoom         float64 // Upper zoom clamp for focused blocks
+	Padding         float64 // Share of the viewport a focused block may fill (0..1)
+	Optimizer       *TrajectoryOptimizer
+	Composition     string  // Framing of focused blocks: center, thirds or leading
+	AdaptivePadding bool    // Derive padding from the whitespace around each block
+	ReadingWPM      float64 // Reading speed for dwell from word counts (0 = weights only)
+	Ordering        string  // Visiting order: reading, greedy or tsp
+	PageWidth       int     // Size of the analysed page image (block coordinates); 0 = viewport pixels
+	PageHeight      int
+	Reframe         bool // Keep the camera on the page (cover crop), never showing the letterbox bars
+
+	// Covered parts of the output frame: focused blocks are framed away from them and the
+	// zoom is capped so that a block never lands under platform UI or the QR code
+	SafeArea   Insets      // Edges covered by platform UI (shares of the frame)
+	Exclusions []Rectangle // Frame regions (viewport pixels) that stay clear of content
+
+	// Covered is set by GenerateScenario: the number of blocks that stay partly under
+	// the safe-area insets or an exclusion zone however they are framed
+	Covered int
 }
 
 // NewDirector creates a new Director with default settings
@@ -33,6 +62,17 @@ func NewDirector(viewportWidth, viewportHeight int) *Director {
 		ViewportHeight: viewportHeight,
 		MinDwell:       1.0,
 		MaxDwell:       3.0,
+		CameraSpeed:    0.6,
+		ZoomRate:       1.5,
+		MinTravel:      0.4,
+		MaxTravel:      2.0,
+		MinZoom:        1.0,
+		MaxZoom:        3.0,
+		Padding:        0.9,
+		Optimizer:      NewTrajectoryOptimizer(),
+		Composition:    CompositionCenter,
+		ReadingWPM:     200,
+		Ordering:       OrderingReading,
 	}
 }
 
@@ -42,24 +82,32 @@ func (d *Director) GenerateScenario(blocks []analyzer.Block, input string, total
 		return nil, fmt.Errorf("no blocks detected")
 	}
 
-	// Sort blocks in reading order (top-to-bottom, left-to-right)
-	sortedBlocks := d.sortBlocks(blocks)
+	// Visiting order: reading order or a route from the trajectory optimizer
+	sortedBlocks, ordering := d.orderBlocks(d.toViewport(blocks))
 
 	// Calculate durations per block and slide components using adaptive logic
 	timings := d.calculateDwellTimes(totalDuration, fadeDuration, outroDuration, sortedBlocks)
 
-	// Generate keyframes
+	// Generate keyframes and store them relative to the page, independent of DPI and output size
 	keyframes := d.generateKeyframes(sortedBlocks, timings)
+	page := d.pageRect()
+	for i := range keyframes {
+		keyframes[i].Rect = keyframes[i].Rect.Normalize(page)
+		if keyframes[i].FocusRect != (Rectangle{}) {
+			keyframes[i].FocusRect = keyframes[i].FocusRect.Normalize(page)
+		}
+	}
 
 	slide := Slide{
 		ID:        1,
 		Input:     input,
 		Duration:  totalDuration,
+		Ordering:  ordering,
 		Keyframes: keyframes,
 	}
 
 	scenario := &Scenario{
-		Version: "1.0",
+		Version: ScenarioVersion,
 		Slides:  []Slide{slide},
 	}
 
@@ -87,8 +135,9 @@ func (d *Director) sortBlocks(blocks []analyzer.Block) []analyzer.Block {
 	return sorted
 }
 
-// calculateDwellTimes determines adaptive stay duration for each block based on importance and content.
-// It ensures that Intro + Outro + Fade + sum(Dwell) == totalDuration.
+// calculateDwellTimes determines flight time to each block and adaptive stay duration on it.
+// Flights are carved out of the block budget first, the rest is distributed by importance and content.
+// It ensures that Intro + Outro + Fade + sum(Travel) + sum(Dwell) + Hold == totalDuration.
 func (d *Director) calculateDwellTimes(totalDuration, fadeDuration, outroDuration float64, blocks []analyzer.Block) SlideTimings {
 	res := SlideTimings{
 		Fade:  fadeDuration,
@@ -128,36 +177,48 @@ func (d *Director) calculateDwellTimes(totalDuration, fadeDuration, outroDuratio
 		res.Outro = outroNominal
 	}
 
-	availableDuration := totalDuration - (res.Intro + res.Outro + res.Fade)
-	if availableDuration < 0.001 {
-		availableDuration = 0.001 // Minimum available for division
+	blockBudget := totalDuration - (res.Intro + res.Outro + res.Fade)
+	if blockBudget < 0 {
+		blockBudget = 0
 	}
 
+	// Travel time between blocks, never more than maxTravelShare of the budget
+	travel := d.calculateTravelTimes(blocks)
+	travelTotal := sum(travel)
+	if limit := blockBudget * maxTravelShare; travelTotal > limit {
+		scale := limit / travelTotal
+		for i := range travel {
+			travel[i] *= scale
+		}
+		travelTotal = limit
+	}
+
+	availableDuration := blockBudget - travelTotal
+
 	// 2. Assign weights
+	// With a text layer the weight is the reading time of the block; otherwise importance and content.
+	reading := d.ReadingWPM > 0 && hasWordCounts(blocks)
 	weights := make([]float64, len(blocks))
 	totalWeight := 0.0
 
 	for i, b := range blocks {
-		weight := b.Priority
-		if weight == 0 {
-			weight = 0.5
-		}
-
-		multiplier := 1.0
-		switch b.Type {
-		case analyzer.BlockTypeHeader:
-			multiplier = 0.8
-		case analyzer.BlockTypeChart, analyzer.BlockTypeDiagram:
-			multiplier = 1.5
-		case analyzer.BlockTypeImage:
-			multiplier = 1.1
-		case analyzer.BlockTypeText:
-			multiplier = 1.2
-		}
-		weight *= multiplier
+		var weight float64
+		if reading {
+			// Wordless blocks (images, charts) get a glance scaled by their type
+			weight = d.MinDwell * typeMultiplier(b.Type)
+			if b.WordCount > 0 {
+				weight = math.Max(float64(b.WordCount)/d.ReadingWPM*60, d.MinDwell)
+			}
+		} else {
+			weight = b.Priority
+			if weight == 0 {
+				weight = 0.5
+			}
+			weight *= typeMultiplier(b.Type)
 
-		if b.Metrics.EdgeDensity > 0 {
-			weight *= (1.0 + b.Metrics.EdgeDensity)
+			if b.Metrics.EdgeDensity > 0 {
+				weight *= (1.0 + b.Metrics.EdgeDensity)
+			}
 		}
 
 		weights[i] = weight
@@ -182,7 +243,7 @@ func (d *Director) calculateDwellTimes(totalDuration, fadeDuration, outroDuratio
 	// If sum(minDwell) > availableDuration, we scale minDwell down
 	minTotal := d.MinDwell * float64(len(blocks))
 	effectiveMin := d.MinDwell
-	if minTotal > availableDuration && availableDuration > 0 {
+	if minTotal > availableDuration {
 		effectiveMin = availableDuration / float64(len(blocks))
 	}
 
@@ -212,47 +273,140 @@ func (d *Director) calculateDwellTimes(totalDuration, fadeDuration, outroDuratio
 		}
 	}
 
+	// Clamping may leave a residual: the camera stays on the last block until the outro,
+	// so flights keep their comfortable speed and never exceed MaxTravel
+	residual := availableDuration - sum(durations)
+	if residual >= 0 {
+		res.Hold = residual
+	} else if travelTotal > 0 {
+		// Blocks need more than is left: flights get shorter, never longer
+		scale := math.Max(travelTotal+residual, 0) / travelTotal
+		for i := range travel {
+			travel[i] *= scale
+		}
+	}
+
+	res.Travel = travel
 	res.Dwell = durations
 	return res
 }
 
+// typeMultiplier is the relative attention a block type needs
+func typeMultiplier(t analyzer.BlockType) float64 {
+	switch t {
+	case analyzer.BlockTypeHeader:
+		return 0.8
+	case analyzer.BlockTypeChart, analyzer.BlockTypeDiagram:
+		return 1.5
+	case analyzer.BlockTypeImage:
+		return 1.1
+	case analyzer.BlockTypeText:
+		return 1.2
+	}
+	return 1.0
+}
+
+// hasWordCounts reports whether word counts from a text layer are available
+func hasWordCounts(blocks []analyzer.Block) bool {
+	for _, b := range blocks {
+		if b.WordCount > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// calculateTravelTimes returns the nominal flight time to each block.
+// The camera starts from the full view, then flies from block to block; each flight
+// takes as long as the slower of the pan (at CameraSpeed) and the zoom change (at ZoomRate)
+// between the framed shots.
+func (d *Director) calculateTravelTimes(blocks []analyzer.Block) []float64 {
+	travel := make([]float64, len(blocks))
+
+	diagonal := math.Hypot(float64(d.ViewportWidth), float64(d.ViewportHeight))
+	start := d.fullView()
+	prevX, prevY, prevZoom := start.X, start.Y, start.Zoom
+
+	for i, shot := range d.composeShots(blocks) {
+		panTime := 0.0
+		if d.CameraSpeed > 0 && diagonal > 0 {
+			dist := math.Hypot(shot.X-prevX, shot.Y-prevY)
+			panTime = dist / diagonal / d.CameraSpeed
+		}
+
+		zoomTime := 0.0
+		if d.ZoomRate > 0 {
+			zoomTime = math.Abs(math.Log2(shot.Zoom/prevZoom)) / d.ZoomRate
+		}
+
+		t := math.Max(panTime, zoomTime)
+		t = math.Max(t, d.MinTravel)
+		if d.MaxTravel > 0 {
+			t = math.Min(t, d.MaxTravel)
+		}
+		travel[i] = t
+
+		prevX, prevY, prevZoom = shot.X, shot.Y, shot.Zoom
+	}
+
+	return travel
+}
+
 // generateKeyframes creates keyframes for camera movement using adaptive dwell durations and precise slide timings
 func (d *Director) generateKeyframes(blocks []analyzer.Block, t SlideTimings) []Keyframe {
 	keyframes := []Keyframe{}
 
+	full := d.fullView()
+
 	// Start with full view
 	keyframes = append(keyframes, Keyframe{
 		Time:  0.0,
 		Focus: "full_view",
-		Rect: Rectangle{
-			X: 0,
-			Y: 0,
-			W: d.ViewportWidth,
-			H: d.ViewportHeight,
-		},
-		Zoom: 1.0,
+		Rect:  full.Rect,
+		Zoom:  full.Zoom,
 	})
 
-	currentTime := t.Intro
+	// Establishing shot: hold the full view during the intro
+	if t.Intro > 0 {
+		keyframes = append(keyframes, Keyframe{
+			Time:  t.Intro,
+			Focus: "full_view",
+			Rect:  full.Rect,
+			Zoom:  full.Zoom,
+		})
+	}
 
-	// Generate keyframes for each block using its adaptive duration
-	for i, block := range blocks {
-		zoom := d.calculateZoom(block.Rect)
-		localDwell := t.Dwell[i]
+	currentTime := t.Intro
 
+	// Each block gets an arrival keyframe (end of flight) and a hold keyframe (end of dwell).
+	// The hold of the last block is closed by the outro_stable keyframe below.
+	shots := d.composeShots(blocks)
+	d.Covered = 0
+	for i, shot := range shots {
+		if shot.Covered {
+			d.Covered++
+		}
+		if i < len(t.Travel) {
+			currentTime += t.Travel[i]
+		}
 		keyframes = append(keyframes, Keyframe{
-			Time:  currentTime,
-			Focus: fmt.Sprintf("region_%d", i+1),
-			Rect: Rectangle{
-				X: block.Rect.Min.X,
-				Y: block.Rect.Min.Y,
-				W: block.Rect.Dx(),
-				H: block.Rect.Dy(),
-			},
-			Zoom: zoom,
+			Time:      currentTime,
+			Focus:     fmt.Sprintf("region_%d", i+1),
+			Rect:      shot.Rect,
+			FocusRect: shot.focusRect(),
+			Zoom:      shot.Zoom,
 		})
 
-		currentTime += localDwell
+		currentTime += t.Dwell[i]
+		if i < len(blocks)-1 {
+			keyframes = append(keyframes, Keyframe{
+				Time:      currentTime,
+				Focus:     fmt.Sprintf("region_%d_hold", i+1),
+				Rect:      shot.Rect,
+				FocusRect: shot.focusRect(),
+				Zoom:      shot.Zoom,
+			})
+		}
 	}
 
 	// End of blocks, finish exactly outroDuration before the fade starts
@@ -262,18 +416,14 @@ func (d *Director) generateKeyframes(blocks []analyzer.Block, t SlideTimings) []
 	}
 
 	// Fix the current state before zoom-out starts to ensure exact duration
-	if len(blocks) > 0 {
-		lastBlock := blocks[len(blocks)-1]
+	if len(shots) > 0 {
+		lastShot := shots[len(shots)-1]
 		keyframes = append(keyframes, Keyframe{
-			Time:  outroZoomOutStartTime,
-			Focus: "outro_stable",
-			Rect: Rectangle{
-				X: lastBlock.Rect.Min.X,
-				Y: lastBlock.Rect.Min.Y,
-				W: lastBlock.Rect.Dx(),
-				H: lastBlock.Rect.Dy(),
-			},
-			Zoom: d.calculateZoom(lastBlock.Rect),
+			Time:      outroZoomOutStartTime,
+			Focus:     "outro_stable",
+			Rect:      lastShot.Rect,
+			FocusRect: lastShot.focusRect(),
+			Zoom:      lastShot.Zoom,
 		})
 	}
 
@@ -281,26 +431,16 @@ func (d *Director) generateKeyframes(blocks []analyzer.Block, t SlideTimings) []
 	keyframes = append(keyframes, Keyframe{
 		Time:  t.Total - t.Fade,
 		Focus: "full_view",
-		Rect: Rectangle{
-			X: 0,
-			Y: 0,
-			W: d.ViewportWidth,
-			H: d.ViewportHeight,
-		},
-		Zoom: 1.0,
+		Rect:  full.Rect,
+		Zoom:  full.Zoom,
 	})
 
 	// Maintain full view during the crossfade
 	keyframes = append(keyframes, Keyframe{
 		Time:  t.Total,
 		Focus: "full_view",
-		Rect: Rectangle{
-			X: 0,
-			Y: 0,
-			W: d.ViewportWidth,
-			H: d.ViewportHeight,
-		},
-		Zoom: 1.0,
+		Rect:  full.Rect,
+		Zoom:  full.Zoom,
 	})
 
 	return keyframes
@@ -308,10 +448,22 @@ func (d *Director) generateKeyframes(blocks []analyzer.Block, t SlideTimings) []
 
 // calculateZoom determines zoom level to fit block in viewport
 func (d *Director) calculateZoom(block image.Rectangle) float64 {
-	padding := 0.9 // Use 90% of viewport
+	padding := d.padding()
+	return d.zoomToFit(block, padding, padding)
+}
 
-	viewportW := float64(d.ViewportWidth) * padding
-	viewportH := float64(d.ViewportHeight) * padding
+// padding returns the share of the viewport a focused block may fill
+func (d *Director) padding() float64 {
+	if d.Padding <= 0 || d.Padding > 1 {
+		return 0.9 // Use 90% of viewport
+	}
+	return d.Padding
+}
+
+// zoomToFit determines zoom level so that the block fills the given share of the viewport
+func (d *Director) zoomToFit(block image.Rectangle, padX, padY float64) float64 {
+	viewportW := float64(d.ViewportWidth) * padX
+	viewportH := float64(d.ViewportHeight) * padY
 
 	blockW := float64(block.Dx())
 	blockH := float64(block.Dy())
@@ -326,12 +478,20 @@ func (d *Director) calculateZoom(block image.Rectangle) float64 {
 	// Use the smaller scale to ensure block fits
 	zoom := math.Min(scaleX, scaleY)
 
-	// Clamp zoom to reasonable range
-	if zoom < 1.0 {
-		zoom = 1.0
+	// Clamp zoom to the configured range
+	minZoom := math.Max(d.MinZoom, 1.0)
+	maxZoom := d.MaxZoom
+	if maxZoom <= 0 {
+		maxZoom = 3.0
+	}
+	if maxZoom < minZoom {
+		maxZoom = minZoom
+	}
+	if zoom < minZoom {
+		zoom = minZoom
 	}
-	if zoom > 3.0 {
-		zoom = 3.0
+	if zoom > maxZoom {
+		zoom = maxZoom
 	}
 
 	return zoom
@@ -345,6 +505,15 @@ func (d *Director) calculateCenter(rect image.Rectangle) image.Point {
 	}
 }
 
+// sum returns the sum of the values
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
 // abs returns absolute value of an integer
 func abs(x int) int {
 	if x < 0 {
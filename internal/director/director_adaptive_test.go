@@ -46,6 +46,9 @@ func TestCalculateDwellTimes(t *testing.T) {
 
 	// Verify total duration
 	sum := timings.Intro + timings.Outro + timings.Fade
+	for _, v := range timings.Travel {
+		sum += v
+	}
 	for _, v := range timings.Dwell {
 		sum += v
 	}
@@ -55,6 +58,10 @@ func TestCalculateDwellTimes(t *testing.T) {
 	}
 
 	// Verify that Chart (index 1) has more time than Header (index 0)
+	if len(timings.Travel) != len(blocks) {
+		t.Fatalf("expected %d travel times, got %d", len(blocks), len(timings.Travel))
+	}
+
 	if timings.Dwell[1] <= timings.Dwell[0] {
 		t.Errorf("expected Chart to have more time than Header, got %f vs %f", timings.Dwell[1], timings.Dwell[0])
 	}
@@ -102,6 +109,9 @@ func TestCalculateDwellTimes_ShortSlideSync(t *testing.T) {
 
 	// Check if total matches
 	sum := timings.Intro + timings.Outro + timings.Fade
+	for _, tv := range timings.Travel {
+		sum += tv
+	}
 	for _, dv := range timings.Dwell {
 		sum += dv
 	}
@@ -119,3 +129,38 @@ func TestCalculateDwellTimes_ShortSlideSync(t *testing.T) {
 		t.Errorf("expected intro 0.75, got %f", timings.Intro)
 	}
 }
+
+func TestCalculateDwellTimes_ReadingTime(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.MinDwell = 1.0
+	d.MaxDwell = 10.0
+
+	// Same type and priority: only the amount of text differs
+	blocks := []analyzer.Block{
+		{Type: analyzer.BlockTypeText, Priority: 0.7, Rect: image.Rect(0, 0, 400, 50), WordCount: 2},
+		{Type: analyzer.BlockTypeText, Priority: 0.7, Rect: image.Rect(0, 100, 400, 500), WordCount: 60},
+		{Type: analyzer.BlockTypeImage, Priority: 0.7, Rect: image.Rect(500, 100, 900, 500)},
+	}
+
+	timings := d.calculateDwellTimes(20.0, 0.5, 1.0, blocks)
+	caption, paragraph, picture := timings.Dwell[0], timings.Dwell[1], timings.Dwell[2]
+
+	if paragraph < 2*caption {
+		t.Errorf("expected a dense paragraph to get much more time than a caption, got %.2f vs %.2f", paragraph, caption)
+	}
+	if caption < d.MinDwell-0.001 || picture < d.MinDwell-0.001 {
+		t.Errorf("expected MinDwell to apply, got caption %.2f, picture %.2f", caption, picture)
+	}
+
+	total := timings.Intro + timings.Outro + timings.Fade + sum(timings.Travel) + sum(timings.Dwell) + timings.Hold
+	if math.Abs(total-20.0) > 0.001 {
+		t.Errorf("expected total 20.0, got %f", total)
+	}
+
+	// Without reading speed the word counts are ignored
+	d.ReadingWPM = 0
+	timings = d.calculateDwellTimes(20.0, 0.5, 1.0, blocks)
+	if math.Abs(timings.Dwell[0]-timings.Dwell[1]) > 0.001 {
+		t.Errorf("expected equal dwell without reading time, got %.2f vs %.2f", timings.Dwell[0], timings.Dwell[1])
+	}
+}
@@ -29,8 +29,8 @@ func TestDirector(t *testing.T) {
 		t.Fatalf("GenerateScenario failed: %v", err)
 	}
 
-	if scenario.Version != "1.0" {
-		t.Errorf("Expected version 1.0, got %s", scenario.Version)
+	if scenario.Version != ScenarioVersion {
+		t.Errorf("Expected version %s, got %s", ScenarioVersion, scenario.Version)
 	}
 
 	if len(scenario.Slides) != 1 {
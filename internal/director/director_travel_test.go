@@ -0,0 +1,149 @@
+package director
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+func TestCalculateTravelTimes_ProportionalToDistance(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.MinTravel = 0.1
+	d.MaxTravel = 10.0
+
+	blocks := []analyzer.Block{
+		{Priority: 0.5, Rect: image.Rect(600, 320, 680, 400)},   // Near the center
+		{Priority: 0.5, Rect: image.Rect(630, 320, 710, 400)},   // 30px nudge
+		{Priority: 0.5, Rect: image.Rect(1150, 620, 1230, 700)}, // Across the page
+	}
+
+	travel := d.calculateTravelTimes(blocks)
+	if len(travel) != len(blocks) {
+		t.Fatalf("expected %d travel times, got %d", len(blocks), len(travel))
+	}
+
+	if travel[2] <= travel[1] {
+		t.Errorf("expected long flight to take more time than a nudge, got %f vs %f", travel[2], travel[1])
+	}
+
+	if travel[1] != d.MinTravel {
+		t.Errorf("expected nudge to take MinTravel %f, got %f", d.MinTravel, travel[1])
+	}
+}
+
+func TestCalculateTravelTimes_ZoomChange(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.MinTravel = 0.0
+	d.MaxTravel = 10.0
+
+	// Same center as the viewport: the flight is only a zoom change
+	blocks := []analyzer.Block{
+		{Priority: 0.5, Rect: image.Rect(426, 240, 853, 480)},
+	}
+
+	travel := d.calculateTravelTimes(blocks)
+	zoom := d.calculateZoom(blocks[0].Rect)
+	expected := math.Log2(zoom) / d.ZoomRate
+	if math.Abs(travel[0]-expected) > 0.01 {
+		t.Errorf("expected zoom-only flight %f, got %f", expected, travel[0])
+	}
+}
+
+func TestCalculateDwellTimes_TravelBudget(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.MinTravel = 2.0
+	d.MaxTravel = 5.0
+
+	blocks := []analyzer.Block{
+		{Priority: 0.5, Rect: image.Rect(0, 0, 100, 100)},
+		{Priority: 0.5, Rect: image.Rect(1100, 600, 1200, 700)},
+		{Priority: 0.5, Rect: image.Rect(0, 600, 100, 700)},
+	}
+
+	totalDuration := 8.0
+	timings := d.calculateDwellTimes(totalDuration, 0.5, 1.0, blocks)
+
+	blockBudget := totalDuration - timings.Intro - timings.Outro - timings.Fade
+	if travel := sum(timings.Travel); travel > blockBudget*maxTravelShare+0.001 {
+		t.Errorf("travel %f exceeds %.0f%% of block budget %f", travel, maxTravelShare*100, blockBudget)
+	}
+
+	total := timings.Intro + timings.Outro + timings.Fade + sum(timings.Travel) + sum(timings.Dwell) + timings.Hold
+	if math.Abs(total-totalDuration) > 0.001 {
+		t.Errorf("expected total %f, got %f", totalDuration, total)
+	}
+}
+
+func TestGenerateScenario_TravelAndHoldKeyframes(t *testing.T) {
+	d := NewDirector(1280, 720)
+
+	blocks := []analyzer.Block{
+		{Priority: 0.5, Type: analyzer.BlockTypeText, Rect: image.Rect(50, 50, 400, 150)},
+		{Priority: 0.5, Type: analyzer.BlockTypeText, Rect: image.Rect(800, 500, 1200, 650)},
+	}
+
+	scenario, err := d.GenerateScenario(blocks, "test.png", 12.0, 0.5, 1.0)
+	if err != nil {
+		t.Fatalf("GenerateScenario failed: %v", err)
+	}
+	keyframes := scenario.Slides[0].Keyframes
+
+	for i := 1; i < len(keyframes); i++ {
+		if keyframes[i].Time < keyframes[i-1].Time {
+			t.Fatalf("keyframes out of order at %d: %f < %f", i, keyframes[i].Time, keyframes[i-1].Time)
+		}
+	}
+
+	if last := keyframes[len(keyframes)-1].Time; math.Abs(last-12.0) > 0.001 {
+		t.Errorf("expected last keyframe at slide end 12.0, got %f", last)
+	}
+
+	// First block: arrival and hold share the same framing
+	var arrival, hold *Keyframe
+	for i := range keyframes {
+		switch keyframes[i].Focus {
+		case "region_1":
+			arrival = &keyframes[i]
+		case "region_1_hold":
+			hold = &keyframes[i]
+		}
+	}
+	if arrival == nil || hold == nil {
+		t.Fatalf("expected region_1 and region_1_hold keyframes")
+	}
+	if arrival.Rect != hold.Rect || arrival.Zoom != hold.Zoom {
+		t.Errorf("hold keyframe should keep the arrival framing")
+	}
+	if hold.Time <= arrival.Time {
+		t.Errorf("hold should end after arrival, got %f <= %f", hold.Time, arrival.Time)
+	}
+}
+
+func TestCalculateDwellTimes_ResidualKeepsTravel(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.MaxDwell = 1.0 // Every block is clamped: most of the budget is left over
+
+	blocks := []analyzer.Block{
+		{Priority: 0.5, Rect: image.Rect(0, 0, 100, 100)},
+		{Priority: 0.5, Rect: image.Rect(1100, 600, 1200, 700)},
+	}
+	nominal := d.calculateTravelTimes(blocks)
+
+	totalDuration := 20.0
+	timings := d.calculateDwellTimes(totalDuration, 0.5, 1.0, blocks)
+	for i, travel := range timings.Travel {
+		if math.Abs(travel-nominal[i]) > 0.001 || travel > d.MaxTravel {
+			t.Errorf("flight %d: expected nominal %f (max %f), got %f", i, nominal[i], d.MaxTravel, travel)
+		}
+	}
+	if timings.Dwell[0] != d.MaxDwell || timings.Dwell[1] != d.MaxDwell || timings.Hold <= 0 {
+		t.Errorf("expected the leftover held on the last block, got dwell %v, hold %f", timings.Dwell, timings.Hold)
+	}
+
+	total := timings.Intro + timings.Outro + timings.Fade + sum(timings.Travel) + sum(timings.Dwell) + timings.Hold
+	if math.Abs(total-totalDuration) > 0.001 {
+		t.Errorf("expected total %f, got %f", totalDuration, total)
+	}
+}
@@ -0,0 +1,151 @@
+package director
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Easing names (Keyframe.Easing); a cubic-bezier is written as "cubic-bezier(x1, y1, x2, y2)"
+const (
+	EasingLinear = "linear"
+	EasingIn     = "ease-in"
+	EasingOut    = "ease-out"
+	EasingInOut  = "ease-in-out"
+	EasingBezier = "cubic-bezier"
+
+	DefaultEasing = EasingLinear // Used when a keyframe does not name one, as zoompan always interpolated
+)
+
+// EasingNames lists the named easings
+var EasingNames = []string{EasingLinear, EasingIn, EasingOut, EasingInOut}
+
+// Easing maps the linear progress between two keyframes (0..1) to the eased progress
+type Easing struct {
+	Kind           string  // One of EasingNames or EasingBezier
+	X1, Y1, X2, Y2 float64 // Control points of a cubic-bezier
+}
+
+// ParseEasing parses an easing name or a cubic-bezier; an empty string is the default easing
+func ParseEasing(s string) (Easing, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Easing{Kind: DefaultEasing}, nil
+	}
+	for _, name := range EasingNames {
+		if s == name {
+			return Easing{Kind: name}, nil
+		}
+	}
+
+	args, ok := strings.CutPrefix(s, EasingBezier+"(")
+	if !ok || !strings.HasSuffix(args, ")") {
+		return Easing{}, fmt.Errorf("unknown easing %q", s)
+	}
+	parts := strings.Split(strings.TrimSuffix(args, ")"), ",")
+	if len(parts) != 4 {
+		return Easing{}, fmt.Errorf("cubic-bezier needs 4 values, got %d", len(parts))
+	}
+	var v [4]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return Easing{}, fmt.Errorf("invalid cubic-bezier value %q", strings.TrimSpace(part))
+		}
+		v[i] = f
+	}
+	if v[0] < 0 || v[0] > 1 || v[2] < 0 || v[2] > 1 {
+		return Easing{}, fmt.Errorf("cubic-bezier x values must be in [0, 1], got %g and %g", v[0], v[2])
+	}
+	return Easing{Kind: EasingBezier, X1: v[0], Y1: v[1], X2: v[2], Y2: v[3]}, nil
+}
+
+// EasingCurve returns the easing of the keyframe into the next one.
+// Invalid values (reported by Lint) fall back to the default easing.
+func (kf Keyframe) EasingCurve() Easing {
+	e, err := ParseEasing(kf.Easing)
+	if err != nil {
+		return Easing{Kind: DefaultEasing}
+	}
+	return e
+}
+
+// IsLinear reports whether the easing keeps the progress as is
+func (e Easing) IsLinear() bool {
+	return e.Kind == EasingLinear || (e.Kind == EasingBezier && e.X1 == e.Y1 && e.X2 == e.Y2)
+}
+
+// Apply returns the eased progress for the linear progress t (clamped to 0..1)
+func (e Easing) Apply(t float64) float64 {
+	t = math.Max(0, math.Min(1, t))
+	switch e.Kind {
+	case EasingLinear:
+		return t
+	case EasingIn:
+		return t * t * t
+	case EasingOut:
+		u := 1 - t
+		return 1 - u*u*u
+	case EasingBezier:
+		return e.bezier(t)
+	}
+	// ease-in-out
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	u := -2*t + 2
+	return 1 - u*u*u/2
+}
+
+// bezier solves x(s) = t for the curve parameter s and returns y(s)
+func (e Easing) bezier(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	coord := func(p1, p2, s float64) float64 {
+		u := 1 - s
+		return 3*u*u*s*p1 + 3*u*s*s*p2 + s*s*s
+	}
+	slope := func(p1, p2, s float64) float64 {
+		u := 1 - s
+		return 3*u*u*p1 + 6*u*s*(p2-p1) + 3*s*s*(1-p2)
+	}
+
+	// Newton steps from s = t, bisection when the slope is too flat
+	s := t
+	for i := 0; i < 8; i++ {
+		dx := coord(e.X1, e.X2, s) - t
+		if math.Abs(dx) < 1e-7 {
+			return coord(e.Y1, e.Y2, s)
+		}
+		d := slope(e.X1, e.X2, s)
+		if math.Abs(d) < 1e-6 {
+			break
+		}
+		s = math.Max(0, math.Min(1, s-dx/d))
+	}
+
+	lo, hi := 0.0, 1.0
+	s = t
+	for i := 0; i < 50; i++ {
+		x := coord(e.X1, e.X2, s)
+		if math.Abs(x-t) < 1e-7 {
+			break
+		}
+		if x < t {
+			lo = s
+		} else {
+			hi = s
+		}
+		s = (lo + hi) / 2
+	}
+	return coord(e.Y1, e.Y2, s)
+}
+
+func (e Easing) String() string {
+	if e.Kind == EasingBezier {
+		return fmt.Sprintf("%s(%g, %g, %g, %g)", EasingBezier, e.X1, e.Y1, e.X2, e.Y2)
+	}
+	return e.Kind
+}
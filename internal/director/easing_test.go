@@ -0,0 +1,84 @@
+package director
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseEasing(t *testing.T) {
+	tests := []struct {
+		in      string
+		kind    string
+		wantErr bool
+	}{
+		{"", DefaultEasing, false},
+		{"linear", EasingLinear, false},
+		{"ease-out", EasingOut, false},
+		{"cubic-bezier(0.25, 0.1, 0.25, 1)", EasingBezier, false},
+		{"cubic-bezier(0.3,-0.5,0.7,1.5)", EasingBezier, false}, // y may overshoot
+		{"bounce", "", true},
+		{"cubic-bezier(0.1, 0.2, 0.3)", "", true},
+		{"cubic-bezier(1.2, 0, 0.5, 1)", "", true},
+		{"cubic-bezier(a, 0, 0.5, 1)", "", true},
+	}
+	for _, tt := range tests {
+		e, err := ParseEasing(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: unexpected error state: %v", tt.in, err)
+			continue
+		}
+		if err == nil && e.Kind != tt.kind {
+			t.Errorf("%q: expected %s, got %s", tt.in, tt.kind, e.Kind)
+		}
+	}
+
+	e, _ := ParseEasing("cubic-bezier(0.25, 0.1, 0.25, 1)")
+	if e.String() != "cubic-bezier(0.25, 0.1, 0.25, 1)" {
+		t.Errorf("unexpected round trip: %s", e)
+	}
+}
+
+func TestEasingApply(t *testing.T) {
+	parse := func(s string) Easing {
+		e, err := ParseEasing(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	tests := []struct {
+		easing string
+		t      float64
+		want   float64
+	}{
+		{"linear", 0.3, 0.3},
+		{"ease-in", 0.5, 0.125},
+		{"ease-out", 0.5, 0.875},
+		{"ease-in-out", 0.25, 0.0625},
+		{"ease-in-out", 0.5, 0.5},
+		{"ease-in-out", 0.75, 0.9375},
+		{"cubic-bezier(0, 0, 1, 1)", 0.37, 0.37},
+		{"cubic-bezier(0.42, 0, 0.58, 1)", 0.5, 0.5},      // Symmetric curve
+		{"cubic-bezier(0.25, 0.1, 0.25, 1)", 0.5, 0.8024}, // CSS "ease"
+	}
+	for _, tt := range tests {
+		if got := parse(tt.easing).Apply(tt.t); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("%s at %.2f: expected %.4f, got %.4f", tt.easing, tt.t, tt.want, got)
+		}
+	}
+
+	// Every easing starts at 0, ends at 1 and clamps the progress
+	for _, name := range append(EasingNames, "cubic-bezier(0.7, -0.4, 0.3, 1.4)") {
+		e := parse(name)
+		if e.Apply(0) != 0 || math.Abs(e.Apply(1)-1) > 1e-9 || e.Apply(-1) != 0 || math.Abs(e.Apply(2)-1) > 1e-9 {
+			t.Errorf("%s: bad endpoints", name)
+		}
+	}
+
+	if !parse("cubic-bezier(0.2, 0.2, 0.8, 0.8)").IsLinear() || parse("ease-in").IsLinear() {
+		t.Error("unexpected IsLinear result")
+	}
+	if (Keyframe{Easing: "wobble"}).EasingCurve().Kind != DefaultEasing {
+		t.Error("an invalid easing should fall back to the default")
+	}
+}
@@ -0,0 +1,319 @@
+package director
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/ivlev/pdf2video/internal/config"
+	"github.com/ivlev/pdf2video/internal/system"
+	"gopkg.in/yaml.v3"
+)
+
+// Lint issue severities
+const (
+	SeverityError   = "error"   // The scenario renders wrong or fails
+	SeverityWarning = "warning" // Suspicious but renderable
+)
+
+// lintEpsilon absorbs float noise in generated timings
+const lintEpsilon = 1e-6
+
+// Issue is a single problem found in a scenario. Slide and Keyframe are 1-based
+// positions in the file, 0 when the issue is not tied to a slide or keyframe.
+type Issue struct {
+	Severity string `json:"severity"`
+	Slide    int    `json:"slide,omitempty"`
+	Keyframe int    `json:"keyframe,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// Location formats the issue position, e.g. "slides[2].keyframes[3].zoom"
+func (i Issue) Location() string {
+	var parts []string
+	if i.Slide > 0 {
+		parts = append(parts, fmt.Sprintf("slides[%d]", i.Slide))
+	}
+	if i.Keyframe > 0 {
+		parts = append(parts, fmt.Sprintf("keyframes[%d]", i.Keyframe))
+	}
+	if i.Field != "" {
+		parts = append(parts, i.Field)
+	}
+	if len(parts) == 0 {
+		return "scenario"
+	}
+	return strings.Join(parts, ".")
+}
+
+func (i Issue) String() string {
+	s := fmt.Sprintf("%s: %s: %s", i.Severity, i.Location(), i.Message)
+	if i.Fix != "" {
+		s += " (fix: " + i.Fix + ")"
+	}
+	return s
+}
+
+type issueList []Issue
+
+func (l *issueList) add(severity string, slide, keyframe int, field, fix, format string, args ...interface{}) {
+	*l = append(*l, Issue{
+		Severity: severity,
+		Slide:    slide,
+		Keyframe: keyframe,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+		Fix:      fix,
+	})
+}
+
+// HasErrors reports whether any issue has the error severity
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// LintFile reads a scenario file and reports every problem in it.
+// Unknown YAML keys (typos) are reported as errors instead of being silently dropped.
+// The returned error is only set when the file cannot be read.
+func LintFile(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenario Scenario
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&scenario); err != nil && !errors.Is(err, io.EOF) {
+		return []Issue{{
+			Severity: SeverityError,
+			Message:  err.Error(),
+			Fix:      "fix the YAML syntax or the field name",
+		}}, nil
+	}
+
+	return scenario.Lint(), nil
+}
+
+// Lint checks the scenario for mistakes that would only show up after rendering:
+// keyframe order and timing, degenerate rectangles, zoom below 1:1, unknown settings.
+func (s *Scenario) Lint() []Issue {
+	var issues issueList
+	add := issues.add
+
+	if err := s.checkVersion(); err != nil {
+		add(SeverityError, 0, 0, "version", fmt.Sprintf("set version to %q", ScenarioVersion), "%v", err)
+	} else if s.Version == "" {
+		add(SeverityWarning, 0, 0, "version", fmt.Sprintf("set version to %q (page-normalized) or %q (pixels)", ScenarioVersion, LegacyScenarioVersion),
+			"version is missing, the scenario is read as %s (viewport pixels)", LegacyScenarioVersion)
+	}
+
+	if cam := s.Camera; cam != nil {
+		if cam.Path != "" && cam.Path != PathLinear && cam.Path != PathSpline {
+			add(SeverityError, 0, 0, "camera.path", fmt.Sprintf("use %q or %q", PathLinear, PathSpline), "unknown camera path %q", cam.Path)
+		}
+		if cam.Tension < 0 || cam.Tension > 1 {
+			add(SeverityWarning, 0, 0, "camera.tension", "use a value in [0, 1]", "tension %.2f is clamped to [0, 1]", cam.Tension)
+		}
+		if in := cam.Inertia; in != nil {
+			if in.Mass < 0 {
+				add(SeverityError, 0, 0, "camera.inertia.mass", "use a positive mass or 0 for the default", "negative mass %.2f", in.Mass)
+			}
+			if in.Damping < 0 || in.Damping > 1 {
+				add(SeverityError, 0, 0, "camera.inertia.damping", "use a value in (0, 1]", "damping %.2f is out of range", in.Damping)
+			}
+		}
+	}
+
+	if len(s.Slides) == 0 {
+		add(SeverityError, 0, 0, "slides", "add at least one slide", "scenario has no slides")
+	}
+
+	ids := make(map[int]int)
+	for i, slide := range s.Slides {
+		n := i + 1
+		if prev, ok := ids[slide.ID]; ok {
+			add(SeverityWarning, n, 0, "id", fmt.Sprintf("use id %d", n), "id %d is already used by slide %d", slide.ID, prev)
+		} else {
+			ids[slide.ID] = n
+		}
+		if ref := ParseInput(slide.Input); ref.Page == 0 && strings.Contains(ref.Path, "#") {
+			add(SeverityWarning, n, 0, "input", "use deck.pdf#7, image.jpg or #7", "input %q has no page number after #, it is read as a file name", slide.Input)
+		}
+		if slide.Duration <= 0 {
+			add(SeverityError, n, 0, "duration", "set a positive duration in seconds", "duration %.2f must be positive", slide.Duration)
+		}
+		if slide.Ordering != "" && !isOrdering(slide.Ordering) {
+			add(SeverityWarning, n, 0, "ordering", "use one of: "+strings.Join(SupportedOrderings, ", "), "unknown ordering %q", slide.Ordering)
+		}
+		s.lintOverrides(n, slide, &issues)
+		if len(slide.Keyframes) == 0 && slide.CameraEffect() == SlideEffectKeyframes {
+			add(SeverityWarning, n, 0, "keyframes", "add a full_view keyframe at time 0 or set effect: static", "slide has no keyframes, the page is shown static")
+		}
+
+		s.lintKeyframes(n, slide, &issues)
+	}
+
+	return issues
+}
+
+// lintOverrides checks the per-slide transition and camera settings
+func (s *Scenario) lintOverrides(n int, slide Slide, issues *issueList) {
+	add := issues.add
+	if slide.Transition != "" && !inList(config.SupportedTransitions, slide.Transition) {
+		add(SeverityError, n, 0, "transition", "use one of: "+strings.Join(config.SupportedTransitions, ", "), "unknown transition %q", slide.Transition)
+	}
+	if slide.Fade != nil {
+		if *slide.Fade < 0 {
+			add(SeverityError, n, 0, "fade", "use 0 for a hard cut or a positive duration", "fade %.2f is negative", *slide.Fade)
+		} else if slide.Duration > 0 && *slide.Fade >= slide.Duration {
+			add(SeverityError, n, 0, "fade", fmt.Sprintf("use a fade shorter than %.2f", slide.Duration),
+				"fade %.2f is not shorter than the slide duration %.2f", *slide.Fade, slide.Duration)
+		} else if prev := n - 2; prev >= 0 && slide.Transition != "none" && *slide.Fade > 0 &&
+			s.Slides[prev].Duration > 0 && *slide.Fade >= s.Slides[prev].Duration {
+			// The transition into the slide overlaps the end of the previous one
+			add(SeverityError, n, 0, "fade", fmt.Sprintf("use a fade shorter than %.2f", s.Slides[prev].Duration),
+				"fade %.2f is not shorter than the previous slide duration %.2f", *slide.Fade, s.Slides[prev].Duration)
+		}
+	}
+	if slide.Outro != nil && *slide.Outro < 0 {
+		add(SeverityError, n, 0, "outro", "use 0 to skip the return to 1:1 or a positive duration", "outro %.2f is negative", *slide.Outro)
+	}
+	if slide.Effect != "" && !inList(SlideEffects, slide.Effect) {
+		add(SeverityError, n, 0, "effect", "use one of: "+strings.Join(SlideEffects, ", "), "unknown effect %q", slide.Effect)
+	}
+	if slide.ZoomMode != "" {
+		if !inList(config.SupportedZoomModes, slide.ZoomMode) {
+			add(SeverityError, n, 0, "zoom_mode", "use one of: "+strings.Join(config.SupportedZoomModes, ", "), "unknown zoom mode %q", slide.ZoomMode)
+		} else if slide.CameraEffect() != SlideEffectZoom {
+			add(SeverityWarning, n, 0, "zoom_mode", "remove zoom_mode or set effect: zoom", "zoom_mode is ignored by the %s effect", slide.CameraEffect())
+		}
+	}
+}
+
+func inList(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// lintKeyframes checks the keyframes of one slide
+func (s *Scenario) lintKeyframes(n int, slide Slide, issues *issueList) {
+	add := issues.add
+	for k, kf := range slide.Keyframes {
+		m := k + 1
+		if math.IsNaN(kf.Time) || kf.Time < 0 {
+			add(SeverityError, n, m, "time", "use a time in [0, duration]", "time %.2f is negative", kf.Time)
+		}
+		if slide.Duration > 0 && kf.Time > slide.Duration+lintEpsilon {
+			add(SeverityError, n, m, "time", fmt.Sprintf("set time to at most %.2f or increase the slide duration", slide.Duration),
+				"time %.2f exceeds the slide duration %.2f", kf.Time, slide.Duration)
+		}
+		if k > 0 && kf.Time < slide.Keyframes[k-1].Time {
+			add(SeverityError, n, m, "time", "sort the keyframes by time",
+				"time %.2f is before the previous keyframe (%.2f)", kf.Time, slide.Keyframes[k-1].Time)
+		}
+
+		if kf.Emphasis != "" && !inList(config.SupportedEmphasisModes, kf.Emphasis) {
+			add(SeverityError, n, m, "emphasis", "use one of: "+strings.Join(config.SupportedEmphasisModes, ", "), "unknown emphasis %q", kf.Emphasis)
+		}
+		if math.IsNaN(kf.Rotation) || math.Abs(kf.Rotation) > MaxRotation {
+			add(SeverityError, n, m, "rotation", fmt.Sprintf("use an angle in [-%g, %g] degrees", MaxRotation, MaxRotation), "rotation %.1f is out of range", kf.Rotation)
+		}
+		if _, err := ParseEasing(kf.Easing); err != nil {
+			add(SeverityError, n, m, "easing", "use one of: "+strings.Join(EasingNames, ", ")+" or cubic-bezier(x1, y1, x2, y2)", "%v", err)
+		}
+		lintAnnotations(n, m, slide, kf, issues)
+
+		if math.IsNaN(kf.Zoom) || kf.Zoom < 1 {
+			add(SeverityError, n, m, "zoom", "use zoom 1.0 for the full view or more to zoom in", "zoom %.2f is below 1:1", kf.Zoom)
+		}
+
+		r := kf.Rect
+		if !(r.W > 0) || !(r.H > 0) {
+			add(SeverityError, n, m, "rect", "give the rectangle a positive width and height", "zero-size rectangle %.3gx%.3g", r.W, r.H)
+			continue
+		}
+		// The camera follows the rectangle center; page-normalized centers must be on the page
+		if !s.IsLegacy() {
+			cx, cy := r.X+r.W/2, r.Y+r.H/2
+			if cx < -lintEpsilon || cx > 1+lintEpsilon || cy < -lintEpsilon || cy > 1+lintEpsilon {
+				add(SeverityWarning, n, m, "rect", "page coordinates are fractions of the page in [0, 1]",
+					"rectangle center (%.2f, %.2f) is outside the page", cx, cy)
+			}
+		}
+	}
+}
+
+// lintAnnotations checks the annotations of one keyframe
+func lintAnnotations(n, m int, slide Slide, kf Keyframe, issues *issueList) {
+	add := issues.add
+	for i, a := range kf.Annotations {
+		field := func(name string) string {
+			return fmt.Sprintf("annotations[%d].%s", i+1, name)
+		}
+
+		switch a.Type {
+		case AnnotationBox, AnnotationCircle:
+			if !(a.Rect.W > 0) || !(a.Rect.H > 0) {
+				add(SeverityError, n, m, field("rect"), "give the marked region a positive width and height",
+					"zero-size %s %.3gx%.3g", a.Type, a.Rect.W, a.Rect.H)
+			}
+		case AnnotationArrow:
+			if a.From == a.To {
+				add(SeverityError, n, m, field("to"), "set different from and to points", "arrow has zero length")
+			}
+		case AnnotationLabel:
+			if strings.TrimSpace(a.Text) == "" {
+				add(SeverityError, n, m, field("text"), "set the label text", "label has no text")
+			}
+			if a.Size < 0 {
+				add(SeverityError, n, m, field("size"), "use a fraction of the page height, e.g. 0.04", "size %.3g is negative", a.Size)
+			}
+			if a.Background != "" {
+				if _, err := system.ParseHexColor(a.Background); err != nil {
+					add(SeverityError, n, m, field("background"), "use #RRGGBB", "invalid color %q", a.Background)
+				}
+			}
+		default:
+			add(SeverityError, n, m, field("type"), "use one of: "+strings.Join(AnnotationTypes, ", "), "unknown annotation type %q", a.Type)
+		}
+
+		if a.Color != "" {
+			if _, err := system.ParseHexColor(a.Color); err != nil {
+				add(SeverityError, n, m, field("color"), "use #RRGGBB", "invalid color %q", a.Color)
+			}
+		}
+		if a.Stroke < 0 {
+			add(SeverityError, n, m, field("stroke"), "use a positive width in pixels or 0 for the default", "stroke %.2f is negative", a.Stroke)
+		}
+		if a.Fade != nil && *a.Fade < 0 {
+			add(SeverityError, n, m, field("fade"), "use 0 to pop in and out or a positive duration", "fade %.2f is negative", *a.Fade)
+		}
+		if a.Appear < 0 {
+			add(SeverityError, n, m, field("appear"), "times are seconds after the keyframe time", "appear %.2f is negative", a.Appear)
+		}
+		if a.Disappear != 0 && a.Disappear <= a.Appear {
+			add(SeverityError, n, m, field("disappear"), fmt.Sprintf("use a time after appear (%.2f) or 0 to keep it until the slide ends", a.Appear),
+				"disappear %.2f is not after appear %.2f", a.Disappear, a.Appear)
+		}
+		if slide.Duration > 0 && kf.Time+a.Appear >= slide.Duration {
+			add(SeverityWarning, n, m, field("appear"), "reduce appear or attach the annotation to an earlier keyframe",
+				"annotation appears at %.2f, after the slide ends (%.2f)", kf.Time+a.Appear, slide.Duration)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package director
+
+import (
+	"encoding/json"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+func findIssue(issues []Issue, slide, keyframe int, field string) *Issue {
+	for i := range issues {
+		if issues[i].Slide == slide && issues[i].Keyframe == keyframe && issues[i].Field == field {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+func TestLint_ReportsEveryProblem(t *testing.T) {
+	s := &Scenario{
+		Version: ScenarioVersion,
+		Camera:  &CameraSettings{Path: "curvy"},
+		Slides: []Slide{{
+			ID:       1,
+			Duration: 5,
+			Keyframes: []Keyframe{
+				{Time: 0, Zoom: 1, Rect: Rectangle{W: 1, H: 1}},
+				{Time: 3, Zoom: 0.5, Rect: Rectangle{X: 0.2, Y: 0.2, W: 0.1, H: 0.1}},
+				{Time: 2, Zoom: 2, Rect: Rectangle{X: 0.2, Y: 0.2, W: 0, H: 0.1}},
+				{Time: 6, Zoom: 2, Rect: Rectangle{X: 0.2, Y: 0.2, W: 0.1, H: 0.1}},
+				{Time: -1, Zoom: 2, Rect: Rectangle{X: 1.4, Y: 0.2, W: 0.2, H: 0.1}},
+			},
+		}},
+	}
+
+	issues := s.Lint()
+	for _, want := range []struct {
+		keyframe int
+		field    string
+	}{
+		{0, "camera.path"},
+		{2, "zoom"},
+		{3, "time"},
+		{3, "rect"},
+		{4, "time"},
+		{5, "time"},
+		{5, "rect"},
+	} {
+		slide := 1
+		if want.keyframe == 0 {
+			slide = 0
+		}
+		issue := findIssue(issues, slide, want.keyframe, want.field)
+		if issue == nil {
+			t.Errorf("expected an issue at %s, got %v", Issue{Slide: slide, Keyframe: want.keyframe, Field: want.field}.Location(), issues)
+			continue
+		}
+		if issue.Fix == "" {
+			t.Errorf("%s: expected a suggested fix", issue.Location())
+		}
+	}
+	if !HasErrors(issues) {
+		t.Error("expected errors")
+	}
+}
+
+func TestLint_GeneratedScenarioIsClean(t *testing.T) {
+	d := NewDirector(1280, 720)
+	blocks := []analyzer.Block{
+		{Rect: image.Rect(100, 100, 400, 200), Type: analyzer.BlockTypeText},
+		{Rect: image.Rect(700, 400, 1100, 650), Type: analyzer.BlockTypeImage},
+	}
+	s, err := d.GenerateScenario(blocks, "slide_1.png", 10, 0.5, 1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := s.Lint(); len(issues) > 0 {
+		t.Errorf("expected no issues in a generated scenario, got %v", issues)
+	}
+}
+
+func TestLintFile_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	data := "version: \"2.0\"\nslides:\n  - id: 1\n    duraton: 5\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := LintFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !HasErrors(issues) {
+		t.Errorf("expected the misspelled field to be reported, got %v", issues)
+	}
+}
+
+func TestScenarioSchema(t *testing.T) {
+	data, err := ScenarioSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	defs, ok := schema["$defs"].(map[string]interface{})
+	if !ok {
+		t.Fatal("schema has no $defs")
+	}
+	for _, name := range []string{"slide", "keyframe", "rectangle", "camera"} {
+		if _, ok := defs[name]; !ok {
+			t.Errorf("schema misses the %s definition", name)
+		}
+	}
+}
+
+func TestLint_SlideOverrides(t *testing.T) {
+	negative, long, medium := -0.5, 6.0, 3.0
+	s := &Scenario{
+		Version: ScenarioVersion,
+		Slides: []Slide{
+			{ID: 1, Duration: 5, Transition: "swirl", Fade: &long, Effect: SlideEffectStatic},
+			{ID: 2, Duration: 5, Outro: &negative, Effect: "shake", ZoomMode: "sideways"},
+			{ID: 3, Duration: 5, Transition: "none", ZoomMode: "top-left"},
+			{ID: 4, Duration: 2},
+			{ID: 5, Duration: 8, Fade: &medium}, // Longer than the previous slide it fades out of
+			{ID: 6, Duration: 2},
+			{ID: 7, Duration: 5, Transition: "none", Fade: &medium}, // A hard cut does not overlap
+		},
+	}
+
+	issues := s.Lint()
+	for _, want := range []struct {
+		slide int
+		field string
+	}{
+		{1, "transition"}, {1, "fade"}, {2, "outro"}, {2, "effect"}, {2, "zoom_mode"}, {5, "fade"},
+	} {
+		if findIssue(issues, want.slide, 0, want.field) == nil {
+			t.Errorf("expected an issue at slides[%d].%s, got %v", want.slide, want.field, issues)
+		}
+	}
+	if issue := findIssue(issues, 3, 0, "zoom_mode"); issue != nil {
+		t.Errorf("zoom_mode alone is a valid zoom slide, got %v", issue)
+	}
+	if issue := findIssue(issues, 1, 0, "keyframes"); issue != nil {
+		t.Errorf("a static slide needs no keyframes, got %v", issue)
+	}
+	if issue := findIssue(issues, 7, 0, "fade"); issue != nil {
+		t.Errorf("a hard cut does not overlap the previous slide, got %v", issue)
+	}
+}
+
+func TestLint_Annotations(t *testing.T) {
+	s := &Scenario{
+		Version: ScenarioVersion,
+		Slides: []Slide{{
+			ID:       1,
+			Duration: 5,
+			Keyframes: []Keyframe{{
+				Time: 1, Zoom: 1, Rect: Rectangle{W: 1, H: 1},
+				Annotations: []Annotation{
+					{Type: AnnotationBox, Rect: Rectangle{X: 0.1, Y: 0.1, W: 0.2, H: 0.1}, Color: "#00FF00", Appear: 0.5, Disappear: 2},
+					{Type: "star"},
+					{Type: AnnotationCircle, Color: "red"},
+					{Type: AnnotationArrow, From: Point{X: 0.5, Y: 0.5}, To: Point{X: 0.5, Y: 0.5}},
+					{Type: AnnotationLabel, At: Point{X: 0.1, Y: 0.1}, Appear: 2, Disappear: 1},
+					{Type: AnnotationLabel, Text: "late", Appear: 4.5},
+				},
+			}},
+		}},
+	}
+
+	issues := s.Lint()
+	for _, want := range []struct {
+		field    string
+		severity string
+	}{
+		{"annotations[2].type", SeverityError},
+		{"annotations[3].rect", SeverityError},
+		{"annotations[3].color", SeverityError},
+		{"annotations[4].to", SeverityError},
+		{"annotations[5].text", SeverityError},
+		{"annotations[5].disappear", SeverityError},
+		{"annotations[6].appear", SeverityWarning},
+	} {
+		issue := findIssue(issues, 1, 1, want.field)
+		if issue == nil {
+			t.Errorf("expected an issue at %s, got %v", want.field, issues)
+			continue
+		}
+		if issue.Severity != want.severity {
+			t.Errorf("%s: expected %s, got %s", want.field, want.severity, issue.Severity)
+		}
+	}
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.Field, "annotations[1].") {
+			t.Errorf("valid annotation reported: %s", issue)
+		}
+	}
+}
+
+func TestLint_Emphasis(t *testing.T) {
+	s := &Scenario{
+		Version: ScenarioVersion,
+		Slides: []Slide{{ID: 1, Duration: 5, Keyframes: []Keyframe{
+			{Time: 0, Zoom: 1, Rect: Rectangle{W: 1, H: 1}, Emphasis: EmphasisNone},
+			{Time: 2, Zoom: 1, Rect: Rectangle{X: 0.2, Y: 0.2, W: 0.3, H: 0.3}, Emphasis: "glow"},
+			{Time: 4, Zoom: 1, Rect: Rectangle{X: 0.2, Y: 0.2, W: 0.3, H: 0.3}, Emphasis: EmphasisSpotlight},
+		}}},
+	}
+	issues := s.Lint()
+	if findIssue(issues, 1, 2, "emphasis") == nil {
+		t.Errorf("expected an unknown emphasis error, got %v", issues)
+	}
+	if findIssue(issues, 1, 1, "emphasis") != nil || findIssue(issues, 1, 3, "emphasis") != nil {
+		t.Errorf("valid emphasis modes reported: %v", issues)
+	}
+}
+
+func TestLint_Easing(t *testing.T) {
+	s := &Scenario{
+		Version: ScenarioVersion,
+		Slides: []Slide{{ID: 1, Duration: 5, Keyframes: []Keyframe{
+			{Time: 0, Zoom: 1, Rect: Rectangle{W: 1, H: 1}, Easing: EasingOut},
+			{Time: 2, Zoom: 1, Rect: Rectangle{W: 1, H: 1}, Easing: "cubic-bezier(0.2, 0.8)"},
+			{Time: 4, Zoom: 1, Rect: Rectangle{W: 1, H: 1}, Easing: "cubic-bezier(0.2, 0, 0.2, 1)"},
+		}}},
+	}
+	issues := s.Lint()
+	if findIssue(issues, 1, 2, "easing") == nil {
+		t.Errorf("expected an invalid easing error, got %v", issues)
+	}
+	if findIssue(issues, 1, 1, "easing") != nil || findIssue(issues, 1, 3, "easing") != nil {
+		t.Errorf("valid easings reported: %v", issues)
+	}
+}
+
+func TestLint_Rotation(t *testing.T) {
+	s := &Scenario{
+		Version: ScenarioVersion,
+		Slides: []Slide{{ID: 1, Duration: 5, Keyframes: []Keyframe{
+			{Time: 0, Zoom: 1, Rect: Rectangle{W: 1, H: 1}, Rotation: -3},
+			{Time: 2, Zoom: 1, Rect: Rectangle{W: 1, H: 1}, Rotation: 120},
+		}}},
+	}
+	issues := s.Lint()
+	if findIssue(issues, 1, 2, "rotation") == nil {
+		t.Errorf("expected a rotation range error, got %v", issues)
+	}
+	if findIssue(issues, 1, 1, "rotation") != nil {
+		t.Errorf("valid rotation reported: %v", issues)
+	}
+}
@@ -62,11 +62,7 @@ func (o *TrajectoryOptimizer) findNext(blocks []analyzer.Block, from image.Point
 	}
 
 	for i, b := range blocks {
-		// Norm priority (0.0 - 1.0) - assume analyzer already provides normalized priority
-		prio := b.Priority
-		if prio == 0 {
-			prio = b.Score // Fallback to score
-		}
+		prio := blockPriority(b)
 
 		// Norm distance (0.0 - 1.0, where 0 is far, 1 is close)
 		dist := distance(from, calculateCenter(b.Rect))
@@ -83,6 +79,103 @@ func (o *TrajectoryOptimizer) findNext(blocks []analyzer.Block, from image.Point
 	return bestIdx
 }
 
+// maxRoutePasses bounds the 2-opt improvement passes
+const maxRoutePasses = 50
+
+// Route finds a visiting order for the whole page: it starts from the greedy order and
+// improves it with 2-opt segment reversals while the route cost goes down.
+// The cost (routeCost) trades total travel (in page diagonals) against showing important
+// blocks late. The reversals are judged by cost deltas; the full cost of the result is
+// compared with the greedy order at the end, and the cheaper of the two is returned.
+func (o *TrajectoryOptimizer) Route(blocks []analyzer.Block, startPoint image.Point, diagonal float64) []analyzer.Block {
+	route := o.Optimize(blocks, startPoint)
+	if len(route) <= 2 {
+		return route
+	}
+	if diagonal <= 0 {
+		diagonal = 1
+	}
+	greedy := append([]analyzer.Block(nil), route...)
+
+	n := len(route)
+	centers := make([]image.Point, n)
+	// prefix[k] and weighted[k] are the sums of p and k*p over route[:k]
+	prefix := make([]float64, n+1)
+	weighted := make([]float64, n+1)
+	refresh := func() {
+		for k, b := range route {
+			centers[k] = calculateCenter(b.Rect)
+			p := blockPriority(b)
+			prefix[k+1] = prefix[k] + p
+			weighted[k+1] = weighted[k] + float64(k)*p
+		}
+	}
+
+	refresh()
+	for pass := 0; pass < maxRoutePasses; pass++ {
+		improved := false
+		for i := 0; i < n-1; i++ {
+			prev := startPoint
+			if i > 0 {
+				prev = centers[i-1]
+			}
+			for j := i + 1; j < n; j++ {
+				// Only the two edges around the segment change, the inner ones are walked backwards
+				travel := distance(prev, centers[j]) - distance(prev, centers[i])
+				if j+1 < n {
+					travel += distance(centers[i], centers[j+1]) - distance(centers[j], centers[j+1])
+				}
+				// A block at position k moves to i+j-k
+				sum := prefix[j+1] - prefix[i]
+				sumK := weighted[j+1] - weighted[i]
+				lateness := (float64(i+j)*sum - 2*sumK) / float64(n-1)
+
+				if delta := o.DistanceWeight*travel/diagonal + o.PriorityWeight*lateness; delta < -1e-9 {
+					reverseBlocks(route[i : j+1])
+					refresh()
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	if o.routeCost(route, startPoint, diagonal) > o.routeCost(greedy, startPoint, diagonal) {
+		return greedy
+	}
+	return route
+}
+
+// routeCost is the weighted sum of the path length and of the priority-weighted lateness
+func (o *TrajectoryOptimizer) routeCost(route []analyzer.Block, startPoint image.Point, diagonal float64) float64 {
+	travel := 0.0
+	lateness := 0.0
+	pos := startPoint
+	for k, b := range route {
+		center := calculateCenter(b.Rect)
+		travel += distance(pos, center) / diagonal
+		pos = center
+		lateness += blockPriority(b) * float64(k) / float64(len(route)-1)
+	}
+	return o.DistanceWeight*travel + o.PriorityWeight*lateness
+}
+
+// blockPriority is the normalized priority (0.0 - 1.0) provided by the analyzer
+func blockPriority(b analyzer.Block) float64 {
+	if b.Priority == 0 {
+		return b.Score // Fallback to score
+	}
+	return b.Priority
+}
+
+func reverseBlocks(blocks []analyzer.Block) {
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+}
+
 func distance(p1, p2 image.Point) float64 {
 	dx := float64(p1.X - p2.X)
 	dy := float64(p1.Y - p2.Y)
@@ -0,0 +1,53 @@
+package director
+
+import (
+	"image"
+	"math"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+	"github.com/ivlev/pdf2video/internal/config"
+)
+
+// Supported block ordering strategies
+const (
+	OrderingReading  = "reading"  // Top-to-bottom, left-to-right (legacy)
+	OrderingGreedy   = "greedy"   // Next block by priority and proximity (TrajectoryOptimizer)
+	OrderingTSP      = "tsp"      // Global route: greedy start improved with 2-opt
+	OrderingDetector = "detector" // As returned by the detector (comic panels in reading order)
+)
+
+// SupportedOrderings lists the ordering strategies accepted by the Director;
+// the list lives in config, which validates -ordering against it
+var SupportedOrderings = config.SupportedOrderings
+
+// orderBlocks puts blocks in visiting order and returns the strategy that was used
+func (d *Director) orderBlocks(blocks []analyzer.Block) ([]analyzer.Block, string) {
+	optimizer := d.Optimizer
+	if optimizer == nil {
+		optimizer = NewTrajectoryOptimizer()
+	}
+
+	// The camera starts from the full view, i.e. the middle of the page
+	start := image.Point{X: d.ViewportWidth / 2, Y: d.ViewportHeight / 2}
+
+	switch d.Ordering {
+	case OrderingGreedy:
+		return optimizer.Optimize(d.sortBlocks(blocks), start), OrderingGreedy
+	case OrderingTSP:
+		diagonal := math.Hypot(float64(d.ViewportWidth), float64(d.ViewportHeight))
+		return optimizer.Route(d.sortBlocks(blocks), start, diagonal), OrderingTSP
+	case OrderingDetector:
+		return blocks, OrderingDetector
+	}
+
+	return d.sortBlocks(blocks), OrderingReading
+}
+
+func isOrdering(mode string) bool {
+	for _, m := range SupportedOrderings {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
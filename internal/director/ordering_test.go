@@ -0,0 +1,141 @@
+package director
+
+import (
+	"image"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+func routeLength(route []analyzer.Block, start image.Point) float64 {
+	total := 0.0
+	pos := start
+	for _, b := range route {
+		c := calculateCenter(b.Rect)
+		total += distance(pos, c)
+		pos = c
+	}
+	return total
+}
+
+func TestRoute_ShorterThanGreedy(t *testing.T) {
+	o := &TrajectoryOptimizer{PriorityWeight: 0, DistanceWeight: 1}
+	start := image.Point{X: 500, Y: 500}
+
+	// Greedy takes the nearest block and ends up crossing the page twice
+	blocks := []analyzer.Block{
+		{Rect: image.Rect(440, 490, 460, 510)},
+		{Rect: image.Rect(590, 490, 610, 510)},
+		{Rect: image.Rect(0, 490, 20, 510)},
+		{Rect: image.Rect(980, 490, 1000, 510)},
+	}
+
+	greedy := routeLength(o.Optimize(blocks, start), start)
+	route := o.Route(blocks, start, 1000)
+	if len(route) != len(blocks) {
+		t.Fatalf("expected %d blocks, got %d", len(blocks), len(route))
+	}
+	if tsp := routeLength(route, start); tsp >= greedy {
+		t.Errorf("expected 2-opt route shorter than greedy %.0f, got %.0f", greedy, tsp)
+	}
+}
+
+func TestRoute_PriorityFirst(t *testing.T) {
+	o := &TrajectoryOptimizer{PriorityWeight: 1, DistanceWeight: 0}
+	blocks := []analyzer.Block{
+		{Rect: image.Rect(0, 0, 100, 100), Priority: 0.2},
+		{Rect: image.Rect(900, 900, 1000, 1000), Priority: 0.9},
+		{Rect: image.Rect(450, 450, 550, 550), Priority: 0.5},
+	}
+
+	route := o.Route(blocks, image.Point{X: 500, Y: 500}, 1414)
+	for i := 1; i < len(route); i++ {
+		if route[i].Priority > route[i-1].Priority {
+			t.Fatalf("expected blocks by descending priority, got %.1f before %.1f", route[i-1].Priority, route[i].Priority)
+		}
+	}
+}
+
+func TestRoute_NoImprovingReversalLeft(t *testing.T) {
+	o := &TrajectoryOptimizer{PriorityWeight: 0.7, DistanceWeight: 1}
+	start := image.Point{X: 0, Y: 0}
+	var blocks []analyzer.Block
+	for k := 0; k < 9; k++ {
+		x, y := (k*373)%1000, (k*617)%800
+		blocks = append(blocks, analyzer.Block{Rect: image.Rect(x, y, x+40, y+20), Priority: float64(k%4) / 4})
+	}
+
+	// The cost deltas must agree with the full cost: no reversal of the result is cheaper
+	route := o.Route(blocks, start, 1280)
+	best := o.routeCost(route, start, 1280)
+	if greedy := o.routeCost(o.Optimize(blocks, start), start, 1280); best > greedy+1e-9 {
+		t.Errorf("route cost %.4f is above the greedy order %.4f", best, greedy)
+	}
+	for i := 0; i < len(route)-1; i++ {
+		for j := i + 1; j < len(route); j++ {
+			reverseBlocks(route[i : j+1])
+			if cost := o.routeCost(route, start, 1280); cost < best-1e-6 {
+				t.Errorf("reversing %d..%d lowers the cost from %.4f to %.4f", i, j, best, cost)
+			}
+			reverseBlocks(route[i : j+1])
+		}
+	}
+}
+
+func TestGenerateScenario_Ordering(t *testing.T) {
+	blocks := []analyzer.Block{
+		{Rect: image.Rect(1000, 500, 1200, 600), Priority: 0.9, Type: analyzer.BlockTypeChart},
+		{Rect: image.Rect(100, 50, 400, 100), Priority: 0.3, Type: analyzer.BlockTypeHeader},
+		{Rect: image.Rect(100, 300, 500, 400), Priority: 0.5, Type: analyzer.BlockTypeText},
+	}
+
+	for _, ordering := range SupportedOrderings {
+		d := NewDirector(1280, 720)
+		d.Ordering = ordering
+
+		scenario, err := d.GenerateScenario(blocks, "slide_1.png", 10, 0.5, 1.0)
+		if err != nil {
+			t.Fatalf("%s: GenerateScenario failed: %v", ordering, err)
+		}
+		slide := scenario.Slides[0]
+		if slide.Ordering != ordering {
+			t.Errorf("expected slide ordering %q, got %q", ordering, slide.Ordering)
+		}
+
+		// Every block is visited exactly once
+		seen := map[Rectangle]bool{}
+		for _, kf := range slide.Keyframes {
+			if kf.Zoom > 1.0 {
+				seen[kf.Rect] = true
+			}
+		}
+		if len(seen) != len(blocks) {
+			t.Errorf("%s: expected %d distinct shots, got %d", ordering, len(blocks), len(seen))
+		}
+	}
+
+	// Reading order keeps the legacy top-to-bottom sequence
+	d := NewDirector(1280, 720)
+	ordered, used := d.orderBlocks(blocks)
+	if used != OrderingReading || ordered[0].Rect.Min.Y != 50 || ordered[2].Rect.Min.Y != 500 {
+		t.Errorf("expected reading order by default, got %s %v", used, ordered)
+	}
+
+	// Detector order is kept as is
+	d.Ordering = OrderingDetector
+	ordered, used = d.orderBlocks(blocks)
+	if used != OrderingDetector || ordered[0].Rect != blocks[0].Rect || ordered[2].Rect != blocks[2].Rect {
+		t.Errorf("expected detector order, got %s %v", used, ordered)
+	}
+}
+
+func TestSupportedOrderings(t *testing.T) {
+	for _, mode := range []string{OrderingReading, OrderingGreedy, OrderingTSP, OrderingDetector} {
+		if !isOrdering(mode) {
+			t.Errorf("ordering %q is missing from the supported list", mode)
+		}
+	}
+	if len(SupportedOrderings) != 4 {
+		t.Errorf("expected 4 supported orderings, got %v", SupportedOrderings)
+	}
+}
@@ -0,0 +1,116 @@
+package director
+
+import (
+	"math"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+// reframeBleed overscans the cover crop slightly so that pixel rounding never uncovers the bars
+const reframeBleed = 1.01
+
+// PageRect returns where a page of the given pixel size lands inside the viewport
+// when it is letterboxed (fitted without cropping and centered)
+func PageRect(pageW, pageH, viewW, viewH int) Rectangle {
+	vw, vh := float64(viewW), float64(viewH)
+	if pageW <= 0 || pageH <= 0 {
+		return Rectangle{X: 0, Y: 0, W: vw, H: vh}
+	}
+	scale := math.Min(vw/float64(pageW), vh/float64(pageH))
+	w := float64(pageW) * scale
+	h := float64(pageH) * scale
+	return Rectangle{X: (vw - w) / 2, Y: (vh - h) / 2, W: w, H: h}
+}
+
+// CoverZoom is the smallest zoom at which the camera window lies entirely on the page:
+// for a landscape page in a vertical frame it is the full-height crop
+func CoverZoom(page Rectangle, viewW, viewH int) float64 {
+	if page.W <= 0 || page.H <= 0 {
+		return 1.0
+	}
+	zoom := math.Max(float64(viewW)/page.W, float64(viewH)/page.H)
+	if zoom <= 1.0 {
+		return 1.0
+	}
+	return zoom * reframeBleed
+}
+
+// ReframeKeyframes keeps the camera inside the page: every keyframe is zoomed in to at
+// least the cover zoom and its window is shifted onto the page, so the letterbox bars are
+// never shown. Rectangles are replaced by the camera windows.
+func ReframeKeyframes(keyframes []Keyframe, viewW, viewH int, page Rectangle) []Keyframe {
+	result := make([]Keyframe, len(keyframes))
+	for i, kf := range keyframes {
+		cx := kf.Rect.X + kf.Rect.W/2
+		cy := kf.Rect.Y + kf.Rect.H/2
+		result[i] = kf
+		result[i].Rect, result[i].Zoom = reframeWindow(cx, cy, kf.Zoom, viewW, viewH, page)
+	}
+	return result
+}
+
+// reframeWindow returns the camera window around (cx, cy) at no less than the cover zoom,
+// clamped to the page
+func reframeWindow(cx, cy, zoom float64, viewW, viewH int, page Rectangle) (Rectangle, float64) {
+	zoom = math.Max(zoom, CoverZoom(page, viewW, viewH))
+	winW := float64(viewW) / zoom
+	winH := float64(viewH) / zoom
+
+	x0 := clampRange(cx-winW/2, page.X, page.X+page.W-winW)
+	y0 := clampRange(cy-winH/2, page.Y, page.Y+page.H-winH)
+
+	return Rectangle{X: x0, Y: y0, W: winW, H: winH}, zoom
+}
+
+// pageRect is the letterboxed page inside the viewport (the whole viewport if the page size is unknown)
+func (d *Director) pageRect() Rectangle {
+	return PageRect(d.PageWidth, d.PageHeight, d.ViewportWidth, d.ViewportHeight)
+}
+
+// toViewport maps blocks from page pixels (analysis render) into viewport pixels
+func (d *Director) toViewport(blocks []analyzer.Block) []analyzer.Block {
+	if d.PageWidth <= 0 || d.PageHeight <= 0 {
+		return blocks
+	}
+	page := d.pageRect()
+	sx := page.W / float64(d.PageWidth)
+	sy := page.H / float64(d.PageHeight)
+
+	mapped := make([]analyzer.Block, len(blocks))
+	for i, b := range blocks {
+		mapped[i] = b
+		mapped[i].Rect.Min.X = int(math.Round(page.X + float64(b.Rect.Min.X)*sx))
+		mapped[i].Rect.Min.Y = int(math.Round(page.Y + float64(b.Rect.Min.Y)*sy))
+		mapped[i].Rect.Max.X = int(math.Round(page.X + float64(b.Rect.Max.X)*sx))
+		mapped[i].Rect.Max.Y = int(math.Round(page.Y + float64(b.Rect.Max.Y)*sy))
+	}
+	return mapped
+}
+
+// fullView is the overview shot of the slide: the whole viewport, or the cover crop of the
+// page center when reframing
+func (d *Director) fullView() Shot {
+	full := Shot{
+		Rect: Rectangle{X: 0, Y: 0, W: float64(d.ViewportWidth), H: float64(d.ViewportHeight)},
+		Zoom: 1.0,
+		X:    float64(d.ViewportWidth) / 2,
+		Y:    float64(d.ViewportHeight) / 2,
+	}
+	if d.Reframe {
+		return d.reframeShot(full)
+	}
+	return full
+}
+
+// reframeShot moves a shot onto the page at no less than the cover zoom
+func (d *Director) reframeShot(s Shot) Shot {
+	rect, zoom := reframeWindow(s.X, s.Y, s.Zoom, d.ViewportWidth, d.ViewportHeight, d.pageRect())
+	return Shot{
+		Rect:    rect,
+		Block:   s.Block,
+		Zoom:    zoom,
+		X:       rect.X + rect.W/2,
+		Y:       rect.Y + rect.H/2,
+		Covered: s.Covered,
+	}
+}
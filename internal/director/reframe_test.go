@@ -0,0 +1,147 @@
+package director
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+func TestPageRect(t *testing.T) {
+	// Landscape page letterboxed into a vertical frame
+	page := PageRect(1920, 1080, 720, 1280)
+	if page != (Rectangle{X: 0, Y: 437.5, W: 720, H: 405}) {
+		t.Errorf("unexpected page rectangle %+v", page)
+	}
+
+	if zoom := CoverZoom(page, 720, 1280); math.Abs(zoom-1280.0/405*reframeBleed) > 1e-9 {
+		t.Errorf("expected full-height cover zoom, got %.3f", zoom)
+	}
+	if zoom := CoverZoom(PageRect(1280, 720, 1280, 720), 1280, 720); zoom != 1.0 {
+		t.Errorf("expected no cover zoom for a matching aspect, got %.3f", zoom)
+	}
+}
+
+func TestGenerateScenario_Reframe(t *testing.T) {
+	d := NewDirector(720, 1280)
+	d.PageWidth, d.PageHeight = 1920, 1080
+	d.Reframe = true
+
+	// Blocks in page pixels: left column and right column
+	blocks := []analyzer.Block{
+		{Rect: image.Rect(100, 200, 700, 500), Type: analyzer.BlockTypeText, Priority: 0.5},
+		{Rect: image.Rect(1200, 300, 1800, 900), Type: analyzer.BlockTypeChart, Priority: 0.8},
+	}
+
+	scenario, err := d.GenerateScenario(blocks, "slide_1.png", 10, 0.5, 1.0)
+	if err != nil {
+		t.Fatalf("GenerateScenario failed: %v", err)
+	}
+
+	page := d.pageRect()
+	cover := CoverZoom(page, d.ViewportWidth, d.ViewportHeight)
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, kf := range scenario.Slides[0].Keyframes {
+		if kf.Zoom < cover-1e-9 {
+			t.Errorf("%s at %.2fs: zoom %.2f below cover %.2f shows the bars", kf.Focus, kf.Time, kf.Zoom, cover)
+		}
+		// Scenario rectangles are page-normalized
+		r := kf.Rect.Denormalize(page)
+		if r.X < page.X-1e-6 || r.Y < page.Y-1e-6 || r.X+r.W > page.X+page.W+1e-6 || r.Y+r.H > page.Y+page.H+1e-6 {
+			t.Errorf("%s at %.2fs: window %+v leaves the page %+v", kf.Focus, kf.Time, r, page)
+		}
+		minX = min(minX, r.X)
+		maxX = max(maxX, r.X)
+	}
+
+	// The camera pans between the columns
+	if maxX-minX < page.W/3 {
+		t.Errorf("expected a horizontal pan across the page, got x in [%.0f, %.0f]", minX, maxX)
+	}
+}
+
+func TestToViewport(t *testing.T) {
+	d := NewDirector(1280, 720)
+	d.PageWidth, d.PageHeight = 2560, 1440 // Analysis render at twice the viewport size
+
+	mapped := d.toViewport([]analyzer.Block{{Rect: image.Rect(200, 100, 600, 300)}})
+	if mapped[0].Rect != image.Rect(100, 50, 300, 150) {
+		t.Errorf("expected block in viewport pixels, got %v", mapped[0].Rect)
+	}
+
+	d.PageWidth, d.PageHeight = 0, 0
+	if same := d.toViewport([]analyzer.Block{{Rect: image.Rect(1, 2, 3, 4)}}); same[0].Rect != image.Rect(1, 2, 3, 4) {
+		t.Error("blocks should stay unchanged without a page size")
+	}
+}
+
+func TestRotationMargin(t *testing.T) {
+	if m := RotationMargin(0, 1920, 1080); m != 1 {
+		t.Errorf("expected no margin without rotation, got %.3f", m)
+	}
+	// 16:9 frame turned by 10 degrees: the height needs the larger growth
+	a := 10 * math.Pi / 180
+	want := math.Cos(a) + 1920.0/1080*math.Sin(a)
+	if m := RotationMargin(-10, 1920, 1080); math.Abs(m-want) > 1e-9 {
+		t.Errorf("expected margin %.4f, got %.4f", want, m)
+	}
+
+	// The widest angle between keyframes counts, including the peak on the way
+	keyframes := []Keyframe{{Rotation: -5}, {Rotation: 80}}
+	if m := MaxRotationMargin(keyframes, 1920, 1080); math.Abs(m-math.Hypot(1, 1920.0/1080)) > 1e-9 {
+		t.Errorf("expected the peak margin, got %.4f", m)
+	}
+}
+
+func TestFitRotatedKeyframes(t *testing.T) {
+	page := PageRect(1080, 1920, 1920, 1080) // Portrait scan letterboxed into 16:9
+	full := Rectangle{X: 0, Y: 0, W: 1920, H: 1080}
+	keyframes := []Keyframe{
+		{Time: 0, Rect: full, Zoom: 1},
+		{Time: 1, Rect: full, Zoom: 1},
+		{Time: 3, Rect: Rectangle{X: page.X, Y: page.Y, W: 100, H: 100}, Zoom: 3, Rotation: 4},
+		{Time: 5, Rect: full, Zoom: 1, Rotation: -6},
+	}
+	fitted := FitRotatedKeyframes(keyframes, 1920, 1080, page)
+	if fitted[0].Rect != keyframes[0].Rect || fitted[0].Zoom != keyframes[0].Zoom {
+		t.Errorf("keyframes without rotation around them must not change: %+v", fitted[0])
+	}
+	if kf := fitted[2]; kf.Zoom < 3 || math.Abs(kf.Rect.W-1920/kf.Zoom) > 1e-9 {
+		t.Errorf("expected the camera window at zoom >= 3, got %+v", kf)
+	}
+
+	// Every state on the way, not only the keyframes, keeps the rotated frame on the page
+	// (zoom, center and angle follow the same progress through a segment)
+	for i := 0; i+1 < len(fitted); i++ {
+		from, to := fitted[i], fitted[i+1]
+		for k := 0; k <= 20; k++ {
+			p := float64(k) / 20
+			zoom := from.Zoom + (to.Zoom-from.Zoom)*p
+			cx := from.Rect.X + from.Rect.W/2 + (to.Rect.X+to.Rect.W/2-from.Rect.X-from.Rect.W/2)*p
+			cy := from.Rect.Y + from.Rect.H/2 + (to.Rect.Y+to.Rect.H/2-from.Rect.Y-from.Rect.H/2)*p
+			boxW, boxH := rotatedBox(from.Rotation+(to.Rotation-from.Rotation)*p, 1920, 1080)
+			boxW, boxH = boxW/zoom, boxH/zoom
+			if from.Rotation == 0 && to.Rotation == 0 {
+				continue
+			}
+			if cx-boxW/2 < page.X-1e-6 || cx+boxW/2 > page.X+page.W+1e-6 ||
+				cy-boxH/2 < page.Y-1e-6 || cy+boxH/2 > page.Y+page.H+1e-6 {
+				t.Fatalf("segment %d at %.2f leaves the page: center (%.1f, %.1f), box %.1fx%.1f, page %+v",
+					i+1, p, cx, cy, boxW, boxH, page)
+			}
+		}
+	}
+}
+
+func TestWidestRotatedBox(t *testing.T) {
+	// Passing through the diagonal angle, the width peaks between the keyframes
+	w, _ := widestRotatedBox(-5, 80, 1920, 1080)
+	if math.Abs(w-math.Hypot(1920, 1080)) > 1e-9 {
+		t.Errorf("expected the diagonal width %.1f, got %.1f", math.Hypot(1920, 1080), w)
+	}
+	// Crossing the level position keeps the level frame
+	if w, h := widestRotatedBox(-2, 3, 1920, 1080); w < 1920 || h < 1080 {
+		t.Errorf("expected at least the level frame, got %.1fx%.1f", w, h)
+	}
+}
@@ -0,0 +1,117 @@
+package director
+
+import "math"
+
+// MaxRotation limits Keyframe.Rotation (degrees either way)
+const MaxRotation = 90.0
+
+// RotationMargin returns how much a camera window has to grow so that the frame,
+// turned by rotation degrees, is still covered by it. The rotated viewW x viewH
+// frame needs a bounding box of (W|cos|+H|sin|) x (W|sin|+H|cos|).
+func RotationMargin(rotation float64, viewW, viewH int) float64 {
+	if rotation == 0 || viewW <= 0 || viewH <= 0 {
+		return 1.0
+	}
+	a := math.Abs(rotation) * math.Pi / 180
+	c, s := math.Abs(math.Cos(a)), math.Abs(math.Sin(a))
+	r := float64(viewH) / float64(viewW)
+	return math.Max(c+r*s, c+s/r)
+}
+
+// MaxRotationMargin is the largest RotationMargin over every angle the camera passes
+// through between the keyframes, which sizes a buffer shared by the whole slide
+func MaxRotationMargin(keyframes []Keyframe, viewW, viewH int) float64 {
+	maxAngle := 0.0
+	for _, kf := range keyframes {
+		maxAngle = math.Max(maxAngle, math.Min(math.Abs(kf.Rotation), MaxRotation))
+	}
+	if maxAngle == 0 || viewW <= 0 || viewH <= 0 {
+		return 1.0
+	}
+
+	// cos + r*sin peaks at atan(r) with sqrt(1 + r^2), the intermediate angles can be
+	// wider than the keyframe ones
+	margin := RotationMargin(maxAngle, viewW, viewH)
+	a := maxAngle * math.Pi / 180
+	for _, r := range []float64{float64(viewH) / float64(viewW), float64(viewW) / float64(viewH)} {
+		if a >= math.Atan(r) {
+			margin = math.Max(margin, math.Hypot(1, r))
+		}
+	}
+	return margin
+}
+
+// FitRotatedKeyframes keeps rotated shots on the page: the area a keyframe shows at
+// its rotation (the window turned around its center) must fit into the page, so the
+// zoom is raised and the window shifted until the bounding box of that area does.
+// Between keyframes the camera turns through every intermediate angle, so a keyframe
+// makes room for the widest angle of both intervals around it: the zoom and the window
+// center are interpolated with the same progress, so every state in between then fits
+// as well. Keyframes with no rotation on either side are returned as is.
+func FitRotatedKeyframes(keyframes []Keyframe, viewW, viewH int, page Rectangle) []Keyframe {
+	result := make([]Keyframe, len(keyframes))
+	copy(result, keyframes)
+	if page.W <= 2 || page.H <= 2 {
+		return result
+	}
+
+	vw, vh := float64(viewW), float64(viewH)
+	// A pixel inside the page edge, where bilinear sampling already blends in the padding
+	inner := Rectangle{X: page.X + 1, Y: page.Y + 1, W: page.W - 2, H: page.H - 2}
+
+	for i, kf := range keyframes {
+		rotated := kf.Rotation != 0
+		boxW, boxH := rotatedBox(kf.Rotation, vw, vh) // Bounding box of the rotated frame at zoom 1
+		for _, j := range []int{i - 1, i + 1} {
+			if j < 0 || j >= len(keyframes) || (kf.Rotation == 0 && keyframes[j].Rotation == 0) {
+				continue
+			}
+			rotated = true
+			w, h := widestRotatedBox(kf.Rotation, keyframes[j].Rotation, vw, vh)
+			boxW, boxH = math.Max(boxW, w), math.Max(boxH, h)
+		}
+		if !rotated {
+			continue
+		}
+
+		zoom := math.Max(kf.Zoom, 1.0)
+		if need := math.Max(boxW/inner.W, boxH/inner.H) * reframeBleed; zoom < need {
+			zoom = need
+		}
+		boxW, boxH = boxW/zoom, boxH/zoom
+
+		cx := clampRange(kf.Rect.X+kf.Rect.W/2-boxW/2, inner.X, inner.X+inner.W-boxW) + boxW/2
+		cy := clampRange(kf.Rect.Y+kf.Rect.H/2-boxH/2, inner.Y, inner.Y+inner.H-boxH) + boxH/2
+		w, h := vw/zoom, vh/zoom
+		result[i].Rect = Rectangle{X: cx - w/2, Y: cy - h/2, W: w, H: h}
+		result[i].Zoom = zoom
+	}
+	return result
+}
+
+// rotatedBox is the bounding box of a vw x vh frame turned by rotation degrees
+func rotatedBox(rotation, vw, vh float64) (float64, float64) {
+	a := rotation * math.Pi / 180
+	c, s := math.Abs(math.Cos(a)), math.Abs(math.Sin(a))
+	return vw*c + vh*s, vw*s + vh*c
+}
+
+// widestRotatedBox is the largest bounding box (each side on its own) of the frame over
+// all angles from a to b. A side grows up to its peak angle and shrinks after it, so
+// besides the ends only the peaks inside the range can be wider.
+func widestRotatedBox(a, b, vw, vh float64) (float64, float64) {
+	lo, hi := math.Min(math.Abs(a), math.Abs(b)), math.Max(math.Abs(a), math.Abs(b))
+	if a*b < 0 {
+		lo = 0 // The camera passes through the level position
+	}
+	boxW, boxH := rotatedBox(lo, vw, vh)
+	w, h := rotatedBox(hi, vw, vh)
+	boxW, boxH = math.Max(boxW, w), math.Max(boxH, h)
+	for _, peak := range []float64{math.Atan2(vh, vw), math.Atan2(vw, vh)} {
+		if deg := peak * 180 / math.Pi; deg > lo && deg < hi {
+			w, h := rotatedBox(deg, vw, vh)
+			boxW, boxH = math.Max(boxW, w), math.Max(boxH, h)
+		}
+	}
+	return boxW, boxH
+}
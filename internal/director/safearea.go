@@ -0,0 +1,67 @@
+package director
+
+import (
+	"image"
+	"math"
+)
+
+// Insets are the frame edges covered by platform UI, as shares of the frame size
+type Insets struct {
+	Top, Right, Bottom, Left float64
+}
+
+// IsZero reports whether no edge is covered
+func (in Insets) IsZero() bool {
+	return in == Insets{}
+}
+
+// hasSafeArea reports whether framing has to keep blocks away from covered parts of the frame
+func (d *Director) hasSafeArea() bool {
+	return !d.SafeArea.IsZero() || len(d.Exclusions) > 0
+}
+
+// safeFrame returns the part of the frame where block b may land: the frame without
+// the safe-area insets, cut away from every exclusion zone it overlaps. Of the four
+// ways around a zone (left, right, above or below it) the one that leaves the most
+// room for the block wins. The result is in frame pixels.
+func (d *Director) safeFrame(b image.Rectangle) Rectangle {
+	vw, vh := float64(d.ViewportWidth), float64(d.ViewportHeight)
+	in := d.SafeArea
+	area := Rectangle{
+		X: in.Left * vw,
+		Y: in.Top * vh,
+		W: vw * (1 - in.Left - in.Right),
+		H: vh * (1 - in.Top - in.Bottom),
+	}
+	if area.W <= 0 || area.H <= 0 {
+		return Rectangle{X: 0, Y: 0, W: vw, H: vh}
+	}
+
+	bw, bh := math.Max(float64(b.Dx()), 1), math.Max(float64(b.Dy()), 1)
+	for _, z := range d.Exclusions {
+		if !overlaps(area, z) {
+			continue
+		}
+		candidates := []Rectangle{
+			{X: area.X, Y: area.Y, W: z.X - area.X, H: area.H},
+			{X: z.X + z.W, Y: area.Y, W: area.X + area.W - (z.X + z.W), H: area.H},
+			{X: area.X, Y: area.Y, W: area.W, H: z.Y - area.Y},
+			{X: area.X, Y: z.Y + z.H, W: area.W, H: area.Y + area.H - (z.Y + z.H)},
+		}
+		best, bestFit := area, 0.0
+		for _, c := range candidates {
+			if c.W <= 0 || c.H <= 0 {
+				continue
+			}
+			if fit := math.Min(c.W/bw, c.H/bh); fit > bestFit {
+				best, bestFit = c, fit
+			}
+		}
+		area = best // A zone covering the whole area is ignored
+	}
+	return area
+}
+
+func overlaps(a, b Rectangle) bool {
+	return a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H
+}
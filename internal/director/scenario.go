@@ -1,31 +1,223 @@
 package director
 
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scenario format versions
+const (
+	ScenarioVersion       = "2.0" // Rectangles normalized to the page
+	LegacyScenarioVersion = "1.0" // Rectangles in viewport pixels of the output the scenario was made for
+)
+
 // Scenario represents a complete animation scenario for a video
 type Scenario struct {
-	Version string  `yaml:"version"`
-	Slides  []Slide `yaml:"slides"`
+	Version string          `yaml:"version"`
+	Camera  *CameraSettings `yaml:"camera,omitempty"` // Optional camera motion settings
+	Slides  []Slide         `yaml:"slides"`
 }
 
-// Slide represents a single page/image with its animation keyframes
+// CameraSettings controls how the camera moves between keyframes
+type CameraSettings struct {
+	Path    string           `yaml:"path,omitempty"`    // Path type: "linear" (default) or "spline"
+	Tension float64          `yaml:"tension,omitempty"` // Spline tension: 0 = Catmull-Rom, 1 = no curvature
+	Inertia *InertiaSettings `yaml:"inertia,omitempty"` // Optional overshoot and settle at the end of flights
+}
+
+// InertiaSettings describes the camera as a damped mass on a spring
+type InertiaSettings struct {
+	Mass    float64 `yaml:"mass"`    // Heavier camera overshoots further and settles slower (default 1.0)
+	Damping float64 `yaml:"damping"` // Damping ratio 0..1: lower values wobble longer (default 0.6)
+}
+
+// Supported camera path types
+const (
+	PathLinear = "linear"
+	PathSpline = "spline"
+)
+
+// Slide represents a single page/image with its animation keyframes.
+// The optional fields override the global settings for this slide only.
 type Slide struct {
-	ID        int        `yaml:"id"`
-	Input     string     `yaml:"input"`
-	Duration  float64    `yaml:"duration"` // Total duration in seconds
-	Keyframes []Keyframe `yaml:"keyframes"`
+	ID         int        `yaml:"id"`
+	Input      string     `yaml:"input"`                // Page reference: deck.pdf#7, cover.jpg, #3 (see ParseInput)
+	Duration   float64    `yaml:"duration"`             // Total duration in seconds
+	Ordering   string     `yaml:"ordering,omitempty"`   // Block ordering strategy used by the Director
+	Transition string     `yaml:"transition,omitempty"` // xfade transition into this slide ("none" = hard cut)
+	Fade       *float64   `yaml:"fade,omitempty"`       // Duration of the transition into this slide, 0 = hard cut
+	Outro      *float64   `yaml:"outro,omitempty"`      // Return to 1:1 before the transition out of this slide
+	Effect     string     `yaml:"effect,omitempty"`     // Camera effect: keyframes, zoom or static
+	ZoomMode   string     `yaml:"zoom_mode,omitempty"`  // Zoom mode of the zoom effect (center, top-left, out-random, ...)
+	Keyframes  []Keyframe `yaml:"keyframes"`
+}
+
+// InputRef is a parsed Slide.Input: a file and a 1-based page in it.
+// An empty Path refers to the main input (-input), Page 0 means "not specified".
+type InputRef struct {
+	Path string
+	Page int
+}
+
+// legacyInput matches the names written by older scenario generators
+var legacyInput = regexp.MustCompile(`^slide_(\d+)\.png$`)
+
+// ParseInput parses a slide input reference:
+//
+//	deck.pdf#7        page 7 of deck.pdf
+//	photos/cover.jpg  an image file (or the first page of a PDF)
+//	#3                page 3 of the main input
+//	slide_3.png       legacy name, page 3 of the main input unless such a file exists
+//	(empty)           the main input page with the slide's position
+//
+// Only a positive number after the last # is a page, otherwise the # belongs to the file name.
+func ParseInput(input string) InputRef {
+	input = strings.TrimSpace(input)
+	if i := strings.LastIndex(input, "#"); i >= 0 {
+		if n, err := strconv.Atoi(input[i+1:]); err == nil && n > 0 {
+			return InputRef{Path: input[:i], Page: n}
+		}
+	}
+	return InputRef{Path: input}
+}
+
+// LegacyPage reports the page of a legacy slide_N.png reference
+func (r InputRef) LegacyPage() (int, bool) {
+	m := legacyInput.FindStringSubmatch(r.Path)
+	if m == nil || r.Page != 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil && n > 0
+}
+
+// Per-slide camera effects
+const (
+	SlideEffectKeyframes = "keyframes" // Follow the scenario keyframes (default)
+	SlideEffectZoom      = "zoom"      // Built-in Ken Burns zoom with the slide zoom mode
+	SlideEffectStatic    = "static"    // Whole page, no camera movement
+)
+
+// SlideEffects lists the supported per-slide camera effects
+var SlideEffects = []string{SlideEffectKeyframes, SlideEffectZoom, SlideEffectStatic}
+
+// CameraEffect resolves the camera effect of the slide: an explicit effect wins,
+// a zoom mode alone selects the zoom effect, otherwise the keyframes are used
+func (s Slide) CameraEffect() string {
+	if s.Effect != "" {
+		return s.Effect
+	}
+	if s.ZoomMode != "" {
+		return SlideEffectZoom
+	}
+	return SlideEffectKeyframes
 }
 
 // Keyframe represents a camera position at a specific time
 type Keyframe struct {
-	Time  float64   `yaml:"time"`  // Time offset in seconds
-	Focus string    `yaml:"focus"` // Description of focus region
-	Rect  Rectangle `yaml:"rect"`  // Target rectangle
-	Zoom  float64   `yaml:"zoom"`  // Zoom level (1.0 = no zoom)
+	Time        float64      `yaml:"time"`                  // Time offset in seconds
+	Focus       string       `yaml:"focus"`                 // Description of focus region
+	Rect        Rectangle    `yaml:"rect"`                  // Target rectangle
+	FocusRect   Rectangle    `yaml:"focus_rect,omitempty"`  // Focused block when Rect is the camera window around it (emphasis region)
+	Zoom        float64      `yaml:"zoom"`                  // Zoom level (1.0 = no zoom)
+	Rotation    float64      `yaml:"rotation,omitempty"`    // Camera roll in degrees, clockwise turn of the picture (-90..90)
+	Easing      string       `yaml:"easing,omitempty"`      // Easing into the next keyframe: linear (default), ease-in, ease-out, ease-in-out, cubic-bezier(x1, y1, x2, y2)
+	Emphasis    string       `yaml:"emphasis,omitempty"`    // Treatment of everything outside the focus: dim, desaturate, blur, spotlight or none
+	Annotations []Annotation `yaml:"annotations,omitempty"` // Overlay callouts shown from this keyframe on
+}
+
+// Focus emphasis modes (Keyframe.Emphasis)
+const (
+	EmphasisNone       = "none"
+	EmphasisDim        = "dim"
+	EmphasisDesaturate = "desaturate"
+	EmphasisBlur       = "blur"
+	EmphasisSpotlight  = "spotlight"
+)
+
+// HasEmphasis reports whether the keyframe sets an emphasis mode
+func (kf Keyframe) HasEmphasis() bool {
+	return kf.Emphasis != "" && kf.Emphasis != EmphasisNone
 }
 
-// Rectangle represents a bounding box
+// FocusArea is the region the keyframe draws attention to: the focused block,
+// or Rect when the keyframe does not set one
+func (kf Keyframe) FocusArea() Rectangle {
+	if kf.FocusRect.W > 0 && kf.FocusRect.H > 0 {
+		return kf.FocusRect
+	}
+	return kf.Rect
+}
+
+// Rectangle represents a bounding box. In a scenario it is normalized to the page
+// (0..1 of the page width and height; views wider than the page go beyond that range);
+// keyframes prepared for rendering carry viewport pixels.
 type Rectangle struct {
-	X int `yaml:"x"`
-	Y int `yaml:"y"`
-	W int `yaml:"w"`
-	H int `yaml:"h"`
+	X float64 `yaml:"x"`
+	Y float64 `yaml:"y"`
+	W float64 `yaml:"w"`
+	H float64 `yaml:"h"`
+}
+
+// Normalize converts a viewport rectangle into page coordinates (page is the page area in the viewport)
+func (r Rectangle) Normalize(page Rectangle) Rectangle {
+	if page.W <= 0 || page.H <= 0 {
+		return r
+	}
+	return Rectangle{
+		X: (r.X - page.X) / page.W,
+		Y: (r.Y - page.Y) / page.H,
+		W: r.W / page.W,
+		H: r.H / page.H,
+	}
+}
+
+// Denormalize converts a page rectangle into viewport pixels (page is the page area in the viewport)
+func (r Rectangle) Denormalize(page Rectangle) Rectangle {
+	return Rectangle{
+		X: page.X + r.X*page.W,
+		Y: page.Y + r.Y*page.H,
+		W: r.W * page.W,
+		H: r.H * page.H,
+	}
+}
+
+// IsLegacy reports whether the scenario stores viewport pixels (format 1.x)
+func (s *Scenario) IsLegacy() bool {
+	return s.Version == "" || strings.HasPrefix(s.Version, "1.")
+}
+
+// checkVersion rejects scenarios written by a newer, incompatible format
+func (s *Scenario) checkVersion() error {
+	if s.IsLegacy() || s.Version == ScenarioVersion || strings.HasPrefix(s.Version, "2.") {
+		return nil
+	}
+	return fmt.Errorf("unsupported scenario version %q (supported: %s, %s)", s.Version, LegacyScenarioVersion, ScenarioVersion)
+}
+
+// Migrate converts a legacy pixel scenario to page-normalized coordinates.
+// Legacy rectangles are viewport pixels of a width x height output; pageSize returns the
+// size (any units) of the page of a slide, which was letterboxed into that output.
+func (s *Scenario) Migrate(width, height int, pageSize func(slide int) (float64, float64)) {
+	if !s.IsLegacy() {
+		return
+	}
+	for i := range s.Slides {
+		pw, ph := pageSize(i)
+		page := PageRect(int(math.Round(pw)), int(math.Round(ph)), width, height)
+		for k := range s.Slides[i].Keyframes {
+			kf := &s.Slides[i].Keyframes[k]
+			kf.Rect = kf.Rect.Normalize(page)
+			if kf.FocusRect != (Rectangle{}) {
+				kf.FocusRect = kf.FocusRect.Normalize(page)
+			}
+			for a := range kf.Annotations {
+				kf.Annotations[a] = kf.Annotations[a].Normalize(page)
+			}
+		}
+	}
+	s.Version = ScenarioVersion
 }
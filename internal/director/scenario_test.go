@@ -0,0 +1,106 @@
+package director
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func rectsClose(a, b Rectangle) bool {
+	const eps = 1e-9
+	return math.Abs(a.X-b.X) < eps && math.Abs(a.Y-b.Y) < eps &&
+		math.Abs(a.W-b.W) < eps && math.Abs(a.H-b.H) < eps
+}
+
+func TestRectangleNormalizeRoundTrip(t *testing.T) {
+	page := PageRect(1920, 1080, 720, 1280) // Letterboxed: {0, 437.5, 720, 405}
+	r := Rectangle{X: 180, Y: 538.75, W: 360, H: 202.5}
+
+	n := r.Normalize(page)
+	if !rectsClose(n, Rectangle{X: 0.25, Y: 0.25, W: 0.5, H: 0.5}) {
+		t.Errorf("unexpected normalized rectangle %+v", n)
+	}
+	if back := n.Denormalize(page); !rectsClose(back, r) {
+		t.Errorf("round trip changed the rectangle: %+v -> %+v", r, back)
+	}
+
+	// The same scenario maps onto any output size
+	wide := n.Denormalize(PageRect(1920, 1080, 1920, 1080))
+	if !rectsClose(wide, Rectangle{X: 480, Y: 270, W: 960, H: 540}) {
+		t.Errorf("unexpected rectangle for a 1920x1080 output %+v", wide)
+	}
+}
+
+func TestScenarioMigrate(t *testing.T) {
+	s := &Scenario{
+		Version: LegacyScenarioVersion,
+		Slides: []Slide{{
+			ID: 1,
+			Keyframes: []Keyframe{
+				{Focus: "full_view", Zoom: 1, Rect: Rectangle{X: 0, Y: 0, W: 1280, H: 720}},
+				{Focus: "region_1", Zoom: 2, Rect: Rectangle{X: 320, Y: 180, W: 640, H: 360}},
+			},
+		}},
+	}
+
+	// 4:3 page letterboxed into 1280x720: page area {160, 0, 960, 720}
+	s.Migrate(1280, 720, func(int) (float64, float64) { return 800, 600 })
+
+	if s.Version != ScenarioVersion || s.IsLegacy() {
+		t.Fatalf("expected version %s after migration, got %s", ScenarioVersion, s.Version)
+	}
+	got := s.Slides[0].Keyframes[1].Rect
+	if !rectsClose(got, Rectangle{X: 1.0 / 6, Y: 0.25, W: 2.0 / 3, H: 0.5}) {
+		t.Errorf("unexpected migrated rectangle %+v", got)
+	}
+
+	// Second call is a no-op
+	s.Migrate(1920, 1080, func(int) (float64, float64) { return 1, 1 })
+	if s.Slides[0].Keyframes[1].Rect != got {
+		t.Error("migration should not run twice")
+	}
+}
+
+func TestReadScenarioVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	for version, wantErr := range map[string]bool{"": false, "1.0": false, "2.0": false, "3.0": true} {
+		path := filepath.Join(dir, "scenario.yaml")
+		data := []byte("version: \"" + version + "\"\nslides: []\n")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ReadScenario(path)
+		if (err != nil) != wantErr {
+			t.Errorf("version %q: expected error %v, got %v", version, wantErr, err)
+		}
+	}
+}
+
+func TestParseInput(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   InputRef
+		legacy int
+	}{
+		{"deck.pdf#7", InputRef{Path: "deck.pdf", Page: 7}, 0},
+		{"photos/cover.jpg", InputRef{Path: "photos/cover.jpg"}, 0},
+		{"#3", InputRef{Page: 3}, 0},
+		{"", InputRef{}, 0},
+		{"slide_4.png", InputRef{Path: "slide_4.png"}, 4},
+		{"deck.pdf#0", InputRef{Path: "deck.pdf#0"}, 0},
+		{"deck.pdf#last", InputRef{Path: "deck.pdf#last"}, 0},
+		{"C#/notes#2.pdf", InputRef{Path: "C#/notes#2.pdf"}, 0},
+		{"C#/notes.pdf#2", InputRef{Path: "C#/notes.pdf", Page: 2}, 0},
+	}
+	for _, tt := range tests {
+		got := ParseInput(tt.input)
+		if got != tt.want {
+			t.Errorf("%q: expected %+v, got %+v", tt.input, tt.want, got)
+		}
+		if page, ok := got.LegacyPage(); page != tt.legacy || ok != (tt.legacy > 0) {
+			t.Errorf("%q: expected legacy page %d, got %d (%v)", tt.input, tt.legacy, page, ok)
+		}
+	}
+}
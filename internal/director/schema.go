@@ -0,0 +1,153 @@
+package director
+
+import (
+	"encoding/json"
+
+	"github.com/ivlev/pdf2video/internal/config"
+)
+
+// SchemaID is the $id of the scenario JSON Schema
+const SchemaID = "https://github.com/ivlev/pdf2video/scenario.schema.json"
+
+// ScenarioSchema returns the JSON Schema (draft 2020-12) of the scenario format.
+// YAML editors (e.g. VS Code with the YAML extension) use it for completion and
+// validation; the constraints mirror Lint where a schema can express them.
+func ScenarioSchema() ([]byte, error) {
+	number := func(description string, extra map[string]interface{}) map[string]interface{} {
+		m := map[string]interface{}{"type": "number", "description": description}
+		for k, v := range extra {
+			m[k] = v
+		}
+		return m
+	}
+
+	rectangle := map[string]interface{}{
+		"type":                 "object",
+		"description":          "Camera target. Format 2.0: fractions of the page (0..1); format 1.0: viewport pixels",
+		"required":             []string{"x", "y", "w", "h"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"x": number("Left edge", nil),
+			"y": number("Top edge", nil),
+			"w": number("Width", map[string]interface{}{"exclusiveMinimum": 0}),
+			"h": number("Height", map[string]interface{}{"exclusiveMinimum": 0}),
+		},
+	}
+
+	point := map[string]interface{}{
+		"type":                 "object",
+		"description":          "Position in the coordinates of the keyframe rectangles",
+		"required":             []string{"x", "y"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"x": number("Horizontal position", nil),
+			"y": number("Vertical position", nil),
+		},
+	}
+
+	color := map[string]interface{}{"type": "string", "pattern": "^#?[0-9A-Fa-f]{6}$"}
+	annotation := map[string]interface{}{
+		"type":                 "object",
+		"description":          "Overlay callout drawn on the page; times are seconds after the keyframe time",
+		"required":             []string{"type"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"type":       map[string]interface{}{"enum": AnnotationTypes},
+			"rect":       map[string]interface{}{"$ref": "#/$defs/rectangle", "description": "box, circle: the marked region"},
+			"from":       map[string]interface{}{"$ref": "#/$defs/point", "description": "arrow: tail"},
+			"to":         map[string]interface{}{"$ref": "#/$defs/point", "description": "arrow: head"},
+			"at":         map[string]interface{}{"$ref": "#/$defs/point", "description": "label: top-left corner of the text"},
+			"text":       map[string]interface{}{"type": "string", "description": "label: the text"},
+			"size":       number("label: text height as a fraction of the page height (default 0.04)", map[string]interface{}{"minimum": 0}),
+			"color":      color,
+			"background": color,
+			"stroke":     number("Line width in pixels of a 1:1 view (default 4), scales with the zoom", map[string]interface{}{"minimum": 0}),
+			"appear":     number("Seconds after the keyframe time", map[string]interface{}{"minimum": 0}),
+			"disappear":  number("Seconds after the keyframe time, 0 = until the slide ends", map[string]interface{}{"minimum": 0}),
+			"fade":       number("Fade in and out duration in seconds (default 0.3)", map[string]interface{}{"minimum": 0}),
+		},
+	}
+
+	keyframe := map[string]interface{}{
+		"type":                 "object",
+		"required":             []string{"time", "rect", "zoom"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"time":       number("Time offset in seconds from the slide start, keyframes are sorted by time", map[string]interface{}{"minimum": 0}),
+			"focus":      map[string]interface{}{"type": "string", "description": "Description of the focus region (full_view, region_1, ...)"},
+			"rect":       map[string]interface{}{"$ref": "#/$defs/rectangle"},
+			"focus_rect": map[string]interface{}{"$ref": "#/$defs/rectangle"},
+			"zoom":       number("Zoom level, 1.0 shows the whole page", map[string]interface{}{"minimum": 1}),
+			"rotation": number("Camera roll in degrees, clockwise turn of the picture; the shot is widened to stay on the page",
+				map[string]interface{}{"minimum": -MaxRotation, "maximum": MaxRotation}),
+			"easing": map[string]interface{}{
+				"description": "Easing into the next keyframe (linear by default)",
+				"anyOf": []interface{}{
+					map[string]interface{}{"enum": EasingNames},
+					map[string]interface{}{"type": "string", "pattern": `^cubic-bezier\(.*\)$`},
+				},
+			},
+			"emphasis":    map[string]interface{}{"enum": config.SupportedEmphasisModes, "description": "Treatment of everything outside focus_rect (or rect), eased in and out with the camera"},
+			"annotations": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/annotation"}},
+		},
+	}
+
+	slide := map[string]interface{}{
+		"type":                 "object",
+		"required":             []string{"id", "duration"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"id":         map[string]interface{}{"type": "integer", "description": "Slide number"},
+			"input":      map[string]interface{}{"type": "string", "description": "Page reference: deck.pdf#7 (page of a file), cover.jpg (image), #7 (page of -input); empty = page by position"},
+			"duration":   number("Total slide duration in seconds", map[string]interface{}{"exclusiveMinimum": 0}),
+			"ordering":   map[string]interface{}{"enum": SupportedOrderings, "description": "Block ordering used by the Director"},
+			"transition": map[string]interface{}{"enum": config.SupportedTransitions, "description": "xfade transition into this slide, none = hard cut"},
+			"fade":       number("Duration of the transition into this slide in seconds, 0 = hard cut", map[string]interface{}{"minimum": 0}),
+			"outro":      number("Return to 1:1 before the transition out of this slide, seconds", map[string]interface{}{"minimum": 0}),
+			"effect":     map[string]interface{}{"enum": SlideEffects, "description": "Camera effect of the slide (default: keyframes, or zoom when zoom_mode is set)"},
+			"zoom_mode":  map[string]interface{}{"enum": config.SupportedZoomModes, "description": "Zoom mode of the zoom effect"},
+			"keyframes":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/keyframe"}},
+		},
+	}
+
+	camera := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"path":    map[string]interface{}{"enum": []string{PathLinear, PathSpline}, "description": "Camera path between keyframes"},
+			"tension": number("Spline tension: 0 = Catmull-Rom, 1 = no curvature", map[string]interface{}{"minimum": 0, "maximum": 1}),
+			"inertia": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"mass":    number("Heavier camera overshoots further (0 = default 1.0)", map[string]interface{}{"minimum": 0}),
+					"damping": number("Damping ratio, lower values wobble longer (0 = default 0.6)", map[string]interface{}{"minimum": 0, "maximum": 1}),
+				},
+			},
+		},
+	}
+
+	schema := map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"$id":                  SchemaID,
+		"title":                "pdf2video scenario",
+		"type":                 "object",
+		"required":             []string{"version", "slides"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"version": map[string]interface{}{"enum": []string{ScenarioVersion, LegacyScenarioVersion}},
+			"camera":  map[string]interface{}{"$ref": "#/$defs/camera"},
+			"slides":  map[string]interface{}{"type": "array", "minItems": 1, "items": map[string]interface{}{"$ref": "#/$defs/slide"}},
+		},
+		"$defs": map[string]interface{}{
+			"camera":     camera,
+			"slide":      slide,
+			"keyframe":   keyframe,
+			"rectangle":  rectangle,
+			"annotation": annotation,
+			"point":      point,
+		},
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
@@ -0,0 +1,239 @@
+package director
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultStyleName is the style that reproduces the built-in Director defaults
+const DefaultStyleName = "default"
+
+// Style is a named set of directing parameters that are tuned together:
+// timing, framing, camera speed, visiting order and block importance.
+type Style struct {
+	Name            string             `yaml:"name"`
+	Base            string             `yaml:"base,omitempty"` // Built-in style to start from (YAML files only)
+	Description     string             `yaml:"description,omitempty"`
+	MinDwell        float64            `yaml:"min_dwell"`        // Minimum time per block (seconds)
+	MaxDwell        float64            `yaml:"max_dwell"`        // Maximum time per block (seconds)
+	MinZoom         float64            `yaml:"min_zoom"`         // Lower zoom clamp for focused blocks
+	MaxZoom         float64            `yaml:"max_zoom"`         // Upper zoom clamp for focused blocks
+	Padding         float64            `yaml:"padding"`          // Share of the viewport a focused block may fill (0..1)
+	CameraSpeed     float64            `yaml:"camera_speed"`     // Pan speed (viewport diagonals per second)
+	Composition     string             `yaml:"composition"`      // Framing: center, thirds or leading
+	AdaptivePadding bool               `yaml:"adaptive_padding"` // Padding from the whitespace around each block
+	ReadingWPM      float64            `yaml:"reading_wpm"`      // Reading speed for text blocks (words per minute)
+	Ordering        string             `yaml:"ordering"`         // Visiting order: reading, greedy or tsp
+	Optimizer       OptimizerWeights   `yaml:"optimizer"`
+	Prioritizer     PrioritizerWeights `yaml:"prioritizer"`
+}
+
+// OptimizerWeights mirrors the TrajectoryOptimizer weights
+type OptimizerWeights struct {
+	Priority float64 `yaml:"priority"`
+	Distance float64 `yaml:"distance"`
+}
+
+// PrioritizerWeights mirrors the analyzer.BlockPrioritizer weights
+type PrioritizerWeights struct {
+	Content  float64 `yaml:"content"`
+	Score    float64 `yaml:"score"`
+	Position float64 `yaml:"position"`
+	Size     float64 `yaml:"size"`
+}
+
+var builtinStyles = map[string]Style{
+	DefaultStyleName: {
+		Name:            DefaultStyleName,
+		Description:     "Balanced camera, the Director defaults",
+		MinDwell:        1.0,
+		MaxDwell:        3.0,
+		MinZoom:         1.0,
+		MaxZoom:         3.0,
+		Padding:         0.9,
+		CameraSpeed:     0.6,
+		Composition:     CompositionCenter,
+		AdaptivePadding: false,
+		ReadingWPM:      200,
+		Ordering:        OrderingReading,
+		Optimizer:       OptimizerWeights{Priority: 0.6, Distance: 0.4},
+		Prioritizer:     PrioritizerWeights{Content: 0.40, Score: 0.25, Position: 0.20, Size: 0.15},
+	},
+	"lecture": {
+		Name:            "lecture",
+		Description:     "Reading flow: long holds, moderate zoom, slow pans, top-to-bottom order",
+		MinDwell:        2.0,
+		MaxDwell:        8.0,
+		MinZoom:         1.0,
+		MaxZoom:         2.5,
+		Padding:         0.85,
+		CameraSpeed:     0.4,
+		Composition:     CompositionLeading,
+		AdaptivePadding: true,
+		ReadingWPM:      160,
+		Ordering:        OrderingReading,
+		Optimizer:       OptimizerWeights{Priority: 0.3, Distance: 0.7},
+		Prioritizer:     PrioritizerWeights{Content: 0.30, Score: 0.15, Position: 0.40, Size: 0.15},
+	},
+	"promo": {
+		Name:            "promo",
+		Description:     "Dynamic: short holds, tight zooms on key visuals, fast travel",
+		MinDwell:        0.6,
+		MaxDwell:        2.0,
+		MinZoom:         1.2,
+		MaxZoom:         3.5,
+		Padding:         0.95,
+		CameraSpeed:     1.2,
+		Composition:     CompositionThirds,
+		AdaptivePadding: false,
+		ReadingWPM:      260,
+		Ordering:        OrderingGreedy,
+		Optimizer:       OptimizerWeights{Priority: 0.8, Distance: 0.2},
+		Prioritizer:     PrioritizerWeights{Content: 0.50, Score: 0.30, Position: 0.05, Size: 0.15},
+	},
+	"gallery": {
+		Name:            "gallery",
+		Description:     "Calm: wide framing with air around images, slow drifts, long holds",
+		MinDwell:        2.5,
+		MaxDwell:        6.0,
+		MinZoom:         1.0,
+		MaxZoom:         1.8,
+		Padding:         0.75,
+		CameraSpeed:     0.3,
+		Composition:     CompositionThirds,
+		AdaptivePadding: true,
+		ReadingWPM:      200,
+		Ordering:        OrderingTSP,
+		Optimizer:       OptimizerWeights{Priority: 0.5, Distance: 0.5},
+		Prioritizer:     PrioritizerWeights{Content: 0.25, Score: 0.35, Position: 0.10, Size: 0.30},
+	},
+}
+
+// StyleNames returns the names of the built-in styles
+func StyleNames() []string {
+	names := make([]string, 0, len(builtinStyles))
+	for name := range builtinStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinStyle returns a built-in style by name
+func BuiltinStyle(name string) (Style, bool) {
+	s, ok := builtinStyles[name]
+	return s, ok
+}
+
+// LoadStyle resolves a style by built-in name or loads it from a YAML file.
+// An empty name returns the default style. In a YAML file every omitted field is
+// taken from the style named in "base" (or from the default style).
+func LoadStyle(nameOrPath string) (Style, error) {
+	if nameOrPath == "" {
+		return builtinStyles[DefaultStyleName], nil
+	}
+	if s, ok := builtinStyles[nameOrPath]; ok {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Style{}, fmt.Errorf("unknown style %q: use one of %s or a path to a YAML file",
+				nameOrPath, strings.Join(StyleNames(), ", "))
+		}
+		return Style{}, err
+	}
+	return ParseStyle(data)
+}
+
+// ParseStyle parses a style from YAML on top of its base style
+func ParseStyle(data []byte) (Style, error) {
+	var header struct {
+		Base string `yaml:"base"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return Style{}, fmt.Errorf("invalid style file: %w", err)
+	}
+
+	baseName := header.Base
+	if baseName == "" {
+		baseName = DefaultStyleName
+	}
+	style, ok := builtinStyles[baseName]
+	if !ok {
+		return Style{}, fmt.Errorf("unknown base style %q: use one of %s", baseName, strings.Join(StyleNames(), ", "))
+	}
+	style.Name = "custom"
+
+	if err := yaml.Unmarshal(data, &style); err != nil {
+		return Style{}, fmt.Errorf("invalid style file: %w", err)
+	}
+	if err := style.Validate(); err != nil {
+		return Style{}, err
+	}
+	return style, nil
+}
+
+// Validate checks that the style parameters are consistent
+func (s Style) Validate() error {
+	switch {
+	case s.MinDwell < 0 || s.MaxDwell <= 0 || s.MinDwell > s.MaxDwell:
+		return fmt.Errorf("style %s: invalid dwell range %.2f..%.2f", s.Name, s.MinDwell, s.MaxDwell)
+	case s.MinZoom < 1.0 || s.MaxZoom < s.MinZoom:
+		return fmt.Errorf("style %s: invalid zoom range %.2f..%.2f (min zoom must be >= 1.0)", s.Name, s.MinZoom, s.MaxZoom)
+	case s.Padding <= 0 || s.Padding > 1:
+		return fmt.Errorf("style %s: padding must be in (0, 1], got %.2f", s.Name, s.Padding)
+	case s.ReadingWPM < 0:
+		return fmt.Errorf("style %s: reading speed must not be negative, got %.0f", s.Name, s.ReadingWPM)
+	case s.CameraSpeed <= 0:
+		return fmt.Errorf("style %s: camera speed must be positive, got %.2f", s.Name, s.CameraSpeed)
+	case !isComposition(s.Composition):
+		return fmt.Errorf("style %s: unknown composition %q, use one of %s", s.Name, s.Composition, strings.Join(SupportedCompositions, ", "))
+	case !isOrdering(s.Ordering):
+		return fmt.Errorf("style %s: unknown ordering %q, use one of %s", s.Name, s.Ordering, strings.Join(SupportedOrderings, ", "))
+	case s.Optimizer.Priority < 0 || s.Optimizer.Distance < 0:
+		return fmt.Errorf("style %s: optimizer weights must not be negative", s.Name)
+	case s.Prioritizer.Content < 0 || s.Prioritizer.Score < 0 || s.Prioritizer.Position < 0 || s.Prioritizer.Size < 0:
+		return fmt.Errorf("style %s: prioritizer weights must not be negative", s.Name)
+	}
+	return nil
+}
+
+// NewPrioritizer builds a BlockPrioritizer with the style weights
+func (s Style) NewPrioritizer() *analyzer.BlockPrioritizer {
+	return &analyzer.BlockPrioritizer{
+		ContentWeight:  s.Prioritizer.Content,
+		ScoreWeight:    s.Prioritizer.Score,
+		PositionWeight: s.Prioritizer.Position,
+		SizeWeight:     s.Prioritizer.Size,
+	}
+}
+
+// NewOptimizer builds a TrajectoryOptimizer with the style weights
+func (s Style) NewOptimizer() *TrajectoryOptimizer {
+	return &TrajectoryOptimizer{
+		PriorityWeight: s.Optimizer.Priority,
+		DistanceWeight: s.Optimizer.Distance,
+	}
+}
+
+// ApplyStyle sets all Director parameters controlled by the style
+func (d *Director) ApplyStyle(s Style) {
+	d.MinDwell = s.MinDwell
+	d.MaxDwell = s.MaxDwell
+	d.MinZoom = s.MinZoom
+	d.MaxZoom = s.MaxZoom
+	d.Padding = s.Padding
+	d.CameraSpeed = s.CameraSpeed
+	d.Composition = s.Composition
+	d.AdaptivePadding = s.AdaptivePadding
+	d.ReadingWPM = s.ReadingWPM
+	d.Ordering = s.Ordering
+	d.Optimizer = s.NewOptimizer()
+}
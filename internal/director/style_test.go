@@ -0,0 +1,97 @@
+package director
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuiltinStylesValid(t *testing.T) {
+	for _, name := range StyleNames() {
+		style, err := LoadStyle(name)
+		if err != nil {
+			t.Fatalf("LoadStyle(%q) failed: %v", name, err)
+		}
+		if err := style.Validate(); err != nil {
+			t.Errorf("built-in style %s is invalid: %v", name, err)
+		}
+	}
+}
+
+func TestDefaultStyleMatchesDirectorDefaults(t *testing.T) {
+	base := NewDirector(1280, 720)
+	styled := NewDirector(1280, 720)
+
+	style, err := LoadStyle("")
+	if err != nil {
+		t.Fatalf("LoadStyle failed: %v", err)
+	}
+	styled.ApplyStyle(style)
+
+	if base.MinDwell != styled.MinDwell || base.MaxDwell != styled.MaxDwell ||
+		base.MinZoom != styled.MinZoom || base.MaxZoom != styled.MaxZoom ||
+		base.Padding != styled.Padding || base.CameraSpeed != styled.CameraSpeed ||
+		base.ReadingWPM != styled.ReadingWPM ||
+		*base.Optimizer != *styled.Optimizer {
+		t.Errorf("default style changes Director defaults: %+v vs %+v", styled, base)
+	}
+}
+
+func TestApplyStyleZoomClamp(t *testing.T) {
+	d := NewDirector(1280, 720)
+	small := image.Rect(100, 100, 150, 120) // Would need a huge zoom
+
+	if zoom := d.calculateZoom(small); zoom != 3.0 {
+		t.Errorf("expected default max zoom 3.0, got %f", zoom)
+	}
+
+	gallery, _ := BuiltinStyle("gallery")
+	d.ApplyStyle(gallery)
+	if zoom := d.calculateZoom(small); zoom != gallery.MaxZoom {
+		t.Errorf("expected gallery max zoom %f, got %f", gallery.MaxZoom, zoom)
+	}
+
+	promo, _ := BuiltinStyle("promo")
+	d.ApplyStyle(promo)
+	large := image.Rect(0, 0, 1200, 700)
+	if zoom := d.calculateZoom(large); zoom != promo.MinZoom {
+		t.Errorf("expected promo min zoom %f, got %f", promo.MinZoom, zoom)
+	}
+}
+
+func TestLoadStyleFromYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "style.yaml")
+	data := "name: webinar\nbase: lecture\nmax_dwell: 10\noptimizer:\n  priority: 0.1\n  distance: 0.9\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	style, err := LoadStyle(path)
+	if err != nil {
+		t.Fatalf("LoadStyle failed: %v", err)
+	}
+
+	lecture, _ := BuiltinStyle("lecture")
+	if style.Name != "webinar" || style.MaxDwell != 10 {
+		t.Errorf("expected overridden fields, got %+v", style)
+	}
+	if style.MinDwell != lecture.MinDwell || style.Prioritizer != lecture.Prioritizer {
+		t.Errorf("omitted fields should come from the base style, got %+v", style)
+	}
+	if style.Optimizer.Priority != 0.1 || style.Optimizer.Distance != 0.9 {
+		t.Errorf("expected optimizer weights from file, got %+v", style.Optimizer)
+	}
+}
+
+func TestLoadStyleErrors(t *testing.T) {
+	if _, err := LoadStyle("cinematic-nonexistent"); err == nil {
+		t.Error("expected error for unknown style")
+	}
+	if _, err := ParseStyle([]byte("base: unknown\n")); err == nil {
+		t.Error("expected error for unknown base style")
+	}
+	if _, err := ParseStyle([]byte("min_zoom: 0.5\n")); err == nil {
+		t.Error("expected validation error for zoom below 1.0")
+	}
+}
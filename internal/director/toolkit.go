@@ -0,0 +1,412 @@
+package director
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Scenario editing operations. All of them keep the keyframes of every slide
+// sorted by time and within the slide duration.
+
+// Shift moves the keyframes of a slide (1-based) by offset seconds. The keyframe at the
+// slide start and the closing keyframes (see closingStart) stay in place, so the slide
+// still opens on the same frame and zooms out before its transition out exactly as
+// before; the keyframes between them move and must stay strictly between the two,
+// since a keyframe landing on an anchor could no longer be told apart from it.
+func (s *Scenario) Shift(slide int, offset float64) error {
+	sl, err := s.slide(slide)
+	if err != nil {
+		return err
+	}
+	if len(sl.Keyframes) == 0 {
+		return nil
+	}
+
+	closing := sl.closingStart()
+	var moving []int
+	first, last := math.Inf(1), math.Inf(-1)
+	for i, kf := range sl.Keyframes {
+		if kf.Time <= 1e-9 || kf.Time >= closing-1e-9 {
+			continue
+		}
+		moving = append(moving, i)
+		first = math.Min(first, kf.Time)
+		last = math.Max(last, kf.Time)
+	}
+	if len(moving) == 0 {
+		return fmt.Errorf("slide %d has no keyframes between its start and its closing zoom-out to shift", slide)
+	}
+	minOffset := -first
+	maxOffset := closing - last
+	if offset <= minOffset+1e-9 || offset >= maxOffset-1e-9 {
+		return fmt.Errorf("shifting slide %d by %s moves keyframes onto or past the slide start or its closing zoom-out (0-%s): allowed offsets lie strictly between %s and %s",
+			slide, formatSeconds(offset), formatSeconds(closing), formatSeconds(minOffset), formatSeconds(maxOffset))
+	}
+
+	for _, i := range moving {
+		sl.Keyframes[i].Time += offset
+	}
+	sl.tidyKeyframes()
+	return nil
+}
+
+// closingStart returns the time the slide starts closing: the Director holds the last
+// block until outro_stable, zooms out to the full_view reached when the transition out
+// starts and keeps it through the crossfade. The trailing run of such keyframes marks
+// it, or else the fade and outro overrides of the slide; without either it is the end.
+func (sl *Slide) closingStart() float64 {
+	if sl.Duration <= 0 {
+		return math.Inf(1)
+	}
+	start := sl.Duration
+	if sl.Fade != nil || sl.Outro != nil {
+		var fade, outro float64
+		if sl.Fade != nil {
+			fade = *sl.Fade
+		}
+		if sl.Outro != nil {
+			outro = *sl.Outro
+		}
+		start = math.Max(0, sl.Duration-fade-outro)
+	}
+	for i := len(sl.Keyframes) - 1; i >= 0; i-- {
+		kf := sl.Keyframes[i]
+		if kf.Time <= 1e-9 || (kf.Focus != "full_view" && kf.Focus != "outro_stable") {
+			break
+		}
+		start = math.Min(start, kf.Time)
+		if kf.Focus == "outro_stable" {
+			break
+		}
+	}
+	return start
+}
+
+// Stretch scales the slide durations so that they add up to total seconds.
+// Keyframe times scale with their slide, so the camera work keeps its rhythm.
+func (s *Scenario) Stretch(total float64) error {
+	if total <= 0 {
+		return fmt.Errorf("total duration must be positive, got %.2f", total)
+	}
+	current := 0.0
+	for _, sl := range s.Slides {
+		current += sl.Duration
+	}
+	if current <= 0 {
+		return fmt.Errorf("scenario has no duration to stretch")
+	}
+
+	scale := total / current
+	for i := range s.Slides {
+		s.Slides[i].retime(s.Slides[i].Duration * scale)
+	}
+	return nil
+}
+
+// Merge copies the camera work (keyframes and ordering) of pages from..to (1-based,
+// inclusive) of an input file into the slides of s that show the same pages. Slides
+// are matched by their page reference (see pageRef), not by position, since -pages,
+// reordering and several inputs break the position = page rule; an empty input is the
+// main input. The keyframes are rescaled to the target slide durations; inputs,
+// durations and overrides of s are kept.
+func (s *Scenario) Merge(src *Scenario, input string, from, to int) error {
+	if from < 1 || to < from {
+		return fmt.Errorf("invalid page range %d-%d", from, to)
+	}
+	if s.IsLegacy() != src.IsLegacy() {
+		return fmt.Errorf("cannot merge a %q scenario into a %q one: coordinates differ, render or migrate the legacy one first",
+			src.Version, s.Version)
+	}
+
+	input = cleanInputPath(input)
+	donors := make(map[InputRef]int)
+	for i := range src.Slides {
+		if ref := src.pageRef(i); donors[ref] == 0 {
+			donors[ref] = i + 1
+		}
+	}
+
+	var targets []int
+	for i := range s.Slides {
+		ref := s.pageRef(i)
+		if ref.Path != input || ref.Page < from || ref.Page > to {
+			continue
+		}
+		if donors[ref] == 0 {
+			return fmt.Errorf("page %d of %s is not in the source scenario", ref.Page, inputName(input))
+		}
+		targets = append(targets, i)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no slide shows pages %d-%d of %s", from, to, inputName(input))
+	}
+
+	for _, i := range targets {
+		donor := src.Slides[donors[s.pageRef(i)]-1].clone()
+		if donor.Duration > 0 {
+			donor.retime(s.Slides[i].Duration)
+		}
+		s.Slides[i].Keyframes = donor.Keyframes
+		s.Slides[i].Ordering = donor.Ordering
+		s.Slides[i].tidyKeyframes()
+	}
+	return nil
+}
+
+// pageRef resolves the input of the slide at a 0-based position to the file and page it
+// shows: a legacy slide_N.png and a slide without an input show a page of the main
+// input (the latter the page at its position), a file without a page its first page
+func (s *Scenario) pageRef(i int) InputRef {
+	ref := ParseInput(s.Slides[i].Input)
+	if n, ok := ref.LegacyPage(); ok {
+		return InputRef{Page: n}
+	}
+	ref.Path = cleanInputPath(ref.Path)
+	switch {
+	case ref.Path == "" && ref.Page == 0:
+		ref.Page = i + 1
+	case ref.Page == 0:
+		ref.Page = 1
+	}
+	return ref
+}
+
+func cleanInputPath(path string) string {
+	if path = strings.TrimSpace(path); path == "" {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+func inputName(path string) string {
+	if path == "" {
+		return "the main input"
+	}
+	return path
+}
+
+// Split cuts the scenario before slide at (1-based): the first part gets slides
+// 1..at-1, the second part at..N renumbered from 1. Camera settings are copied to both.
+// Slides without an input took the page at their position, so they get an explicit #N
+// and keep showing the same page after renumbering.
+func (s *Scenario) Split(at int) (*Scenario, *Scenario, error) {
+	if at < 2 || at > len(s.Slides) {
+		return nil, nil, fmt.Errorf("cannot split %d slides before slide %d", len(s.Slides), at)
+	}
+	part := func(offset int, slides []Slide) *Scenario {
+		out := &Scenario{Version: s.Version, Camera: s.Camera.clone()}
+		for i, sl := range slides {
+			c := sl.clone()
+			c.ID = i + 1
+			if strings.TrimSpace(c.Input) == "" {
+				c.Input = fmt.Sprintf("#%d", offset+i+1)
+			}
+			out.Slides = append(out.Slides, c)
+		}
+		return out
+	}
+	return part(0, s.Slides[:at-1]), part(at-1, s.Slides[at-1:]), nil
+}
+
+// Change is a single difference between two scenarios
+type Change struct {
+	Kind string // "+" added, "-" removed, "~" changed
+	Path string // e.g. "slides[4].keyframes[2].zoom"
+	Old  string
+	New  string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case "+":
+		return fmt.Sprintf("+ %s: %s", c.Path, c.New)
+	case "-":
+		return fmt.Sprintf("- %s: %s", c.Path, c.Old)
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, c.Old, c.New)
+}
+
+// Diff lists the differences from a to b: scenario settings, slides and keyframes by position
+func Diff(a, b *Scenario) []Change {
+	var changes []Change
+	changed := func(path, old, new string) {
+		if old != new {
+			changes = append(changes, Change{Kind: "~", Path: path, Old: old, New: new})
+		}
+	}
+
+	changed("version", a.Version, b.Version)
+	if !reflect.DeepEqual(a.Camera, b.Camera) {
+		changed("camera", cameraSummary(a.Camera), cameraSummary(b.Camera))
+	}
+
+	for i := 0; i < len(a.Slides) || i < len(b.Slides); i++ {
+		path := fmt.Sprintf("slides[%d]", i+1)
+		switch {
+		case i >= len(a.Slides):
+			changes = append(changes, Change{Kind: "+", Path: path, New: b.Slides[i].summary()})
+			continue
+		case i >= len(b.Slides):
+			changes = append(changes, Change{Kind: "-", Path: path, Old: a.Slides[i].summary()})
+			continue
+		}
+
+		sa, sb := a.Slides[i], b.Slides[i]
+		changed(path+".id", fmt.Sprint(sa.ID), fmt.Sprint(sb.ID))
+		changed(path+".input", sa.Input, sb.Input)
+		changed(path+".duration", formatSeconds(sa.Duration), formatSeconds(sb.Duration))
+		changed(path+".ordering", sa.Ordering, sb.Ordering)
+		changed(path+".transition", sa.Transition, sb.Transition)
+		changed(path+".fade", formatOptional(sa.Fade), formatOptional(sb.Fade))
+		changed(path+".outro", formatOptional(sa.Outro), formatOptional(sb.Outro))
+		changed(path+".effect", sa.Effect, sb.Effect)
+		changed(path+".zoom_mode", sa.ZoomMode, sb.ZoomMode)
+
+		for k := 0; k < len(sa.Keyframes) || k < len(sb.Keyframes); k++ {
+			kpath := fmt.Sprintf("%s.keyframes[%d]", path, k+1)
+			switch {
+			case k >= len(sa.Keyframes):
+				changes = append(changes, Change{Kind: "+", Path: kpath, New: sb.Keyframes[k].summary()})
+			case k >= len(sb.Keyframes):
+				changes = append(changes, Change{Kind: "-", Path: kpath, Old: sa.Keyframes[k].summary()})
+			default:
+				ka, kb := sa.Keyframes[k], sb.Keyframes[k]
+				changed(kpath+".time", formatSeconds(ka.Time), formatSeconds(kb.Time))
+				changed(kpath+".focus", ka.Focus, kb.Focus)
+				changed(kpath+".zoom", fmt.Sprintf("%.3g", ka.Zoom), fmt.Sprintf("%.3g", kb.Zoom))
+				changed(kpath+".rect", ka.Rect.summary(), kb.Rect.summary())
+				changed(kpath+".focus_rect", ka.FocusRect.summary(), kb.FocusRect.summary())
+				changed(kpath+".rotation", fmt.Sprintf("%.3g", ka.Rotation), fmt.Sprintf("%.3g", kb.Rotation))
+				changed(kpath+".easing", ka.EasingCurve().String(), kb.EasingCurve().String())
+				changed(kpath+".emphasis", ka.Emphasis, kb.Emphasis)
+				if !reflect.DeepEqual(ka.Annotations, kb.Annotations) {
+					changed(kpath+".annotations", annotationsSummary(ka.Annotations), annotationsSummary(kb.Annotations))
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// slide returns the slide with a 1-based position
+func (s *Scenario) slide(n int) (*Slide, error) {
+	if n < 1 || n > len(s.Slides) {
+		return nil, fmt.Errorf("slide %d does not exist (scenario has %d slides)", n, len(s.Slides))
+	}
+	return &s.Slides[n-1], nil
+}
+
+// retime changes the slide duration, scaling the keyframe and annotation times with it
+func (sl *Slide) retime(duration float64) {
+	if sl.Duration > 0 {
+		scale := duration / sl.Duration
+		for i := range sl.Keyframes {
+			kf := &sl.Keyframes[i]
+			kf.Time *= scale
+			for a := range kf.Annotations {
+				kf.Annotations[a].Appear *= scale
+				kf.Annotations[a].Disappear *= scale
+			}
+		}
+	}
+	sl.Duration = duration
+	sl.tidyKeyframes()
+}
+
+// tidyKeyframes clamps keyframe times to the slide and sorts them (stable for equal times)
+func (sl *Slide) tidyKeyframes() {
+	for i := range sl.Keyframes {
+		t := math.Max(0, sl.Keyframes[i].Time)
+		if sl.Duration > 0 {
+			t = math.Min(t, sl.Duration)
+		}
+		sl.Keyframes[i].Time = t
+	}
+	sort.SliceStable(sl.Keyframes, func(i, j int) bool {
+		return sl.Keyframes[i].Time < sl.Keyframes[j].Time
+	})
+}
+
+// clone returns a copy of the slide that shares no keyframes or overrides with the original
+func (sl Slide) clone() Slide {
+	c := sl
+	c.Keyframes = append([]Keyframe(nil), sl.Keyframes...)
+	for i := range c.Keyframes {
+		c.Keyframes[i].Annotations = append([]Annotation(nil), sl.Keyframes[i].Annotations...)
+	}
+	if sl.Fade != nil {
+		fade := *sl.Fade
+		c.Fade = &fade
+	}
+	if sl.Outro != nil {
+		outro := *sl.Outro
+		c.Outro = &outro
+	}
+	return c
+}
+
+// clone returns a copy of the camera settings that shares no inertia with the original
+func (c *CameraSettings) clone() *CameraSettings {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	if c.Inertia != nil {
+		inertia := *c.Inertia
+		out.Inertia = &inertia
+	}
+	return &out
+}
+
+func (sl Slide) summary() string {
+	return fmt.Sprintf("%s %s, %d keyframes", sl.Input, formatSeconds(sl.Duration), len(sl.Keyframes))
+}
+
+func (kf Keyframe) summary() string {
+	return fmt.Sprintf("%s %s zoom %.3g %s", formatSeconds(kf.Time), kf.Focus, kf.Zoom, kf.Rect.summary())
+}
+
+func (r Rectangle) summary() string {
+	return fmt.Sprintf("{%.4g %.4g %.4g %.4g}", r.X, r.Y, r.W, r.H)
+}
+
+func annotationsSummary(annotations []Annotation) string {
+	if len(annotations) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(annotations))
+	for i, a := range annotations {
+		parts[i] = a.Type
+		if a.Text != "" {
+			parts[i] += fmt.Sprintf(" %q", a.Text)
+		}
+		parts[i] += fmt.Sprintf(" %s-%s", formatSeconds(a.Appear), formatSeconds(a.Disappear))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func cameraSummary(c *CameraSettings) string {
+	if c == nil {
+		return "default"
+	}
+	s := fmt.Sprintf("path %q tension %.3g", c.Path, c.Tension)
+	if c.Inertia != nil {
+		s += fmt.Sprintf(" inertia mass %.3g damping %.3g", c.Inertia.Mass, c.Inertia.Damping)
+	}
+	return s
+}
+
+func formatSeconds(t float64) string {
+	return fmt.Sprintf("%.2fs", t)
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatSeconds(*v)
+}
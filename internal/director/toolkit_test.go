@@ -0,0 +1,295 @@
+package director
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+func toolkitScenario() *Scenario {
+	return &Scenario{
+		Version: ScenarioVersion,
+		Slides: []Slide{
+			{ID: 1, Input: "#1", Duration: 4, Keyframes: []Keyframe{
+				{Time: 0, Focus: "full_view", Zoom: 1, Rect: Rectangle{W: 1, H: 1}},
+				{Time: 2, Focus: "region_1", Zoom: 2, Rect: Rectangle{X: 0.25, Y: 0.25, W: 0.5, H: 0.5}},
+				{Time: 3.8, Focus: "full_view", Zoom: 1, Rect: Rectangle{W: 1, H: 1}},
+			}},
+			{ID: 2, Input: "#2", Duration: 6, Keyframes: []Keyframe{
+				{Time: 0, Focus: "full_view", Zoom: 1, Rect: Rectangle{W: 1, H: 1}},
+				{Time: 3, Focus: "region_1", Zoom: 1.5, Rect: Rectangle{X: 0.1, Y: 0.1, W: 0.6, H: 0.6}},
+			}},
+			{ID: 3, Input: "#3", Duration: 5},
+		},
+	}
+}
+
+func keyframeTimes(sl Slide) []float64 {
+	var times []float64
+	for _, kf := range sl.Keyframes {
+		times = append(times, kf.Time)
+	}
+	return times
+}
+
+func timesEqual(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestShift(t *testing.T) {
+	s := toolkitScenario()
+	if err := s.Shift(1, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	// The keyframe at the slide start and the closing full view are anchors and stay in place
+	if got := keyframeTimes(s.Slides[0]); !timesEqual(got, []float64{0, 2.5, 3.8}) {
+		t.Errorf("unexpected times after shift: %v", got)
+	}
+	if err := s.Shift(1, -0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keyframes are not clamped: a shift out of the slide or onto its closing is rejected and changes nothing
+	if err := s.Shift(1, 1.8); err == nil {
+		t.Error("expected an error for a shift onto the closing full view")
+	}
+	if err := s.Shift(1, 2.5); err == nil {
+		t.Error("expected an error for a shift past the closing full view")
+	}
+	if err := s.Shift(2, 3); err == nil {
+		t.Error("expected an error for a shift onto the slide end")
+	}
+	if err := s.Shift(2, -4); err == nil {
+		t.Error("expected an error for a shift before the slide start")
+	}
+	if got := keyframeTimes(s.Slides[1]); !timesEqual(got, []float64{0, 3}) {
+		t.Errorf("a rejected shift should keep the times: %v", got)
+	}
+
+	// Without closing keyframes the fade and outro overrides mark the closing window
+	fade, outro := 0.5, 1.0
+	s.Slides[1].Fade, s.Slides[1].Outro = &fade, &outro
+	if err := s.Shift(2, 1.5); err == nil {
+		t.Error("expected an error for a shift into the fade and outro window")
+	}
+	if err := s.Shift(2, 1.4); err != nil {
+		t.Errorf("a shift before the fade and outro window should pass: %v", err)
+	}
+
+	if err := s.Shift(4, 1); err == nil {
+		t.Error("expected an error for a missing slide")
+	}
+}
+
+func TestShift_GeneratedSlide(t *testing.T) {
+	d := NewDirector(1280, 720)
+	blocks := []analyzer.Block{
+		{Rect: image.Rect(100, 100, 400, 200), Type: analyzer.BlockTypeText},
+		{Rect: image.Rect(700, 400, 1100, 650), Type: analyzer.BlockTypeImage},
+	}
+	s, err := d.GenerateScenario(blocks, "#1", 8, 0.5, 1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := keyframeTimes(s.Slides[0])
+	closing := map[string]bool{"outro_stable": true, "full_view": true}
+	var tail []float64 // outro_stable at 6.5, full_view at 7.5 and 8
+	for _, kf := range s.Slides[0].Keyframes[len(s.Slides[0].Keyframes)-3:] {
+		if !closing[kf.Focus] {
+			t.Fatalf("expected the generated slide to close with outro_stable and full_view, got %+v", s.Slides[0].Keyframes)
+		}
+		tail = append(tail, kf.Time)
+	}
+
+	// Generated slides keep the keyframe at 0 and the zoom-out before the transition; the rest move
+	for _, offset := range []float64{0.25, -0.25} {
+		if err := s.Shift(1, offset); err != nil {
+			t.Fatalf("shift by %.2f: %v", offset, err)
+		}
+		got := keyframeTimes(s.Slides[0])
+		if got[0] != 0 || !timesEqual(got[len(got)-3:], tail) {
+			t.Errorf("shift by %.2f should keep the start and the closing %v: %v", offset, tail, got)
+		}
+		if offset > 0 && math.Abs(got[1]-before[1]-offset) > 1e-9 {
+			t.Errorf("shift by %.2f should move the inner keyframes: %v", offset, got)
+		}
+	}
+	if got := keyframeTimes(s.Slides[0]); !timesEqual(got, before) {
+		t.Errorf("shifting back should restore the times %v, got %v", before, got)
+	}
+	if issues := s.Lint(); len(issues) > 0 {
+		t.Errorf("a shifted generated slide should stay clean, got %v", issues)
+	}
+
+	// The last block may not be pushed into the zoom-out
+	last := before[len(before)-4]
+	if err := s.Shift(1, tail[0]-last); err == nil {
+		t.Error("expected an error for a shift onto outro_stable")
+	}
+}
+
+func TestStretch(t *testing.T) {
+	s := toolkitScenario()
+	if err := s.Stretch(30); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{8, 12, 10} {
+		if math.Abs(s.Slides[i].Duration-want) > 1e-9 {
+			t.Errorf("slide %d: expected duration %.2f, got %.2f", i+1, want, s.Slides[i].Duration)
+		}
+	}
+	if got := keyframeTimes(s.Slides[0]); !timesEqual(got, []float64{0, 4, 7.6}) {
+		t.Errorf("keyframes should scale with the slide: %v", got)
+	}
+
+	if err := s.Stretch(0); err == nil {
+		t.Error("expected an error for a zero total")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	s := toolkitScenario()
+	src := toolkitScenario()
+	src.Slides[1].Duration = 3
+	src.Slides[1].Ordering = "right_to_left"
+	src.Slides[1].Keyframes[1].Time = 1.5
+	src.Slides[1].Keyframes[1].Zoom = 3
+
+	if err := s.Merge(src, "", 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	got := s.Slides[1]
+	if got.Duration != 6 || got.Ordering != "right_to_left" {
+		t.Errorf("expected duration 6 and the donor ordering, got %.2f/%q", got.Duration, got.Ordering)
+	}
+	if times := keyframeTimes(got); !timesEqual(times, []float64{0, 3}) || got.Keyframes[1].Zoom != 3 {
+		t.Errorf("expected donor keyframes rescaled to the target duration, got %+v", got.Keyframes)
+	}
+
+	// The donor is not modified through shared keyframes
+	s.Slides[1].Keyframes[1].Zoom = 5
+	if src.Slides[1].Keyframes[1].Zoom != 3 {
+		t.Error("merge should copy keyframes")
+	}
+
+	short := toolkitScenario()
+	short.Slides = short.Slides[:2]
+	if err := s.Merge(short, "", 2, 3); err == nil {
+		t.Error("expected an error for a page the source scenario does not show")
+	}
+	if err := s.Merge(src, "", 5, 6); err == nil {
+		t.Error("expected an error for pages no slide shows")
+	}
+	legacy := toolkitScenario()
+	legacy.Version = LegacyScenarioVersion
+	if err := s.Merge(legacy, "", 1, 1); err == nil {
+		t.Error("expected an error for mixed coordinate systems")
+	}
+}
+
+func TestMerge_ByPage(t *testing.T) {
+	// The target was rendered with -pages 2-3 in reverse order and an extra input:
+	// slides are matched by the page they show, not by their position
+	s := toolkitScenario()
+	s.Slides[0].Input = "#3"
+	s.Slides[1].Input = "extra/deck.pdf#2"
+	s.Slides[2].Input = "#2"
+
+	src := toolkitScenario()
+	src.Slides[0].Input = "" // Page 1 by position
+	src.Slides[1].Input = "slide_2.png"
+	src.Slides[1].Keyframes[1].Zoom = 3
+	src.Slides[2].Input = "#3"
+	src.Slides[2].Keyframes = []Keyframe{{Time: 0, Focus: "full_view", Zoom: 1, Rect: Rectangle{W: 1, H: 1}}}
+
+	if err := s.Merge(src, "", 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if kfs := s.Slides[2].Keyframes; len(kfs) != 2 || kfs[1].Zoom != 3 {
+		t.Errorf("slide showing #2 should get the camera of the donor page 2, got %+v", kfs)
+	}
+	if kfs := s.Slides[0].Keyframes; len(kfs) != 1 {
+		t.Errorf("slide showing #3 should get the camera of the donor page 3, got %+v", kfs)
+	}
+	if kfs := s.Slides[1].Keyframes; len(kfs) != 2 || kfs[1].Zoom != 1.5 {
+		t.Errorf("a page of another input should be left alone, got %+v", kfs)
+	}
+
+	src.Slides[1].Input = "./extra/deck.pdf#2"
+	if err := s.Merge(src, "extra/deck.pdf", 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if kfs := s.Slides[1].Keyframes; kfs[1].Zoom != 3 {
+		t.Errorf("-input should select the pages of that file, got %+v", kfs)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	first, second, err := toolkitScenario().Split(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Slides) != 1 || len(second.Slides) != 2 {
+		t.Fatalf("expected 1+2 slides, got %d+%d", len(first.Slides), len(second.Slides))
+	}
+	if second.Slides[0].ID != 1 || second.Slides[0].Input != "#2" || second.Slides[1].ID != 2 {
+		t.Errorf("second part should be renumbered, got %+v", second.Slides)
+	}
+
+	// Slides without an input keep their page, camera settings are not shared
+	s := toolkitScenario()
+	s.Slides[2].Input = ""
+	s.Camera = &CameraSettings{Path: PathSpline, Inertia: &InertiaSettings{Mass: 1, Damping: 0.6}}
+	first, second, err = s.Split(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Slides[0].Input != "#3" {
+		t.Errorf("expected an explicit page for the renumbered slide, got %q", second.Slides[0].Input)
+	}
+	second.Camera.Inertia.Mass = 2
+	if first.Camera.Inertia.Mass != 1 || s.Camera.Inertia.Mass != 1 || first.Camera == s.Camera {
+		t.Error("split parts should copy the camera settings")
+	}
+
+	if _, _, err := toolkitScenario().Split(1); err == nil {
+		t.Error("expected an error for an empty first part")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := toolkitScenario()
+	if changes := Diff(a, toolkitScenario()); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+
+	b := toolkitScenario()
+	b.Slides[0].Keyframes[1].Zoom = 2.5
+	b.Slides[1].Keyframes = b.Slides[1].Keyframes[:1]
+	b.Slides = append(b.Slides, Slide{ID: 4, Input: "#4", Duration: 3})
+
+	want := []string{
+		"~ slides[1].keyframes[2].zoom: 2 -> 2.5",
+		"- slides[2].keyframes[2]: 3.00s region_1 zoom 1.5 {0.1 0.1 0.6 0.6}",
+		"+ slides[4]: #4 3.00s, 0 keyframes",
+	}
+	changes := Diff(a, b)
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %v", len(want), changes)
+	}
+	for i, c := range changes {
+		if c.String() != want[i] {
+			t.Errorf("change %d: expected %q, got %q", i, want[i], c)
+		}
+	}
+}
@@ -27,6 +27,9 @@ func ReadScenario(path string) (*Scenario, error) {
 	if err := yaml.Unmarshal(data, &scenario); err != nil {
 		return nil, err
 	}
+	if err := scenario.checkVersion(); err != nil {
+		return nil, err
+	}
 
 	return &scenario, nil
 }
@@ -2,15 +2,20 @@ package effects
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/ivlev/pdf2video/internal/config"
 	"github.com/ivlev/pdf2video/internal/director"
+	"github.com/ivlev/pdf2video/internal/renderer"
 	"github.com/ivlev/pdf2video/internal/system"
 )
 
+// maxCanvasScale ограничивает увеличение холста при кадрировании (память zoompan)
+const maxCanvasScale = 4.0
+
 type Effect interface {
 	GenerateFilter(params config.SegmentParams) string
 	GenerateKeyframes(params config.SegmentParams) []director.Keyframe
@@ -19,6 +24,14 @@ type Effect interface {
 type DefaultEffect struct{}
 
 func (e *DefaultEffect) GenerateFilter(p config.SegmentParams) string {
+	if strings.ToLower(p.ZoomMode) == ZoomModeScroll {
+		return NewScrollEffect().GenerateFilter(p)
+	}
+	if p.Reframe {
+		// Без полей: камера едет по кадрам панорамы, зум не ниже кадрирования по высоте
+		return keyframeFilter(p, e.GenerateKeyframes(p))
+	}
+
 	mode := strings.ToLower(p.ZoomMode)
 	if mode == "random" || mode == "out-random" {
 		modes := []string{"center", "top-left", "top-right", "bottom-left", "bottom-right"}
@@ -74,26 +87,25 @@ func (e *DefaultEffect) GenerateFilter(p config.SegmentParams) string {
 	zFormula := fmt.Sprintf("if(lte(on,%f), 1.0+(%f*on), if(lte(on,%f), %f, if(lte(on,%f), %f-(%f-1.0)*(on-%f)/(%f-%f), 1.0)))",
 		onPeak, zSpeed, outroStart, actualPeak, fActive, actualPeak, actualPeak, outroStart, fActive, outroStart)
 
-	aspectFilter := fmt.Sprintf(
-		"scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2",
-		p.Width*2, p.Height*2, p.Width*2, p.Height*2,
-	)
-
 	zoomFilter := fmt.Sprintf(
 		"zoompan=z='%s':d=%d:s=%dx%d:x='%s':y='%s':fps=%d",
 		zFormula, int(fTotal), p.Width, p.Height, zoomX, zoomY, p.FPS,
 	)
 
-	if p.Debug && system.CheckFilterSupport("drawtext") {
-		textFilter := fmt.Sprintf("drawtext=text='Slide %d':x=10:y=10:fontsize=24:fontcolor=yellow:box=1:boxcolor=black@0.5", p.PageIndex+1)
-		return fmt.Sprintf("%s,%s,%s,scale=%d:%d", aspectFilter, zoomFilter, textFilter, p.Width, p.Height)
+	if p.Debug {
+		if textFilter := DebugFilter(p); textFilter != "" {
+			return fmt.Sprintf("%s,%s,%s,scale=%d:%d", aspectFilter(p), zoomFilter, textFilter, p.Width, p.Height)
+		}
 	}
 
-	return fmt.Sprintf("%s,%s,scale=%d:%d", aspectFilter, zoomFilter, p.Width, p.Height)
+	return fmt.Sprintf("%s,%s,scale=%d:%d", aspectFilter(p), zoomFilter, p.Width, p.Height)
 }
 
 func (e *DefaultEffect) GenerateKeyframes(p config.SegmentParams) []director.Keyframe {
 	mode := strings.ToLower(p.ZoomMode)
+	if mode == ZoomModeScroll {
+		return NewScrollEffect().GenerateKeyframes(p)
+	}
 	if mode == "random" || mode == "out-random" {
 		modes := []string{"center", "top-left", "top-right", "bottom-left", "bottom-right"}
 		r := rand.New(rand.NewSource(time.Now().UnixNano() + int64(p.PageIndex*99)))
@@ -129,6 +141,10 @@ func (e *DefaultEffect) GenerateKeyframes(p config.SegmentParams) []director.Key
 		outroStart = onPeak
 	}
 
+	if p.Reframe {
+		return reframeSweep(p, mode, onPeak/fFPS, outroStart/fFPS, fActive/fFPS, actualPeak)
+	}
+
 	// We create fake rects simulating the view center coordinate
 	var endX, endY float64
 	w, h := float64(p.Width), float64(p.Height)
@@ -146,15 +162,111 @@ func (e *DefaultEffect) GenerateKeyframes(p config.SegmentParams) []director.Key
 		endX, endY = w/2-(w/actualPeak/2), h/2-(h/actualPeak/2)
 	}
 
-	// Center point is the view top-left + half view width
-	cenX := func(x, z float64) int { return int(x + (w/z)/2) }
-	cenY := func(y, z float64) int { return int(y + (h/z)/2) }
+	full := director.Rectangle{X: 0, Y: 0, W: w, H: h}
+	peak := director.Rectangle{X: endX, Y: endY, W: w / actualPeak, H: h / actualPeak}
 
 	frames := []director.Keyframe{
-		{Time: 0, Focus: "start", Zoom: 1.0, Rect: director.Rectangle{X: cenX(0, 1.0) - int(w)/2, Y: cenY(0, 1.0) - int(h)/2, W: p.Width, H: p.Height}},
-		{Time: onPeak / fFPS, Focus: "peak", Zoom: actualPeak, Rect: director.Rectangle{X: cenX(endX, actualPeak) - int(w/actualPeak)/2, Y: cenY(endY, actualPeak) - int(h/actualPeak)/2, W: int(w / actualPeak), H: int(h / actualPeak)}},
-		{Time: outroStart / fFPS, Focus: "hold_end", Zoom: actualPeak, Rect: director.Rectangle{X: cenX(endX, actualPeak) - int(w/actualPeak)/2, Y: cenY(endY, actualPeak) - int(h/actualPeak)/2, W: int(w / actualPeak), H: int(h / actualPeak)}},
-		{Time: fActive / fFPS, Focus: "outro_end", Zoom: 1.0, Rect: director.Rectangle{X: cenX(0, 1.0) - int(w)/2, Y: cenY(0, 1.0) - int(h)/2, W: p.Width, H: p.Height}},
+		{Time: 0, Focus: "start", Zoom: 1.0, Rect: full},
+		{Time: onPeak / fFPS, Focus: "peak", Zoom: actualPeak, Rect: peak},
+		{Time: outroStart / fFPS, Focus: "hold_end", Zoom: actualPeak, Rect: peak},
+		{Time: fActive / fFPS, Focus: "outro_end", Zoom: 1.0, Rect: full},
 	}
 	return frames
 }
+
+// reframeSweep проводит камеру, кадрирующую страницу по высоте, через всю страницу:
+// от угла из режима зума к противоположному. Темп тот же, что у обычного зума: проезд
+// занимает время наезда со скоростью -zoom-speed, камера приближается от кадрирования
+// до пика, держит кадр и возвращается к кадрированию к началу перехода.
+func reframeSweep(p config.SegmentParams, mode string, peakTime, outroStart, activeEnd, peakZoom float64) []director.Keyframe {
+	page := pageRect(p)
+	cover := director.CoverZoom(page, p.Width, p.Height)
+	peakTime = math.Min(peakTime, activeEnd)
+	outroStart = math.Min(math.Max(outroStart, peakTime), activeEnd)
+	left, right := page.X, page.X+page.W
+	top, middle, bottom := page.Y, page.Y+page.H/2, page.Y+page.H
+
+	fromX, fromY, toX, toY := left, middle, right, middle
+	switch mode {
+	case "top-left":
+		fromX, fromY, toX, toY = left, top, right, bottom
+	case "top-right":
+		fromX, fromY, toX, toY = right, top, left, bottom
+	case "bottom-left":
+		fromX, fromY, toX, toY = left, bottom, right, top
+	case "bottom-right":
+		fromX, fromY, toX, toY = right, bottom, left, top
+	}
+
+	from := director.Rectangle{X: fromX, Y: fromY}
+	to := director.Rectangle{X: toX, Y: toY}
+	frames := []director.Keyframe{
+		{Time: 0, Focus: "start", Zoom: cover, Rect: from},
+		{Time: peakTime, Focus: "sweep_end", Zoom: cover * peakZoom, Rect: to},
+		{Time: outroStart, Focus: "hold_end", Zoom: cover * peakZoom, Rect: to},
+		{Time: activeEnd, Focus: "outro_end", Zoom: cover, Rect: to},
+	}
+	if p.Duration > activeEnd {
+		frames = append(frames, director.Keyframe{Time: p.Duration, Focus: "fade_end", Zoom: cover, Rect: to})
+	}
+	return director.ReframeKeyframes(frames, p.Width, p.Height, page)
+}
+
+// pageRect возвращает положение страницы в кадре (вписанной с полями)
+func pageRect(p config.SegmentParams) director.Rectangle {
+	return director.PageRect(p.SourceWidth, p.SourceHeight, p.Width, p.Height)
+}
+
+// keyframeFilter строит цепочку фильтров, в которой камера zoompan следует ключевым кадрам
+func keyframeFilter(p config.SegmentParams, keyframes []director.Keyframe) string {
+	filters := []string{aspectFilter(p)}
+	if zoomFilter := zoomPanFilter(p, keyframes); zoomFilter != "" {
+		filters = append(filters, zoomFilter)
+	}
+	if p.Debug {
+		if textFilter := DebugFilter(p); textFilter != "" {
+			filters = append(filters, textFilter)
+		}
+	}
+	filters = append(filters, fmt.Sprintf("scale=%d:%d", p.Width, p.Height))
+	return strings.Join(filters, ",")
+}
+
+// DebugFilter returns the drawtext overlay with the slide number and timestamp that
+// -debug puts on every slide, whichever effect moves its camera, or "" when FFmpeg lacks
+// drawtext. Natively rendered slides have no zoompan chain and get it as their whole filter.
+func DebugFilter(p config.SegmentParams) string {
+	if !system.CheckFilterSupport("drawtext") {
+		return ""
+	}
+	return debugTextFilter(p)
+}
+
+func debugTextFilter(p config.SegmentParams) string {
+	return fmt.Sprintf("drawtext=text='Slide %d | Time %%{pts\\:hms}':x=10:y=10:fontsize=24:fontcolor=yellow:box=1:boxcolor=black@0.5", p.PageIndex+1)
+}
+
+// zoomPanFilter строит zoompan по ключевым кадрам. При кадрировании и прокрутке камера
+// должна точно попадать на страницу увеличенного холста, поэтому сдвиг переводится в пиксели входа.
+func zoomPanFilter(p config.SegmentParams, keyframes []director.Keyframe) string {
+	if p.Reframe || strings.ToLower(p.ZoomMode) == ZoomModeScroll {
+		return renderer.GenerateCanvasZoomPanFilter(keyframes, p.Duration, p.FPS, p.Width, p.Height)
+	}
+	return renderer.GenerateZoomPanFilter(keyframes, p.Duration, p.FPS, p.Width, p.Height)
+}
+
+// aspectFilter вписывает страницу в увеличенный холст формата кадра (2x для качества зума).
+// При кадрировании и прокрутке холст увеличивается сильнее, чтобы кадр по высоте
+// (или ширине) страницы оставался четким.
+func aspectFilter(p config.SegmentParams) string {
+	scale := 2.0
+	if p.Reframe || strings.ToLower(p.ZoomMode) == ZoomModeScroll {
+		scale = math.Max(scale, math.Min(2*director.CoverZoom(pageRect(p), p.Width, p.Height), maxCanvasScale))
+	}
+	w := int(math.Round(float64(p.Width)*scale/2)) * 2
+	h := int(math.Round(float64(p.Height)*scale/2)) * 2
+	return fmt.Sprintf(
+		"scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2",
+		w, h, w, h,
+	)
+}
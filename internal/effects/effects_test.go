@@ -0,0 +1,39 @@
+package effects
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/config"
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+func TestReframeSweepFollowsZoomSpeed(t *testing.T) {
+	p := config.SegmentParams{
+		Width: 720, Height: 1280, FPS: 30,
+		Duration: 20, FadeDuration: 0.5, OutroDuration: 1,
+		ZoomMode: "center", ZoomSpeed: 0.005,
+		SourceWidth: 1920, SourceHeight: 1080, Reframe: true,
+	}
+	cover := director.CoverZoom(pageRect(p), p.Width, p.Height)
+
+	// The sweep ends when the zoom peaks: 0.5 / 0.005 = 100 frames
+	frames := (&DefaultEffect{}).GenerateKeyframes(p)
+	if frames[1].Focus != "sweep_end" || frames[1].Time < 3.3 || frames[1].Time > 3.4 {
+		t.Fatalf("expected the sweep to end at the zoom peak, got %+v", frames[1])
+	}
+	if frames[0].Zoom < cover || frames[1].Zoom < cover*1.49 {
+		t.Errorf("expected zoom from the cover %.2f to its peak, got %.2f and %.2f", cover, frames[0].Zoom, frames[1].Zoom)
+	}
+
+	// A faster zoom makes a faster sweep
+	p.ZoomSpeed = 0.01
+	if fast := (&DefaultEffect{}).GenerateKeyframes(p); fast[1].Time >= frames[1].Time {
+		t.Errorf("expected a faster sweep, got %.2f vs %.2f", fast[1].Time, frames[1].Time)
+	}
+
+	// Only the bar-free modes convert the pan to input pixels
+	if f := (&DefaultEffect{}).GenerateFilter(p); !strings.Contains(f, "*iw/720-(iw/zoom)/2") {
+		t.Errorf("expected a canvas pan when reframing: %s", f)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/ivlev/pdf2video/internal/config"
 	"github.com/ivlev/pdf2video/internal/director"
 	"github.com/ivlev/pdf2video/internal/renderer"
-	"github.com/ivlev/pdf2video/internal/system"
 )
 
 // ScenarioEffect uses a YAML scenario for camera movement
@@ -31,6 +30,149 @@ func (e *ScenarioEffect) GenerateFilter(p config.SegmentParams) string {
 			p.Width, p.Height, p.Width, p.Height, p.Width, p.Height)
 	}
 
+	slide := e.Scenario.Slides[p.PageIndex]
+	switch slide.CameraEffect() {
+	case director.SlideEffectZoom:
+		return (&DefaultEffect{}).GenerateFilter(slideZoomParams(slide, p))
+	case director.SlideEffectStatic:
+		return keyframeFilter(p, e.GenerateKeyframes(p))
+	}
+
+	scaledKeyframes := e.slideKeyframes(p)
+
+	// zoompan интерполирует линейно, поэтому криволинейный путь передаем плотной сеткой кадров
+	scaledKeyframes = renderer.ResamplePath(scaledKeyframes, e.PathOptions(), p.Width, p.Height)
+
+	// Используем генератор фильтра с масштабированной длительностью и кадрами
+	zoomFilter := zoomPanFilter(p, scaledKeyframes)
+
+	// Aspect ratio handling (2x scale for better zoom quality)
+	canvasFilter := aspectFilter(p)
+
+	if !p.Debug {
+		if zoomFilter == "" {
+			return fmt.Sprintf("%s,scale=%d:%d", canvasFilter, p.Width, p.Height)
+		}
+		return fmt.Sprintf("%s,%s,scale=%d:%d", canvasFilter, zoomFilter, p.Width, p.Height)
+	}
+
+	// Режим отладки: собираем цепочку фильтров динамически
+	filters := []string{canvasFilter}
+
+	if zoomFilter != "" {
+		filters = append(filters, zoomFilter)
+	}
+
+	// Статистика отображается ПОСЛЕ масштабирования/зума для читаемости
+	if textFilter := DebugFilter(p); textFilter != "" {
+		filters = append(filters, textFilter)
+	}
+
+	filters = append(filters, fmt.Sprintf("scale=%d:%d", p.Width, p.Height))
+
+	return strings.Join(filters, ",")
+}
+
+// PathOptions returns the camera path settings of the scenario
+func (e *ScenarioEffect) PathOptions() renderer.PathOptions {
+	if e.Scenario == nil {
+		return renderer.PathOptionsFor(nil)
+	}
+	return renderer.PathOptionsFor(e.Scenario.Camera)
+}
+
+func (e *ScenarioEffect) GenerateKeyframes(p config.SegmentParams) []director.Keyframe {
+	if e.Scenario == nil || p.PageIndex >= len(e.Scenario.Slides) {
+		return []director.Keyframe{}
+	}
+	slide := e.Scenario.Slides[p.PageIndex]
+	switch slide.CameraEffect() {
+	case director.SlideEffectZoom:
+		return (&DefaultEffect{}).GenerateKeyframes(slideZoomParams(slide, p))
+	case director.SlideEffectStatic:
+		if p.Reframe {
+			return director.ReframeKeyframes(staticKeyframes(p), p.Width, p.Height, pageRect(p))
+		}
+		return staticKeyframes(p)
+	}
+	return e.slideKeyframes(p)
+}
+
+// SlideAnnotations returns the annotations of the slide prepared for rendering:
+// geometry in viewport pixels and times scaled to the actual segment duration,
+// the same way the keyframes are
+func (e *ScenarioEffect) SlideAnnotations(p config.SegmentParams) []director.TimedAnnotation {
+	if e.Scenario == nil || p.PageIndex >= len(e.Scenario.Slides) {
+		return nil
+	}
+	slide := e.Scenario.Slides[p.PageIndex]
+	timeScale := 1.0
+	if slide.Duration > 0 {
+		timeScale = p.Duration / slide.Duration
+	}
+
+	page := pageRect(p)
+	annotations := slide.Annotations()
+	for i := range annotations {
+		annotations[i].Annotation = annotations[i].Denormalize(page)
+		annotations[i].Start *= timeScale
+		annotations[i].End *= timeScale
+	}
+	return annotations
+}
+
+// slideZoomParams applies the zoom mode of the slide to the segment parameters
+func slideZoomParams(slide director.Slide, p config.SegmentParams) config.SegmentParams {
+	if slide.ZoomMode != "" {
+		p.ZoomMode = slide.ZoomMode
+	}
+	return p
+}
+
+// staticKeyframes shows the whole page for the entire slide
+func staticKeyframes(p config.SegmentParams) []director.Keyframe {
+	full := director.Rectangle{X: 0, Y: 0, W: float64(p.Width), H: float64(p.Height)}
+	return []director.Keyframe{
+		{Time: 0, Focus: "full_view", Zoom: 1.0, Rect: full},
+		{Time: p.Duration, Focus: "full_view", Zoom: 1.0, Rect: full},
+	}
+}
+
+// slideKeyframes returns the slide keyframes scaled to the actual segment duration,
+// with the guaranteed return to 1:1 before the transition.
+// Both the zoompan filter and the native renderer use this exact camera path.
+func (e *ScenarioEffect) slideKeyframes(p config.SegmentParams) []director.Keyframe {
+	keyframes := e.focusKeyframes(p)
+
+	// Кадрирование без полей: камера не выходит за страницу, включая общий план
+	if p.Reframe {
+		keyframes = director.ReframeKeyframes(keyframes, p.Width, p.Height, pageRect(p))
+	}
+	// Повернутый кадр не должен открывать область за страницей
+	return director.FitRotatedKeyframes(keyframes, p.Width, p.Height, pageRect(p))
+}
+
+// EmphasisKeyframes returns the keyframes that drive the focus emphasis of the slide:
+// the camera timing with the focused blocks (FocusArea) as written in the scenario, not
+// the camera windows widened by padding or reframing, and the run-wide emphasis mode
+// applied to keyframes without their own.
+// It returns nil when no keyframe of the slide is emphasized.
+func (e *ScenarioEffect) EmphasisKeyframes(p config.SegmentParams) []director.Keyframe {
+	if e.Scenario == nil || p.PageIndex >= len(e.Scenario.Slides) ||
+		e.Scenario.Slides[p.PageIndex].CameraEffect() != director.SlideEffectKeyframes {
+		return nil
+	}
+	keyframes := e.focusKeyframes(p)
+	for _, kf := range keyframes {
+		if kf.HasEmphasis() {
+			return keyframes
+		}
+	}
+	return nil
+}
+
+// focusKeyframes scales the slide keyframes to the segment and adds the return to 1:1
+func (e *ScenarioEffect) focusKeyframes(p config.SegmentParams) []director.Keyframe {
 	slide := e.Scenario.Slides[p.PageIndex]
 
 	// Масштабируем ключевые кадры под реальную длительность (рассчитанную движком)
@@ -40,9 +182,18 @@ func (e *ScenarioEffect) GenerateFilter(p config.SegmentParams) string {
 		timeScale = p.Duration / slide.Duration
 	}
 
+	// Прямоугольники сценария заданы относительно страницы: переводим в пиксели кадра
+	page := pageRect(p)
 	for i, kf := range slide.Keyframes {
 		scaledKeyframes[i] = kf
 		scaledKeyframes[i].Time *= timeScale
+		scaledKeyframes[i].Rect = kf.Rect.Denormalize(page)
+		if kf.FocusRect != (director.Rectangle{}) {
+			scaledKeyframes[i].FocusRect = kf.FocusRect.Denormalize(page)
+		}
+		if kf.Emphasis == "" {
+			scaledKeyframes[i].Emphasis = p.Emphasis
+		}
 	}
 
 	// ДОРАБОТКА: Гарантированный возврат к 1:1 за OutroDuration до начала перехода
@@ -54,11 +205,17 @@ func (e *ScenarioEffect) GenerateFilter(p config.SegmentParams) string {
 		// 1. Находим текущий зум в момент начала зум-аута (интерполяция по существующим кадрам)
 		// Для простоты берем последний кадр перед zoomOutStart
 		lastZoom := 1.0
-		lastRect := director.Rectangle{X: 0, Y: 0, W: p.Width, H: p.Height}
+		lastRect := director.Rectangle{X: 0, Y: 0, W: float64(p.Width), H: float64(p.Height)}
+		lastFocus := director.Rectangle{}
+		lastEmphasis := ""
+		lastRotation := 0.0
 		for _, kf := range scaledKeyframes {
 			if kf.Time <= zoomOutStart {
 				lastZoom = kf.Zoom
 				lastRect = kf.Rect
+				lastFocus = kf.FocusRect
+				lastEmphasis = kf.Emphasis
+				lastRotation = kf.Rotation
 			} else {
 				break
 			}
@@ -66,10 +223,13 @@ func (e *ScenarioEffect) GenerateFilter(p config.SegmentParams) string {
 
 		// 2. Инъекция кадра начала зум-аута (чтобы зафиксировать текущее положение)
 		scaledKeyframes = append(scaledKeyframes, director.Keyframe{
-			Time:  zoomOutStart,
-			Focus: "zoom_out_start",
-			Zoom:  lastZoom,
-			Rect:  lastRect,
+			Time:      zoomOutStart,
+			Focus:     "zoom_out_start",
+			Zoom:      lastZoom,
+			Rect:      lastRect,
+			FocusRect: lastFocus,
+			Rotation:  lastRotation, // Камера выравнивается вместе с зум-аутом
+			Emphasis:  lastEmphasis, // Выделение держится до зум-аута и гаснет вместе с ним
 		})
 
 		// 3. Инъекция кадра завершения зум-аута (1:1 за 1.5с)
@@ -77,7 +237,7 @@ func (e *ScenarioEffect) GenerateFilter(p config.SegmentParams) string {
 			Time:  fadeStart,
 			Focus: "full_view",
 			Zoom:  1.0,
-			Rect:  director.Rectangle{X: 0, Y: 0, W: p.Width, H: p.Height},
+			Rect:  director.Rectangle{X: 0, Y: 0, W: float64(p.Width), H: float64(p.Height)},
 		})
 
 		// 4. Удержание 1:1 во время перехода
@@ -85,7 +245,7 @@ func (e *ScenarioEffect) GenerateFilter(p config.SegmentParams) string {
 			Time:  p.Duration,
 			Focus: "full_view",
 			Zoom:  1.0,
-			Rect:  director.Rectangle{X: 0, Y: 0, W: p.Width, H: p.Height},
+			Rect:  director.Rectangle{X: 0, Y: 0, W: float64(p.Width), H: float64(p.Height)},
 		})
 
 		// 5. Обеспечиваем сортировку по времени
@@ -94,43 +254,5 @@ func (e *ScenarioEffect) GenerateFilter(p config.SegmentParams) string {
 		})
 	}
 
-	// Используем генератор фильтра с масштабированной длительностью и кадрами
-	zoomFilter := renderer.GenerateZoomPanFilter(scaledKeyframes, p.Duration, p.FPS, p.Width, p.Height)
-
-	// Aspect ratio handling (2x scale for better zoom quality)
-	aspectFilter := fmt.Sprintf(
-		"scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2",
-		p.Width*2, p.Height*2, p.Width*2, p.Height*2,
-	)
-
-	if !p.Debug {
-		if zoomFilter == "" {
-			return fmt.Sprintf("%s,scale=%d:%d", aspectFilter, p.Width, p.Height)
-		}
-		return fmt.Sprintf("%s,%s,scale=%d:%d", aspectFilter, zoomFilter, p.Width, p.Height)
-	}
-
-	// Режим отладки: собираем цепочку фильтров динамически
-	filters := []string{aspectFilter}
-
-	if zoomFilter != "" {
-		filters = append(filters, zoomFilter)
-	}
-
-	if system.CheckFilterSupport("drawtext") {
-		// Статистика отображается ПОСЛЕ масштабирования/зума для читаемости
-		textFilter := fmt.Sprintf("drawtext=text='Slide %d | Time %%{pts\\:hms}':x=10:y=10:fontsize=24:fontcolor=yellow:box=1:boxcolor=black@0.5", p.PageIndex+1)
-		filters = append(filters, textFilter)
-	}
-
-	filters = append(filters, fmt.Sprintf("scale=%d:%d", p.Width, p.Height))
-
-	return strings.Join(filters, ",")
-}
-
-func (e *ScenarioEffect) GenerateKeyframes(p config.SegmentParams) []director.Keyframe {
-	if e.Scenario == nil || p.PageIndex >= len(e.Scenario.Slides) {
-		return []director.Keyframe{}
-	}
-	return e.Scenario.Slides[p.PageIndex].Keyframes
+	return scaledKeyframes
 }
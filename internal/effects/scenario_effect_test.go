@@ -0,0 +1,198 @@
+package effects
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/config"
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+func TestScenarioEffectScalesToOutput(t *testing.T) {
+	scenario := &director.Scenario{
+		Version: director.ScenarioVersion,
+		Slides: []director.Slide{{
+			ID:       1,
+			Duration: 5,
+			Keyframes: []director.Keyframe{
+				{Time: 0, Focus: "full_view", Zoom: 1, Rect: director.Rectangle{X: 0, Y: 0, W: 1, H: 1}},
+				{Time: 2, Focus: "region_1", Zoom: 2, Rect: director.Rectangle{X: 0.5, Y: 0.25, W: 0.25, H: 0.5}},
+			},
+		}},
+	}
+	e := NewScenarioEffect(scenario)
+
+	// The same page-normalized scenario lands on the same page content at any output size
+	for _, size := range [][2]int{{1280, 720}, {1920, 1080}, {720, 1280}} {
+		p := config.SegmentParams{
+			Width: size[0], Height: size[1], FPS: 30, Duration: 5,
+			SourceWidth: 1600, SourceHeight: 900,
+		}
+		page := director.PageRect(p.SourceWidth, p.SourceHeight, p.Width, p.Height)
+
+		frames := e.GenerateKeyframes(p)
+		r := frames[1].Rect
+		cx := (r.X + r.W/2 - page.X) / page.W
+		cy := (r.Y + r.H/2 - page.Y) / page.H
+		if math.Abs(cx-0.625) > 1e-9 || math.Abs(cy-0.5) > 1e-9 {
+			t.Errorf("%dx%d: expected focus at page (0.625, 0.5), got (%.3f, %.3f)", p.Width, p.Height, cx, cy)
+		}
+	}
+}
+
+func TestScenarioEffectSlideEffects(t *testing.T) {
+	scenario := &director.Scenario{
+		Version: director.ScenarioVersion,
+		Slides: []director.Slide{
+			{ID: 1, Duration: 5, Effect: director.SlideEffectStatic},
+			{ID: 2, Duration: 5, ZoomMode: "top-left"}, // Zoom mode alone selects Ken Burns
+		},
+	}
+	e := NewScenarioEffect(scenario)
+	p := config.SegmentParams{Width: 1280, Height: 720, FPS: 30, Duration: 5, FadeDuration: 0.5, OutroDuration: 1, ZoomMode: "center"}
+
+	for _, kf := range e.GenerateKeyframes(p) {
+		if kf.Zoom != 1.0 {
+			t.Errorf("static slide: expected zoom 1.0, got %.2f at %.2fs", kf.Zoom, kf.Time)
+		}
+	}
+
+	p.PageIndex = 1
+	want := (&DefaultEffect{}).GenerateKeyframes(config.SegmentParams{Width: 1280, Height: 720, FPS: 30, Duration: 5, FadeDuration: 0.5, OutroDuration: 1, ZoomMode: "top-left", PageIndex: 1})
+	got := e.GenerateKeyframes(p)
+	if len(got) != len(want) {
+		t.Fatalf("zoom slide: expected %d Ken Burns keyframes, got %d", len(want), len(got))
+	}
+	for i := range got {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("zoom slide: keyframe %d differs: %+v vs %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestScenarioEffectSlideAnnotations(t *testing.T) {
+	scenario := &director.Scenario{
+		Version: director.ScenarioVersion,
+		Slides: []director.Slide{{
+			ID:       1,
+			Duration: 5,
+			Keyframes: []director.Keyframe{{
+				Time: 2, Zoom: 1, Rect: director.Rectangle{W: 1, H: 1},
+				Annotations: []director.Annotation{
+					{Type: director.AnnotationBox, Rect: director.Rectangle{X: 0.5, Y: 0.5, W: 0.25, H: 0.25}, Disappear: 1},
+				},
+			}},
+		}},
+	}
+	// 4:3 page letterboxed into 1280x720: page area {160, 0, 960, 720}; the slide is stretched 2x
+	p := config.SegmentParams{Width: 1280, Height: 720, FPS: 30, Duration: 10, SourceWidth: 800, SourceHeight: 600}
+
+	annotations := NewScenarioEffect(scenario).SlideAnnotations(p)
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %d", len(annotations))
+	}
+	a := annotations[0]
+	if a.Rect != (director.Rectangle{X: 640, Y: 360, W: 240, H: 180}) {
+		t.Errorf("expected the box in viewport pixels, got %+v", a.Rect)
+	}
+	if a.Start != 4 || a.End != 6 {
+		t.Errorf("expected times scaled with the slide (4-6), got %.2f-%.2f", a.Start, a.End)
+	}
+	if scenario.Slides[0].Keyframes[0].Annotations[0].Rect.X != 0.5 {
+		t.Error("preparing annotations must not modify the scenario")
+	}
+}
+
+func TestScenarioEffectEmphasisKeyframes(t *testing.T) {
+	block := director.Rectangle{X: 0.25, Y: 0.25, W: 0.5, H: 0.5}
+	scenario := &director.Scenario{
+		Version: director.ScenarioVersion,
+		Slides: []director.Slide{
+			{ID: 1, Duration: 6, Keyframes: []director.Keyframe{
+				{Time: 0, Zoom: 1, Rect: director.Rectangle{W: 1, H: 1}, Emphasis: director.EmphasisNone},
+				{Time: 2, Zoom: 1, Rect: block},
+			}},
+			{ID: 2, Duration: 6, Keyframes: []director.Keyframe{
+				{Time: 2, Zoom: 1, Rect: block, Emphasis: director.EmphasisSpotlight,
+					FocusRect: director.Rectangle{X: 0.5, Y: 0.5, W: 0.25, H: 0.25}},
+			}},
+		},
+	}
+	e := NewScenarioEffect(scenario)
+	p := config.SegmentParams{Width: 1280, Height: 720, FPS: 30, Duration: 6, FadeDuration: 0.5, OutroDuration: 1,
+		SourceWidth: 1280, SourceHeight: 720, Reframe: true}
+
+	if got := e.EmphasisKeyframes(p); got != nil {
+		t.Errorf("expected no emphasis without a mode, got %+v", got)
+	}
+
+	// The run-wide mode fills keyframes without their own, an explicit none stays
+	p.Emphasis = director.EmphasisDim
+	got := e.EmphasisKeyframes(p)
+	if len(got) < 3 || got[0].Emphasis != director.EmphasisNone || got[1].Emphasis != director.EmphasisDim {
+		t.Fatalf("unexpected emphasis keyframes %+v", got)
+	}
+	// Focus rectangles are not widened by reframing, the emphasis holds until the zoom out
+	if got[1].Rect != (director.Rectangle{X: 320, Y: 180, W: 640, H: 360}) {
+		t.Errorf("expected the scenario focus rectangle, got %+v", got[1].Rect)
+	}
+	if got[2].Focus != "zoom_out_start" || got[2].Emphasis != director.EmphasisDim {
+		t.Errorf("expected the emphasis to hold until the zoom out, got %+v", got[2])
+	}
+
+	p.PageIndex = 1
+	p.Emphasis = ""
+	got = e.EmphasisKeyframes(p)
+	if len(got) == 0 || got[0].Emphasis != director.EmphasisSpotlight {
+		t.Fatalf("expected the keyframe spotlight, got %+v", got)
+	}
+	// The focused block is converted to frame pixels along with the camera rectangle
+	if got[0].FocusArea() != (director.Rectangle{X: 640, Y: 360, W: 320, H: 180}) || got[1].FocusArea() != got[0].FocusArea() {
+		t.Errorf("expected the focused block in frame pixels until the zoom out, got %+v", got)
+	}
+}
+
+func TestScenarioEffectRotation(t *testing.T) {
+	scenario := &director.Scenario{
+		Version: director.ScenarioVersion,
+		Slides: []director.Slide{{ID: 1, Duration: 6, Keyframes: []director.Keyframe{
+			{Time: 0, Focus: "full_view", Zoom: 1, Rect: director.Rectangle{W: 1, H: 1}, Rotation: -2},
+			{Time: 3, Focus: "region_1", Zoom: 1.5, Rect: director.Rectangle{X: 0, Y: 0, W: 0.3, H: 0.3}, Rotation: 3},
+		}}},
+	}
+	e := NewScenarioEffect(scenario)
+	p := config.SegmentParams{Width: 1280, Height: 720, FPS: 30, Duration: 6, FadeDuration: 0.5, OutroDuration: 1,
+		SourceWidth: 720, SourceHeight: 1280}
+	page := director.PageRect(p.SourceWidth, p.SourceHeight, p.Width, p.Height)
+
+	frames := e.GenerateKeyframes(p)
+	if frames[0].Rotation != -2 || frames[1].Rotation != 3 {
+		t.Fatalf("expected the rotations to be kept, got %+v", frames)
+	}
+	// The crooked full view of a portrait scan is widened onto the page
+	if r := frames[0].Rect; frames[0].Zoom <= 1 || r.X < page.X || r.X+r.W > page.X+page.W {
+		t.Errorf("rotated full view should stay on the page %+v, got zoom %.2f %+v", page, frames[0].Zoom, r)
+	}
+	// The camera rolls back with the zoom-out before the transition
+	if last := frames[len(frames)-1]; last.Rotation != 0 {
+		t.Errorf("expected a level camera at the end, got %.1f", last.Rotation)
+	}
+	for _, kf := range frames {
+		if kf.Focus == "zoom_out_start" && kf.Rotation != 3 {
+			t.Errorf("zoom-out should start from the last rotation, got %.1f", kf.Rotation)
+		}
+	}
+
+	if filter := e.GenerateFilter(p); !strings.Contains(filter, "rotate=a=") {
+		t.Errorf("expected a rotate filter: %s", filter)
+	}
+}
+
+func TestDebugTextFilter(t *testing.T) {
+	f := debugTextFilter(config.SegmentParams{PageIndex: 2})
+	if !strings.HasPrefix(f, "drawtext=") || !strings.Contains(f, "Slide 3 | Time %{pts\\:hms}") {
+		t.Errorf("debug filter = %q, want the slide number and timestamp drawtext", f)
+	}
+}
@@ -0,0 +1,124 @@
+package effects
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ivlev/pdf2video/internal/config"
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+// ZoomModeScroll — режим прокрутки высоких страниц (инфографика, скриншоты веб-страниц)
+const ZoomModeScroll = "scroll"
+
+const (
+	defaultScrollPause = 1.5  // Пауза на найденном блоке (сек)
+	scrollEdgeHold     = 0.1  // Доля активного времени на удержание в начале и в конце страницы
+	maxPauseShare      = 0.5  // Паузы занимают не больше половины времени прокрутки
+	minStopGap         = 0.25 // Остановки ближе этой доли высоты кадра объединяются
+)
+
+// ScrollEffect вписывает страницу по ширине кадра и прокручивает ее сверху вниз.
+// Скорость прокрутки постоянна и подбирается под длительность слайда; если в
+// параметрах сегмента переданы найденные блоки (Regions), камера на них останавливается.
+type ScrollEffect struct {
+	Pause float64 // Длительность остановки на блоке (0 — по умолчанию)
+}
+
+// NewScrollEffect creates a new ScrollEffect
+func NewScrollEffect() *ScrollEffect {
+	return &ScrollEffect{Pause: defaultScrollPause}
+}
+
+func (e *ScrollEffect) GenerateFilter(p config.SegmentParams) string {
+	return keyframeFilter(p, e.GenerateKeyframes(p))
+}
+
+func (e *ScrollEffect) GenerateKeyframes(p config.SegmentParams) []director.Keyframe {
+	page := pageRect(p)
+	fullView := director.Rectangle{X: 0, Y: 0, W: float64(p.Width), H: float64(p.Height)}
+
+	// Окно камеры при ширине страницы во весь кадр
+	zoom := director.CoverZoom(page, p.Width, p.Height)
+	winH := float64(p.Height) / zoom
+	travel := page.H - winH
+	if travel <= 1 {
+		// Страница целиком помещается по высоте: прокручивать нечего
+		return []director.Keyframe{
+			{Time: 0, Focus: "full_view", Zoom: 1.0, Rect: fullView},
+			{Time: p.Duration, Focus: "full_view", Zoom: 1.0, Rect: fullView},
+		}
+	}
+
+	active := p.Duration - p.FadeDuration
+	if active <= 0 {
+		active = p.Duration
+	}
+	edgeHold := active * scrollEdgeHold
+	scrollTime := active - 2*edgeHold
+	if scrollTime <= 0 {
+		edgeHold, scrollTime = 0, active
+	}
+
+	// Остановки: смещение верхнего края окна от верха страницы
+	stops := e.scrollStops(p, page, winH, travel)
+
+	pause := e.Pause
+	if pause <= 0 {
+		pause = defaultScrollPause
+	}
+	if len(stops) > 0 && pause*float64(len(stops)) > scrollTime*maxPauseShare {
+		pause = scrollTime * maxPauseShare / float64(len(stops))
+	}
+	speed := travel / (scrollTime - pause*float64(len(stops))) // Пикселей кадра в секунду
+
+	cx := page.X + page.W/2
+	at := func(t float64, focus string, offset float64) director.Keyframe {
+		cy := page.Y + offset + winH/2
+		// Постоянная скорость прокрутки: без сглаживания между остановками
+		return director.Keyframe{Time: t, Focus: focus, Zoom: zoom, Rect: director.Rectangle{X: cx, Y: cy}, Easing: director.EasingLinear}
+	}
+
+	frames := []director.Keyframe{at(0, "scroll_top", 0)}
+	t, pos := edgeHold, 0.0
+	frames = append(frames, at(t, "scroll_start", 0))
+	for i, stop := range stops {
+		t += (stop - pos) / speed
+		frames = append(frames, at(t, fmt.Sprintf("stop_%d", i+1), stop))
+		t += pause
+		frames = append(frames, at(t, fmt.Sprintf("stop_%d_hold", i+1), stop))
+		pos = stop
+	}
+	t += (travel - pos) / speed
+	frames = append(frames, at(t, "scroll_bottom", travel))
+	frames = append(frames, at(p.Duration, "scroll_end", travel))
+
+	return director.ReframeKeyframes(frames, p.Width, p.Height, page)
+}
+
+// scrollStops переводит найденные блоки в положения окна, при которых блок по центру кадра.
+// Блоки у краев страницы и слишком близкие друг к другу остановки отбрасываются.
+func (e *ScrollEffect) scrollStops(p config.SegmentParams, page director.Rectangle, winH, travel float64) []float64 {
+	if len(p.Regions) == 0 || p.SourceHeight <= 0 {
+		return nil
+	}
+	scale := page.H / float64(p.SourceHeight)
+
+	var offsets []float64
+	for _, r := range p.Regions {
+		center := float64(r.Min.Y+r.Max.Y) / 2 * scale
+		offsets = append(offsets, center-winH/2)
+	}
+	sort.Float64s(offsets)
+
+	var stops []float64
+	last := 0.0 // Верх страницы уже показан в начале
+	for _, off := range offsets {
+		if off-last < winH*minStopGap || travel-off < winH*minStopGap {
+			continue
+		}
+		stops = append(stops, off)
+		last = off
+	}
+	return stops
+}
@@ -0,0 +1,81 @@
+package effects
+
+import (
+	"image"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/config"
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+func scrollParams() config.SegmentParams {
+	return config.SegmentParams{
+		Width: 1280, Height: 720, FPS: 30,
+		Duration: 12, FadeDuration: 0.5,
+		ZoomMode:    ZoomModeScroll,
+		SourceWidth: 1000, SourceHeight: 6000, // Long infographic
+	}
+}
+
+func TestScrollFitsWidthAndScrollsDown(t *testing.T) {
+	p := scrollParams()
+	frames := NewScrollEffect().GenerateKeyframes(p)
+	page := director.PageRect(p.SourceWidth, p.SourceHeight, p.Width, p.Height)
+
+	first, last := frames[0], frames[len(frames)-1]
+	if first.Rect.Y != page.Y || last.Rect.Y+last.Rect.H < page.Y+page.H-1 {
+		t.Errorf("expected scroll from the top (%.0f) to the bottom (%.0f), got %+v .. %+v", page.Y, page.Y+page.H, first.Rect, last.Rect)
+	}
+
+	for i, kf := range frames {
+		if kf.Rect.X < page.X || kf.Rect.X+kf.Rect.W > page.X+page.W {
+			t.Errorf("window %+v is wider than the page %+v", kf.Rect, page)
+		}
+		if i > 0 && (kf.Rect.Y < frames[i-1].Rect.Y || kf.Time < frames[i-1].Time) {
+			t.Errorf("camera should only move down: %+v after %+v", kf, frames[i-1])
+		}
+	}
+
+	if last.Time != p.Duration {
+		t.Errorf("expected keyframes until the end of the slide, got %.2f", last.Time)
+	}
+}
+
+func TestScrollPausesAtRegions(t *testing.T) {
+	p := scrollParams()
+	p.Regions = []image.Rectangle{
+		image.Rect(100, 2000, 900, 2400),
+		image.Rect(100, 4000, 900, 4300),
+	}
+
+	frames := NewScrollEffect().GenerateKeyframes(p)
+	holds := 0
+	for i := 1; i < len(frames); i++ {
+		if frames[i].Rect == frames[i-1].Rect && frames[i].Time-frames[i-1].Time >= defaultScrollPause-1e-9 &&
+			frames[i].Focus != "scroll_start" && frames[i].Focus != "scroll_end" {
+			holds++
+		}
+	}
+	if holds != len(p.Regions) {
+		t.Errorf("expected %d pauses, got %d in %+v", len(p.Regions), holds, frames)
+	}
+}
+
+func TestScrollShortPageStaysStill(t *testing.T) {
+	p := scrollParams()
+	p.SourceWidth, p.SourceHeight = 1600, 900
+
+	frames := NewScrollEffect().GenerateKeyframes(p)
+	for _, kf := range frames {
+		if kf.Zoom != 1.0 {
+			t.Errorf("expected full view for a page that fits, got %+v", kf)
+		}
+	}
+}
+
+func TestDefaultEffectScrollMode(t *testing.T) {
+	p := scrollParams()
+	if got, want := (&DefaultEffect{}).GenerateKeyframes(p), NewScrollEffect().GenerateKeyframes(p); len(got) != len(want) {
+		t.Errorf("expected the scroll zoom mode to use ScrollEffect, got %d keyframes", len(got))
+	}
+}
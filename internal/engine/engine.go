@@ -11,6 +11,7 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,12 +19,10 @@ import (
 	"github.com/ivlev/pdf2video/internal/config"
 	"github.com/ivlev/pdf2video/internal/director"
 	"github.com/ivlev/pdf2video/internal/effects"
+	"github.com/ivlev/pdf2video/internal/renderer"
 	"github.com/ivlev/pdf2video/internal/source"
 	"github.com/ivlev/pdf2video/internal/system"
 	"github.com/ivlev/pdf2video/internal/video"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/basicfont"
-	"golang.org/x/image/math/fixed"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -68,6 +67,10 @@ func (p *VideoProject) Run(ctx context.Context) error {
 	if pageCount == 0 {
 		return fmt.Errorf("источник не содержит страниц/кадров")
 	}
+	if err := p.selectPages(); err != nil {
+		return err
+	}
+	pageCount = p.Source.PageCount()
 
 	// Обработка сценариев
 	if p.Config.GenerateScenario {
@@ -84,9 +87,28 @@ func (p *VideoProject) Run(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("ошибка чтения сценария: %v", err)
 		}
+		// Проверяем сценарий до рендеринга: ошибки прерывают запуск, предупреждения только выводятся
+		if issues := scenario.Lint(); len(issues) > 0 {
+			for _, issue := range issues {
+				fmt.Printf("[!] Сценарий: %s\n", issue)
+			}
+			if director.HasErrors(issues) {
+				return fmt.Errorf("сценарий содержит ошибки, проверьте его командой: pdf2video lint %s", p.Config.ScenarioInput)
+			}
+		}
 		p.Effect = effects.NewScenarioEffect(scenario)
 		fmt.Printf("[*] Используется сценарий: %s\n", p.Config.ScenarioInput)
 
+		// Страницы видео берутся по ссылкам input слайдов (deck.pdf#7, cover.jpg, #3)
+		slides, err := p.slideSource(scenario)
+		if err != nil {
+			return fmt.Errorf("ошибка сборки слайдов сценария: %v", err)
+		}
+		defer slides.Close()
+		p.Source = slides
+		pageCount = slides.PageCount()
+		fmt.Printf("[*] Слайдов в сценарии: %d\n", pageCount)
+
 		// Если сценарий загружен, длительности берем из него
 		if len(scenario.Slides) > 0 {
 			durations := make([]float64, pageCount)
@@ -99,10 +121,8 @@ func (p *VideoProject) Run(ctx context.Context) error {
 			// Если в конфиге уже задана общая длительность (например, из аудио),
 			// масштабируем длительности слайдов из сценария
 			if p.Config.TotalDuration > 0 {
-				targetTotalClipsDur := p.Config.TotalDuration
-				if pageCount > 1 {
-					targetTotalClipsDur += float64(pageCount-1) * p.Config.FadeDuration
-				}
+				// Переходы (с учетом настроек слайдов) перекрывают соседние сегменты
+				targetTotalClipsDur := p.Config.TotalDuration + p.totalFade(pageCount)
 
 				if scenarioTotalClipsDur > 0 {
 					scale := targetTotalClipsDur / scenarioTotalClipsDur
@@ -115,11 +135,7 @@ func (p *VideoProject) Run(ctx context.Context) error {
 				}
 			} else {
 				// Если общая длительность не задана, рассчитываем её по сценарию
-				total := scenarioTotalClipsDur
-				if pageCount > 1 {
-					total -= float64(pageCount-1) * p.Config.FadeDuration
-				}
-				p.Config.TotalDuration = total
+				p.Config.TotalDuration = scenarioTotalClipsDur - p.totalFade(pageCount)
 				for i := range durations {
 					// Выравниваем по кадрам
 					durations[i] = math.Round(durations[i]*float64(p.Config.FPS)) / float64(p.Config.FPS)
@@ -130,6 +146,9 @@ func (p *VideoProject) Run(ctx context.Context) error {
 	} else {
 		// Рассчитываем рандомизированные длительности (стандартный режим)
 		p.calculateDurations(pageCount)
+		if p.Config.Emphasis != "" && p.Config.Emphasis != director.EmphasisNone {
+			fmt.Println("[!] Предупреждение: -emphasis выделяет области кадров сценария, без -scenario флаг игнорируется")
+		}
 	}
 
 	// Проверка корректности переходов относительно минимальной длительности
@@ -147,7 +166,8 @@ func (p *VideoProject) Run(ctx context.Context) error {
 		p.calculateDurations(pageCount)
 	}
 
-	if p.Config.Width == 1280 && p.Config.Height == 720 {
+	// В режиме прокрутки кадр не подгоняется под высокую страницу, иначе она снова вписывается целиком
+	if p.Config.Width == 1280 && p.Config.Height == 720 && p.Config.ZoomMode != effects.ZoomModeScroll {
 		srcW, srcH, err := p.Source.GetPageDimensions(0)
 		if err == nil {
 			p.Config.Width = int(float64(p.Config.Height) * (srcW / srcH))
@@ -157,9 +177,27 @@ func (p *VideoProject) Run(ctx context.Context) error {
 		}
 	}
 
+	// Сценарии формата 1.x хранят пиксели кадра: переводим их в доли страницы
+	if se, ok := p.Effect.(*effects.ScenarioEffect); ok && se.Scenario.IsLegacy() {
+		se.Scenario.Migrate(p.Config.Width, p.Config.Height, func(i int) (float64, float64) {
+			w, h, _ := p.Source.GetPageDimensions(i)
+			return w, h
+		})
+		fmt.Printf("[*] Сценарий формата 1.x переведен в формат %s (координаты от страницы)\n", director.ScenarioVersion)
+	}
+
 	fmt.Println("--- [PROJECT: MODULAR ENGINE] ---")
-	fmt.Printf("[*] Источник: %s | Кадров/Страниц: %d\n", p.Config.InputPath, pageCount)
+	inputs := p.Config.InputPath
+	if len(p.Config.InputPaths) > 1 {
+		inputs = strings.Join(p.Config.InputPaths, " + ")
+	}
+	fmt.Printf("[*] Источник: %s | Кадров/Страниц: %d\n", inputs, pageCount)
 	fmt.Printf("[*] Разрешение: %dx%d @ %d FPS | DPI: %d\n", p.Config.Width, p.Config.Height, p.Config.FPS, p.Config.DPI)
+	if p.Config.RenderMode == "native" {
+		fmt.Println("[*] Рендеринг камеры: native (покадрово в Go)")
+	} else if n := p.overlaySlides(); n > 0 {
+		fmt.Printf("[*] Слайдов с аннотациями или выделением фокуса: %d, они рендерятся покадрово в Go\n", n)
+	}
 	fmt.Println("-----------------------------")
 
 	// Каналы для пайплайна
@@ -170,9 +208,7 @@ func (p *VideoProject) Run(ctx context.Context) error {
 	for _, d := range p.Config.PageDurations {
 		sumDur += d
 	}
-	if pageCount > 1 {
-		sumDur -= float64(pageCount-1) * p.Config.FadeDuration
-	}
+	sumDur -= p.totalFade(pageCount)
 	p.Config.TotalDuration = sumDur
 
 	results := make([]string, pageCount)
@@ -190,6 +226,12 @@ func (p *VideoProject) Run(ctx context.Context) error {
 		numWorkers = pageCount
 	}
 
+	// Остановки прокрутки: блоки ищутся детектором на каждой странице
+	scrollDetectMode := ""
+	if p.Config.ZoomMode == effects.ZoomModeScroll && p.Config.ScrollStops && p.Config.ScenarioInput == "" {
+		scrollDetectMode = p.resolveAnalyzeMode()
+	}
+
 	// Trace scenario collection
 	traceScenario := &director.Scenario{
 		Version: "1.0-trace",
@@ -251,16 +293,34 @@ func (p *VideoProject) Run(ctx context.Context) error {
 					Height:        p.Config.Height,
 					FPS:           p.Config.FPS,
 					Duration:      duration,
-					ZoomMode:      p.Config.ZoomMode,
+					ZoomMode:      p.zoomModeFor(i),
 					ZoomSpeed:     p.Config.ZoomSpeed,
-					FadeDuration:  p.Config.FadeDuration,
-					OutroDuration: p.Config.OutroDuration,
+					FadeDuration:  p.fadeOut(i, pageCount),
+					OutroDuration: p.outroDuration(i),
 					PageIndex:     i,
 					Debug:         p.Config.Debug,
 					Trace:         p.Config.Trace,
 					TraceColor:    p.Config.TraceColor,
+					SourceWidth:   img.Bounds().Dx(),
+					SourceHeight:  img.Bounds().Dy(),
+					Reframe:       p.Config.Reframe,
+					Emphasis:      p.Config.Emphasis,
+				}
+				if scrollDetectMode != "" {
+					params.Regions = p.detectRegions(scrollDetectMode, img, i, dpi)
+				}
+
+				// Аннотации и выделение фокуса меняются во времени и рисуются покадрово,
+				// поэтому слайд с ними рендерится в Go
+				annotations := p.slideAnnotations(params)
+				emphasis := p.slideEmphasis(params)
+				native := p.Config.RenderMode == "native" || len(annotations) > 0 || len(emphasis) > 0
+				if !native {
+					params.Filter = p.Effect.GenerateFilter(params)
+				} else if p.Config.Debug {
+					// Без цепочки zoompan номер слайда и время накладываются поверх готовых кадров
+					params.Filter = effects.DebugFilter(params)
 				}
-				params.Filter = p.Effect.GenerateFilter(params)
 
 				// Debug/Trace drawing
 				if p.Config.Debug || p.Config.Trace {
@@ -284,7 +344,19 @@ func (p *VideoProject) Run(ctx context.Context) error {
 					}
 				}
 
-				encErr := p.Encoder.EncodeSegment(gCtx, img, segPath, params, p.Config.VideoEncoder, p.Config.Quality)
+				var encErr error
+				if native {
+					// Покадровый рендер в Go: камера считается для каждого кадра без округления до пикселя
+					fr := renderer.NewFrameRenderer(img, p.Effect.GenerateKeyframes(params), params.Width, params.Height, params.FPS)
+					if se, ok := p.Effect.(*effects.ScenarioEffect); ok {
+						fr.Path = se.PathOptions()
+					}
+					fr.Emphasis = emphasis
+					fr.Annotations = annotations
+					encErr = p.Encoder.EncodeFrames(gCtx, segPath, params, p.Config.VideoEncoder, p.Config.Quality, fr.RenderFrame)
+				} else {
+					encErr = p.Encoder.EncodeSegment(gCtx, img, segPath, params, p.Config.VideoEncoder, p.Config.Quality)
+				}
 
 				// Сразу освобождаем память после кодирования
 				if rgba, ok := img.(*image.RGBA); ok {
@@ -359,7 +431,8 @@ func (p *VideoProject) Run(ctx context.Context) error {
 
 	if p.Config.BlackScreenDuration > 0 {
 		introPath := filepath.Join(p.tempDir, "intro_black.mp4")
-		introDur := p.Config.BlackScreenDuration + p.Config.FadeDuration
+		_, introFade := p.transitionInto(0)
+		introDur := p.Config.BlackScreenDuration + introFade
 		fmt.Printf("[*] Создание интро (черный экран, %.2fs)\n", introDur)
 		if err := p.generateBlackSegment(p.ctx, introDur, introPath); err != nil {
 			return fmt.Errorf("ошибка создания интро: %v", err)
@@ -376,15 +449,7 @@ func (p *VideoProject) Run(ctx context.Context) error {
 	}
 
 	for i, r := range results {
-		transType := p.Config.TransitionType
-		fadeDur := p.Config.FadeDuration
-
-		if i == 0 && p.Config.BlackScreenDuration > 0 {
-			transType = p.Config.BlackScreenTransition
-		} else if i == 0 {
-			transType = "none"
-			fadeDur = 0
-		}
+		transType, fadeDur := p.transitionInto(i)
 
 		finalSegments = append(finalSegments, config.VideoSegment{
 			Path:           r,
@@ -562,45 +627,79 @@ func (p *VideoProject) calculateDurations(pageCount int) {
 	p.Config.PageDurations = durations
 }
 
+// applySafeArea передает режиссеру закрытые части кадра: отступы под интерфейс площадки,
+// зоны исключения и угол со сквозным QR-кодом
+func (p *VideoProject) applySafeArea(dir *director.Director) error {
+	insets, err := config.ParseSafeArea(p.Config.SafeArea)
+	if err != nil {
+		return err
+	}
+	dir.SafeArea = director.Insets{Top: insets[0], Right: insets[1], Bottom: insets[2], Left: insets[3]}
+
+	zones, err := config.ParseExclusions(p.Config.Exclusions)
+	if err != nil {
+		return err
+	}
+	w, h := float64(p.Config.Width), float64(p.Config.Height)
+	for _, z := range zones {
+		dir.Exclusions = append(dir.Exclusions, director.Rectangle{X: z[0] * w, Y: z[1] * h, W: z[2] * w, H: z[3] * h})
+	}
+
+	if p.Config.QREnabled && p.Config.QRURL != "" && p.Config.QRSize > 0 {
+		size := float64(p.Config.QRSize)
+		dir.Exclusions = append(dir.Exclusions, director.Rectangle{
+			X: w - float64(p.Config.QRMarginRight) - size,
+			Y: h - float64(p.Config.QRMarginBottom) - size,
+			W: size,
+			H: size,
+		})
+	}
+	return nil
+}
+
 func (p *VideoProject) handleGenerateScenario(pageCount int) error {
 	fmt.Println("[*] Режим генерации сценария...")
-	p.Source.SetDPI(p.Config.DPI)
 
 	// Используем Director для генерации путей камеры
 	dir := director.NewDirector(p.Config.Width, p.Config.Height)
 
-	// Smart Analysis Logic
-	hasText := p.hasTextLayer()
-	finalMode := p.Config.AnalyzeMode
-
-	if finalMode == "auto" {
-		if hasText {
-			finalMode = "ocr"
-			fmt.Println("[*] Автоопределение: найден слой текста, используется режим \"ocr\"")
-		} else {
-			finalMode = "contrast"
-			fmt.Println("[*] Автоопределение: слой текста не найден, используется режим \"contrast\"")
-		}
-	} else if finalMode == "ocr" && !hasText {
-		fmt.Println("[!] Предупреждение: слой текста не найден. Переключение в режим \"contrast\".")
-		finalMode = "contrast"
-	} else if finalMode == "contrast" && hasText {
-		fmt.Println("[!] Предупреждение: в PDF найден слой текста. Возможно, режим \"ocr\" даст лучший результат.")
+	// Стиль режиссуры задает тайминг, кадрирование, скорость камеры и веса приоритетов
+	style, err := director.LoadStyle(p.Config.Style)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки стиля: %v", err)
+	}
+	dir.ApplyStyle(style)
+	if p.Config.Style != "" {
+		fmt.Printf("[*] Стиль режиссуры: %s\n", style.Name)
+	}
+	if p.Config.Composition != "" {
+		dir.Composition = p.Config.Composition
+	}
+	if p.Config.AdaptivePadding != nil {
+		dir.AdaptivePadding = *p.Config.AdaptivePadding
+	}
+	if p.Config.Ordering != "" {
+		dir.Ordering = p.Config.Ordering
+	}
+	dir.Reframe = p.Config.Reframe
+	if p.Config.ReadingWPM > 0 {
+		dir.ReadingWPM = p.Config.ReadingWPM
+	}
+	if err := p.applySafeArea(dir); err != nil {
+		return err
 	}
 
-	// Инициализируем детектор на основе выбранного режима
-	det, err := analyzer.NewDetector(finalMode)
+	// Smart Analysis Logic
+	det, err := p.newDetector()
 	if err != nil {
-		return fmt.Errorf("ошибка инициализации детектора (%s): %v", finalMode, err)
+		return err
 	}
-
-	// Настройка параметров, если детектор их поддерживает
-	if cdet, ok := det.(*analyzer.ContrastDetector); ok {
-		cdet.MinBlockArea = p.Config.MinBlockArea
-		cdet.EdgeThreshold = p.Config.EdgeThreshold
-	} else if edet, ok := det.(*analyzer.EnhancedDetector); ok {
-		edet.MinBlockArea = p.Config.MinBlockArea
-		edet.EdgeThreshold = p.Config.EdgeThreshold
+	if edet, ok := det.(*analyzer.EnhancedDetector); ok {
+		edet.SetPrioritizer(style.NewPrioritizer())
+	}
+	if _, ok := det.(*analyzer.PanelDetector); ok && p.Config.Ordering == "" {
+		// Детектор кадров комикса уже отдает их в порядке чтения: камера идет от кадра к кадру
+		dir.Ordering = director.OrderingDetector
 	}
 
 	var slides []director.Slide
@@ -639,10 +738,28 @@ func (p *VideoProject) handleGenerateScenario(pageCount int) error {
 		if odet, ok := det.(*analyzer.OCRDetector); ok {
 			odet.Source = p.Source
 			odet.PageIndex = i
+			odet.DPI = dpi
+		}
+
+		// Блоки находятся в пикселях отрендеренной страницы, Director переводит их в координаты кадра
+		dir.PageWidth, dir.PageHeight = 0, 0
+		if img != nil {
+			dir.PageWidth, dir.PageHeight = img.Bounds().Dx(), img.Bounds().Dy()
 		}
 
 		// Поиск блоков на изображении
 		blocks, err := det.Detect(img)
+		if _, ok := det.(*analyzer.EnhancedDetector); !ok && err == nil && p.Config.Style != "" && img != nil && dir.Ordering != director.OrderingDetector {
+			// Остальные детекторы не ранжируют блоки: приоритеты считаем по весам стиля
+			b := img.Bounds()
+			blocks = style.NewPrioritizer().Prioritize(blocks, b.Dx(), b.Dy())
+		}
+		if err == nil && p.Source.HasTextLayer(i) {
+			// Время на блоке считается по числу слов из слоя текста
+			if lines, errLines := p.Source.GetTextLines(i, dpi); errLines == nil {
+				blocks = analyzer.AssignWordCounts(blocks, lines)
+			}
+		}
 		if rgba, ok := img.(*image.RGBA); ok {
 			system.PutImage(rgba)
 			p.memory.Release(frameSize)
@@ -662,18 +779,20 @@ func (p *VideoProject) handleGenerateScenario(pageCount int) error {
 			slideDuration = p.Config.PageDurations[i]
 		}
 
-		slideScenario, err := dir.GenerateScenario(blocks, fmt.Sprintf("slide_%d.png", i+1), slideDuration, p.Config.FadeDuration, p.Config.OutroDuration)
+		// Слайд ссылается на исходный номер страницы, даже если часть страниц исключена -pages
+		input := fmt.Sprintf("#%d", p.pageNumber(i))
+		slideScenario, err := dir.GenerateScenario(blocks, input, slideDuration, p.Config.FadeDuration, p.Config.OutroDuration)
 		if err != nil || len(slideScenario.Slides) == 0 {
 			// Если анализ не удался, создаем пустой слайд
 			slides = append(slides, director.Slide{
 				ID:       i + 1,
-				Input:    fmt.Sprintf("slide_%d.png", i+1),
+				Input:    input,
 				Duration: slideDuration,
 				Keyframes: []director.Keyframe{
 					{
 						Time:  0,
 						Focus: "full_view",
-						Rect:  director.Rectangle{X: 0, Y: 0, W: p.Config.Width, H: p.Config.Height},
+						Rect:  director.Rectangle{X: 0, Y: 0, W: 1, H: 1},
 						Zoom:  1.0,
 					},
 				},
@@ -681,13 +800,17 @@ func (p *VideoProject) handleGenerateScenario(pageCount int) error {
 			continue
 		}
 
+		if dir.Covered > 0 {
+			fmt.Printf("[!] Предупреждение: страница %d: блоков, частично закрытых безопасной зоной или зоной исключения: %d\n", p.pageNumber(i), dir.Covered)
+		}
+
 		slide := slideScenario.Slides[0]
 		slide.ID = i + 1
 		slides = append(slides, slide)
 	}
 
 	scenario := &director.Scenario{
-		Version: "1.0",
+		Version: director.ScenarioVersion,
 		Slides:  slides,
 	}
 
@@ -709,33 +832,61 @@ func (p *VideoProject) handleGenerateScenario(pageCount int) error {
 	return nil
 }
 
+// pageKeyframes переводит прямоугольники кадров из долей страницы в пиксели изображения
+func (p *VideoProject) pageKeyframes(keyframes []director.Keyframe, bounds image.Rectangle) []director.Keyframe {
+	page := director.Rectangle{
+		X: float64(bounds.Min.X),
+		Y: float64(bounds.Min.Y),
+		W: float64(bounds.Dx()),
+		H: float64(bounds.Dy()),
+	}
+
+	mapped := make([]director.Keyframe, len(keyframes))
+	for i, kf := range keyframes {
+		mapped[i] = kf
+		mapped[i].Rect = kf.Rect.Denormalize(page)
+		if kf.FocusRect != (director.Rectangle{}) {
+			mapped[i].FocusRect = kf.FocusRect.Denormalize(page)
+		}
+	}
+	return mapped
+}
+
 func (p *VideoProject) debugDrawScenario(img image.Image, se *effects.ScenarioEffect, pageIndex int, drawDebug, drawTrace bool) image.Image {
 	if pageIndex >= len(se.Scenario.Slides) {
 		return img
 	}
 	slide := se.Scenario.Slides[pageIndex]
+	if slide.CameraEffect() != director.SlideEffectKeyframes {
+		// Камера слайда не следует кадрам сценария: рисовать нечего
+		return img
+	}
 
 	// Create a writable copy from the buffer pool
 	bounds := img.Bounds()
 	rgba := system.GetImage(bounds)
 	draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
 
+	// Кадры сценария заданы в долях страницы, рисуем их в пикселях изображения
+	keyframes := p.pageKeyframes(slide.Keyframes, bounds)
+
 	// Draw rectangles for each keyframe
+	painter := renderer.NewPainter(rgba)
 	red := color.RGBA{255, 0, 0, 255}
 	if drawDebug {
-		for _, kf := range slide.Keyframes {
-			p.drawHollowRect(rgba, kf.Rect, red)
+		for _, kf := range keyframes {
+			painter.Rect(kf.Rect, 4, red)
 		}
 	}
 
 	if drawTrace {
-		p.drawTracePath(rgba, slide.Keyframes, p.Config.TraceColor)
+		p.drawTracePath(painter, keyframes, p.Config.TraceColor)
 	}
 
 	return rgba
 }
 
-func (p *VideoProject) drawTracePath(img *image.RGBA, keyframes []director.Keyframe, traceColorStr string) {
+func (p *VideoProject) drawTracePath(painter *renderer.Painter, keyframes []director.Keyframe, traceColorStr string) {
 	if len(keyframes) < 2 {
 		return
 	}
@@ -746,120 +897,30 @@ func (p *VideoProject) drawTracePath(img *image.RGBA, keyframes []director.Keyfr
 	if err != nil {
 		textColor = color.RGBA{255, 255, 255, 255} // Fallback to White
 	}
-	dotRadius := 8
+	dotRadius := 8.0
 
 	// Draw lines between centers of keyframes
 	for i := 0; i < len(keyframes)-1; i++ {
-		start := p.getCenter(keyframes[i])
-		end := p.getCenter(keyframes[i+1])
-		p.drawLine(img, start, end, traceColor, 3)
-	}
-
-	// Prepare font drawer
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(textColor),
-		Face: basicfont.Face7x13,
+		x0, y0 := rectCenter(keyframes[i].Rect)
+		x1, y1 := rectCenter(keyframes[i+1].Rect)
+		painter.Line(x0, y0, x1, y1, 3, traceColor)
 	}
 
 	// Draw dots for each stop point (keyframe center)
 	for _, kf := range keyframes {
-		center := p.getCenter(kf)
-		p.drawCircle(img, center, dotRadius, dotColor)
+		x, y := rectCenter(kf.Rect)
+		painter.Circle(x, y, dotRadius, dotColor)
 
 		// Draw coordinates near the dot
-		label := fmt.Sprintf("(%d, %d)", center.X, center.Y)
-		d.Dot = fixed.Point26_6{
-			X: fixed.I(center.X + 10),
-			Y: fixed.I(center.Y - 10),
-		}
-		d.DrawString(label)
+		label := fmt.Sprintf("(%d, %d)", int(x), int(y))
+		painter.Text(label, x+10, y-24, 14, textColor)
 	}
 }
 
-func (p *VideoProject) getCenter(kf director.Keyframe) image.Point {
-	return image.Point{
-		X: int(kf.Rect.X + kf.Rect.W/2),
-		Y: int(kf.Rect.Y + kf.Rect.H/2),
-	}
+func rectCenter(r director.Rectangle) (float64, float64) {
+	return r.X + r.W/2, r.Y + r.H/2
 }
 
-// Simple Bresenham line drawing
-func (p *VideoProject) drawLine(img *image.RGBA, start, end image.Point, c color.Color, thickness int) {
-	x0, y0 := start.X, start.Y
-	x1, y1 := end.X, end.Y
-
-	dx := x1 - x0
-	if dx < 0 {
-		dx = -dx
-	}
-	dy := y1 - y0
-	if dy < 0 {
-		dy = -dy
-	}
-
-	sx, sy := 1, 1
-	if x0 >= x1 {
-		sx = -1
-	}
-	if y0 >= y1 {
-		sy = -1
-	}
-
-	err := dx - dy
-
-	for {
-		// Draw thick point
-		for i := -thickness / 2; i <= thickness/2; i++ {
-			for j := -thickness / 2; j <= thickness/2; j++ {
-				img.Set(x0+i, y0+j, c)
-			}
-		}
-
-		if x0 == x1 && y0 == y1 {
-			break
-		}
-
-		e2 := 2 * err
-		if e2 > -dy {
-			err -= dy
-			x0 += sx
-		}
-		if e2 < dx {
-			err += dx
-			y0 += sy
-		}
-	}
-}
-
-// Simple circle drawing (filled)
-func (p *VideoProject) drawCircle(img *image.RGBA, center image.Point, radius int, c color.Color) {
-	cx, cy := center.X, center.Y
-	for y := -radius; y <= radius; y++ {
-		for x := -radius; x <= radius; x++ {
-			if x*x+y*y <= radius*radius {
-				img.Set(cx+x, cy+y, c)
-			}
-		}
-	}
-}
-
-func (p *VideoProject) drawHollowRect(img *image.RGBA, r director.Rectangle, c color.Color) {
-	thickness := 4
-	x := int(r.X)
-	y := int(r.Y)
-	w := int(r.W)
-	h := int(r.H)
-
-	// Top
-	draw.Draw(img, image.Rect(x, y, x+w, y+thickness), &image.Uniform{c}, image.Point{}, draw.Src)
-	// Bottom
-	draw.Draw(img, image.Rect(x, y+h-thickness, x+w, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
-	// Left
-	draw.Draw(img, image.Rect(x, y, x+thickness, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
-	// Right
-	draw.Draw(img, image.Rect(x+w-thickness, y, x+w, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
-}
 func (p *VideoProject) calculateOptimalDPI(index int) int {
 	srcW, srcH, err := p.Source.GetPageDimensions(index)
 	if err != nil {
@@ -910,6 +971,80 @@ func (p *VideoProject) calculateOptimalDPI(index int) int {
 	return result
 }
 
+// detectRegions находит блоки страницы для остановок камеры (пиксели отрендеренной страницы).
+// Детектор создается на каждую страницу: воркеры работают параллельно.
+func (p *VideoProject) detectRegions(mode string, img image.Image, pageIndex, dpi int) []image.Rectangle {
+	det, err := p.detectorFor(mode)
+	if err != nil {
+		return nil
+	}
+	if odet, ok := det.(*analyzer.OCRDetector); ok {
+		// Слой текста запрашивается в DPI, с которым отрендерена страница
+		odet.Source = p.Source
+		odet.PageIndex = pageIndex
+		odet.DPI = dpi
+	}
+
+	blocks, err := det.Detect(img)
+	if err != nil {
+		log.Printf("[!] Ошибка анализа страницы %d: %v", pageIndex, err)
+		return nil
+	}
+	regions := make([]image.Rectangle, len(blocks))
+	for j, b := range blocks {
+		regions[j] = b.Rect
+	}
+	return regions
+}
+
+// resolveAnalyzeMode выбирает режим анализа: в режиме auto — по наличию слоя текста
+func (p *VideoProject) resolveAnalyzeMode() string {
+	hasText := p.hasTextLayer()
+	finalMode := p.Config.AnalyzeMode
+
+	if finalMode == "auto" {
+		if hasText {
+			finalMode = "ocr"
+			fmt.Println("[*] Автоопределение: найден слой текста, используется режим \"ocr\"")
+		} else {
+			finalMode = "contrast"
+			fmt.Println("[*] Автоопределение: слой текста не найден, используется режим \"contrast\"")
+		}
+	} else if finalMode == "ocr" && !hasText {
+		fmt.Println("[!] Предупреждение: слой текста не найден. Переключение в режим \"contrast\".")
+		finalMode = "contrast"
+	} else if finalMode == "contrast" && hasText {
+		fmt.Println("[!] Предупреждение: в PDF найден слой текста. Возможно, режим \"ocr\" даст лучший результат.")
+	}
+
+	return finalMode
+}
+
+// newDetector создает детектор блоков для режима анализа из конфигурации
+func (p *VideoProject) newDetector() (analyzer.Detector, error) {
+	return p.detectorFor(p.resolveAnalyzeMode())
+}
+
+// detectorFor создает и настраивает детектор блоков для режима анализа
+func (p *VideoProject) detectorFor(finalMode string) (analyzer.Detector, error) {
+	// Инициализируем детектор на основе выбранного режима
+	det, err := analyzer.NewDetector(finalMode)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка инициализации детектора (%s): %v", finalMode, err)
+	}
+
+	// Настройка параметров, если детектор их поддерживает
+	if cdet, ok := det.(*analyzer.ContrastDetector); ok {
+		cdet.MinBlockArea = p.Config.MinBlockArea
+		cdet.EdgeThreshold = p.Config.EdgeThreshold
+	} else if edet, ok := det.(*analyzer.EnhancedDetector); ok {
+		edet.MinBlockArea = p.Config.MinBlockArea
+		edet.EdgeThreshold = p.Config.EdgeThreshold
+	}
+
+	return det, nil
+}
+
 func (p *VideoProject) hasTextLayer() bool {
 	// Проверяем первые несколько страниц на наличие текста (для экономии времени)
 	checkPages := p.Source.PageCount()
@@ -0,0 +1,303 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ivlev/pdf2video/internal/config"
+	"github.com/ivlev/pdf2video/internal/director"
+	"github.com/ivlev/pdf2video/internal/effects"
+	"github.com/ivlev/pdf2video/internal/source"
+)
+
+// scenarioSlide возвращает слайд сценария для страницы или nil, если рендеринг идет без сценария
+func (p *VideoProject) scenarioSlide(i int) *director.Slide {
+	se, ok := p.Effect.(*effects.ScenarioEffect)
+	if !ok || se.Scenario == nil || i < 0 || i >= len(se.Scenario.Slides) {
+		return nil
+	}
+	return &se.Scenario.Slides[i]
+}
+
+// transitionInto возвращает переход в страницу i и его длительность.
+// Настройки слайда сценария имеют приоритет над глобальными; переход "none" — жесткая склейка.
+func (p *VideoProject) transitionInto(i int) (string, float64) {
+	transType, fade := p.Config.TransitionType, p.Config.FadeDuration
+	if i == 0 {
+		// В первую страницу входим из черного экрана, без него — сразу с первого кадра
+		if p.Config.BlackScreenDuration <= 0 {
+			return "none", 0
+		}
+		transType = p.Config.BlackScreenTransition
+	}
+
+	if slide := p.scenarioSlide(i); slide != nil {
+		if slide.Transition != "" {
+			transType = slide.Transition
+		}
+		if slide.Fade != nil {
+			fade = *slide.Fade
+		}
+	}
+
+	if transType == "none" || fade <= 0 {
+		return "none", 0
+	}
+	return transType, fade
+}
+
+// fadeOut возвращает длительность перехода из страницы i: в следующую страницу
+// или, для последней, в черный экран
+func (p *VideoProject) fadeOut(i, pageCount int) float64 {
+	if i+1 < pageCount {
+		_, fade := p.transitionInto(i + 1)
+		return fade
+	}
+	return p.Config.FadeDuration
+}
+
+// outroDuration возвращает длительность возврата камеры к 1:1 перед переходом из страницы i
+func (p *VideoProject) outroDuration(i int) float64 {
+	if slide := p.scenarioSlide(i); slide != nil && slide.Outro != nil {
+		return *slide.Outro
+	}
+	return p.Config.OutroDuration
+}
+
+// zoomModeFor возвращает режим зума страницы с учетом настроек слайда
+func (p *VideoProject) zoomModeFor(i int) string {
+	if slide := p.scenarioSlide(i); slide != nil && slide.ZoomMode != "" {
+		return slide.ZoomMode
+	}
+	return p.Config.ZoomMode
+}
+
+// slideAnnotations возвращает аннотации слайда в пикселях кадра или nil, если их нет
+func (p *VideoProject) slideAnnotations(params config.SegmentParams) []director.TimedAnnotation {
+	se, ok := p.Effect.(*effects.ScenarioEffect)
+	if !ok {
+		return nil
+	}
+	return se.SlideAnnotations(params)
+}
+
+// slideEmphasis возвращает кадры выделения фокуса слайда или nil, если выделения нет
+func (p *VideoProject) slideEmphasis(params config.SegmentParams) []director.Keyframe {
+	se, ok := p.Effect.(*effects.ScenarioEffect)
+	if !ok {
+		return nil
+	}
+	return se.EmphasisKeyframes(params)
+}
+
+// overlaySlides считает слайды сценария с аннотациями или выделением фокуса
+func (p *VideoProject) overlaySlides() int {
+	se, ok := p.Effect.(*effects.ScenarioEffect)
+	if !ok || se.Scenario == nil {
+		return 0
+	}
+	n := 0
+	for i, slide := range se.Scenario.Slides {
+		params := config.SegmentParams{PageIndex: i, Duration: slide.Duration, Emphasis: p.Config.Emphasis}
+		if len(slide.Annotations()) > 0 || len(se.EmphasisKeyframes(params)) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// totalFade — суммарное перекрытие сегментов на переходах между страницами
+func (p *VideoProject) totalFade(pageCount int) float64 {
+	total := 0.0
+	for i := 1; i < pageCount; i++ {
+		_, fade := p.transitionInto(i)
+		total += fade
+	}
+	return total
+}
+
+// slideSource собирает источник страниц по полю input слайдов сценария:
+// сценарий становится монтажным листом (порядок, повторы, страницы из разных файлов).
+// Страницы основного источника указаны исходными номерами; слайды страниц вне выбора
+// -pages убираются из сценария. Ссылка на один из файлов -input берет его страницу
+// из основного источника, а не открывает файл заново.
+func (p *VideoProject) slideSource(scenario *director.Scenario) (*source.CompositeSource, error) {
+	baseDir := filepath.Dir(p.Config.ScenarioInput)
+	inputs := p.inputSpans()
+
+	opened := make(map[string]source.Source)
+	var owned []source.Source
+	closeOwned := func() {
+		for _, s := range owned {
+			s.Close()
+		}
+	}
+
+	filter, _ := p.Source.(*source.PageFilter)
+	pages := make([]source.PageRef, 0, len(scenario.Slides))
+	kept := make([]director.Slide, 0, len(scenario.Slides))
+	for i, slide := range scenario.Slides {
+		ref := director.ParseInput(slide.Input)
+
+		src, page := p.Source, i+1 // Без ссылки слайд берет страницу основного источника по порядку
+		if ref.Path == "" {
+			if ref.Page > 0 {
+				page = ref.Page
+			}
+		} else if path, found := resolveInputPath(ref.Path, baseDir); !found {
+			legacy, ok := ref.LegacyPage()
+			if !ok {
+				closeOwned()
+				return nil, fmt.Errorf("слайд %d: файл %s не найден", i+1, ref.Path)
+			}
+			page = legacy
+		} else {
+			page = max(ref.Page, 1)
+			abs, _ := filepath.Abs(path)
+			if span, ok := inputs[abs]; ok {
+				// Страница файла -input — это страница основного источника со смещением файла
+				if page > span.pages {
+					closeOwned()
+					return nil, fmt.Errorf("слайд %d: страницы %d нет в %s (%d стр.)", i+1, page, ref.Path, span.pages)
+				}
+				page += span.offset
+			} else {
+				s, ok := opened[abs]
+				if !ok {
+					var err error
+					if s, err = source.OpenWith(path, SourceOptions(p.Config)); err != nil {
+						closeOwned()
+						return nil, fmt.Errorf("слайд %d: %v", i+1, err)
+					}
+					opened[abs] = s
+					owned = append(owned, s)
+				}
+				src = s
+			}
+		}
+
+		if src == p.Source && filter != nil {
+			if page < 1 || page > filter.Source().PageCount() {
+				closeOwned()
+				return nil, fmt.Errorf("слайд %d: страницы %d нет в источнике (%d стр.)", i+1, page, filter.Source().PageCount())
+			}
+			index, selected := filter.Index(page - 1)
+			if !selected {
+				continue
+			}
+			page = index + 1
+		}
+
+		pages = append(pages, source.PageRef{Source: src, Index: page - 1})
+		kept = append(kept, slide)
+	}
+	if skipped := len(scenario.Slides) - len(kept); skipped > 0 {
+		if len(kept) == 0 {
+			closeOwned()
+			return nil, fmt.Errorf("все слайды сценария ссылаются на страницы вне выбора -pages %q", p.Config.Pages)
+		}
+		fmt.Printf("[*] Слайдов на страницах вне -pages: %d, они пропущены\n", skipped)
+		scenario.Slides = kept
+	}
+
+	composite, err := source.NewCompositeSource(pages, owned)
+	if err != nil {
+		closeOwned()
+		return nil, err
+	}
+	return composite, nil
+}
+
+// inputSpan — место страниц одного файла -input в основном источнике
+type inputSpan struct {
+	offset int // Страниц перед файлом
+	pages  int // Страниц в файле
+}
+
+// inputSpans сопоставляет абсолютные пути файлов -input с их страницами в основном
+// источнике (до выбора -pages). Если файл указан дважды, ссылки ведут на первый.
+func (p *VideoProject) inputSpans() map[string]inputSpan {
+	paths := p.Config.InputPaths
+	if len(paths) == 0 {
+		paths = []string{p.Config.InputPath}
+	}
+	base := p.Source
+	if filter, ok := base.(*source.PageFilter); ok {
+		base = filter.Source()
+	}
+	counts := []int{base.PageCount()}
+	if composite, ok := base.(*source.CompositeSource); ok && len(paths) > 1 {
+		counts = composite.InputPages()
+	}
+
+	spans := make(map[string]inputSpan, len(paths))
+	offset := 0
+	for k, path := range paths {
+		if k >= len(counts) {
+			break
+		}
+		abs, _ := filepath.Abs(path)
+		if _, dup := spans[abs]; !dup {
+			spans[abs] = inputSpan{offset: offset, pages: counts[k]}
+		}
+		offset += counts[k]
+	}
+	return spans
+}
+
+// selectPages оставляет в источнике только страницы из -pages; номера страниц
+// в сценариях остаются исходными (см. pageNumber)
+func (p *VideoProject) selectPages() error {
+	if strings.TrimSpace(p.Config.Pages) == "" {
+		return nil
+	}
+	sel, err := config.ParsePages(p.Config.Pages)
+	if err != nil {
+		return err
+	}
+	total := p.Source.PageCount()
+	filter, err := source.NewPageFilter(p.Source, sel.Indices(total))
+	if err != nil {
+		return err
+	}
+	if filter.PageCount() == 0 {
+		return fmt.Errorf("выбор страниц -pages %q не оставил ни одной страницы из %d", p.Config.Pages, total)
+	}
+	p.Source = filter
+	fmt.Printf("[*] Страницы: %s — выбрано %d из %d\n", p.Config.Pages, filter.PageCount(), total)
+	return nil
+}
+
+// pageNumber возвращает исходный номер (с 1) страницы i источника с учетом выбора -pages
+func (p *VideoProject) pageNumber(i int) int {
+	if filter, ok := p.Source.(*source.PageFilter); ok {
+		return filter.Page(i) + 1
+	}
+	return i + 1
+}
+
+// SourceOptions переводит настройки открытия документов из конфигурации.
+// Если сборка не умеет перекомпоновывать документы, размер страницы не передается
+func SourceOptions(cfg *config.Config) source.Options {
+	if !source.ReflowSupported() {
+		return source.Options{}
+	}
+	w, h, _ := config.ParseReflowSize(cfg.ReflowSize) // Проверено в Validate
+	return source.Options{ReflowWidth: w, ReflowHeight: h}
+}
+
+// resolveInputPath ищет файл слайда относительно сценария, затем относительно рабочей папки
+func resolveInputPath(path, baseDir string) (string, bool) {
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		candidates = []string{filepath.Join(baseDir, path), path}
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c, true
+		}
+	}
+	return path, false
+}
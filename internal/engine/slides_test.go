@@ -0,0 +1,271 @@
+package engine
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/config"
+	"github.com/ivlev/pdf2video/internal/director"
+	"github.com/ivlev/pdf2video/internal/effects"
+	"github.com/ivlev/pdf2video/internal/source"
+)
+
+func TestSlideOverrides(t *testing.T) {
+	cut, long, noOutro := 0.0, 1.5, 0.0
+	scenario := &director.Scenario{
+		Version: director.ScenarioVersion,
+		Slides: []director.Slide{
+			{ID: 1, Duration: 4, Transition: "none"},
+			{ID: 2, Duration: 5, Transition: "wipeleft", Fade: &long},
+			{ID: 3, Duration: 5, Fade: &cut, Outro: &noOutro, ZoomMode: "top-left"},
+			{ID: 4, Duration: 5},
+		},
+	}
+	cfg := &config.Config{
+		TransitionType:        "fade",
+		FadeDuration:          0.5,
+		OutroDuration:         1.0,
+		ZoomMode:              "center",
+		BlackScreenDuration:   2.0,
+		BlackScreenTransition: "fade",
+	}
+	p := &VideoProject{Config: cfg, Effect: effects.NewScenarioEffect(scenario)}
+
+	tests := []struct {
+		page  int
+		trans string
+		fade  float64
+	}{
+		{0, "none", 0},       // Hard cut into the title slide
+		{1, "wipeleft", 1.5}, // Section wipe
+		{2, "none", 0},       // fade: 0 is a cut
+		{3, "fade", 0.5},     // Global settings
+	}
+	for _, tt := range tests {
+		trans, fade := p.transitionInto(tt.page)
+		if trans != tt.trans || fade != tt.fade {
+			t.Errorf("page %d: expected %s/%.2f, got %s/%.2f", tt.page, tt.trans, tt.fade, trans, fade)
+		}
+	}
+
+	// The camera of page 1 must settle before the wipe into page 2 starts
+	if got := p.fadeOut(0, 4); got != 1.5 {
+		t.Errorf("expected fade out of page 1 = 1.5, got %.2f", got)
+	}
+	if got := p.totalFade(4); math.Abs(got-2.0) > 1e-9 {
+		t.Errorf("expected total overlap 2.0, got %.2f", got)
+	}
+	if got := p.outroDuration(2); got != 0 {
+		t.Errorf("expected outro override 0, got %.2f", got)
+	}
+	if got := p.outroDuration(3); got != 1.0 {
+		t.Errorf("expected global outro 1.0, got %.2f", got)
+	}
+	if got := p.zoomModeFor(2); got != "top-left" {
+		t.Errorf("expected zoom mode override, got %s", got)
+	}
+}
+
+func TestSlideOverridesWithoutScenario(t *testing.T) {
+	cfg := &config.Config{TransitionType: "slideup", FadeDuration: 0.7, ZoomMode: "center"}
+	p := &VideoProject{Config: cfg, Effect: &effects.DefaultEffect{}}
+
+	if trans, fade := p.transitionInto(0); trans != "none" || fade != 0 {
+		t.Errorf("expected no transition into the first page without a black screen, got %s/%.2f", trans, fade)
+	}
+	if trans, fade := p.transitionInto(1); trans != "slideup" || fade != 0.7 {
+		t.Errorf("expected global transition, got %s/%.2f", trans, fade)
+	}
+}
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSlideSource(t *testing.T) {
+	dir := t.TempDir()
+	deck := filepath.Join(dir, "deck")
+	os.Mkdir(deck, 0755)
+	for i, w := range []int{100, 200, 300} {
+		writePNG(t, filepath.Join(deck, fmt.Sprintf("p%d.png", i+1)), w, 50)
+	}
+	writePNG(t, filepath.Join(dir, "cover.png"), 640, 480)
+
+	main, err := source.NewImageSource(deck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{InputPath: deck, ScenarioInput: filepath.Join(dir, "scenario.yaml")}
+	p := &VideoProject{Config: cfg, Source: main}
+
+	scenario := &director.Scenario{Slides: []director.Slide{
+		{Input: "cover.png"},   // File next to the scenario
+		{Input: ""},            // Page by position
+		{Input: "#3"},          // Page of the main input
+		{Input: "slide_1.png"}, // Legacy name
+		{Input: "#3"},          // Repeated page
+	}}
+	slides, err := p.slideSource(scenario)
+	if err != nil {
+		t.Fatalf("slideSource failed: %v", err)
+	}
+	defer slides.Close()
+
+	if slides.PageCount() != 5 {
+		t.Fatalf("expected 5 slides, got %d", slides.PageCount())
+	}
+	for i, want := range []float64{640, 200, 300, 100, 300} {
+		if w, _, err := slides.GetPageDimensions(i); err != nil || w != want {
+			t.Errorf("slide %d: expected page width %.0f, got %.0f (%v)", i+1, want, w, err)
+		}
+	}
+
+	for _, input := range []string{"missing.png", "#9"} {
+		bad := &director.Scenario{Slides: []director.Slide{{Input: input}}}
+		if _, err := p.slideSource(bad); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestSlideSourceWithPages(t *testing.T) {
+	dir := t.TempDir()
+	for i, w := range []int{100, 200, 300, 400} {
+		writePNG(t, filepath.Join(dir, fmt.Sprintf("p%d.png", i+1)), w, 50)
+	}
+	main, err := source.NewImageSource(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{InputPath: dir, Pages: "1-3,!2"}
+	p := &VideoProject{Config: cfg, Source: main}
+	if err := p.selectPages(); err != nil {
+		t.Fatalf("selectPages failed: %v", err)
+	}
+	if p.Source.PageCount() != 2 || p.pageNumber(1) != 3 {
+		t.Fatalf("expected pages 1 and 3, got %d pages, second is %d", p.Source.PageCount(), p.pageNumber(1))
+	}
+
+	// Slides refer to original page numbers; slides of excluded pages are dropped
+	scenario := &director.Scenario{Slides: []director.Slide{
+		{ID: 1, Input: "#3"},
+		{ID: 2, Input: "#2"},
+		{ID: 3, Input: ""}, // Page by position: page 3
+		{ID: 4, Input: "#4"},
+		{ID: 5, Input: "#1"},
+	}}
+	slides, err := p.slideSource(scenario)
+	if err != nil {
+		t.Fatalf("slideSource failed: %v", err)
+	}
+	defer slides.Close()
+
+	if slides.PageCount() != 3 || len(scenario.Slides) != 3 {
+		t.Fatalf("expected 3 slides, got %d pages and %d slides", slides.PageCount(), len(scenario.Slides))
+	}
+	for i, want := range []float64{300, 300, 100} {
+		if w, _, err := slides.GetPageDimensions(i); err != nil || w != want {
+			t.Errorf("slide %d: expected page width %.0f, got %.0f (%v)", i+1, want, w, err)
+		}
+	}
+	if scenario.Slides[2].ID != 5 {
+		t.Errorf("expected the slide of page 1 last, got slide %d", scenario.Slides[2].ID)
+	}
+
+	bad := &director.Scenario{Slides: []director.Slide{{Input: "#9"}}}
+	if _, err := p.slideSource(bad); err == nil {
+		t.Error("expected an error for a page out of the document")
+	}
+	excluded := &director.Scenario{Slides: []director.Slide{{Input: "#2"}}}
+	if _, err := p.slideSource(excluded); err == nil {
+		t.Error("expected an error when every slide is excluded")
+	}
+}
+
+func TestSlideSourceMultipleInputs(t *testing.T) {
+	dir := t.TempDir()
+	intro, deck := filepath.Join(dir, "intro"), filepath.Join(dir, "deck")
+	os.Mkdir(intro, 0755)
+	os.Mkdir(deck, 0755)
+	for i, w := range []int{100, 200} {
+		writePNG(t, filepath.Join(intro, fmt.Sprintf("p%d.png", i+1)), w, 50)
+	}
+	for i, w := range []int{300, 400, 500} {
+		writePNG(t, filepath.Join(deck, fmt.Sprintf("p%d.png", i+1)), w, 50)
+	}
+
+	main, err := source.OpenInputs([]string{intro, deck}, source.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer main.Close()
+	cfg := &config.Config{InputPath: intro, InputPaths: []string{intro, deck}, ScenarioInput: filepath.Join(dir, "scenario.yaml")}
+	p := &VideoProject{Config: cfg, Source: main}
+
+	// A page of an input is its own page, taken from the main source
+	scenario := &director.Scenario{Slides: []director.Slide{
+		{Input: "deck#2"},
+		{Input: "intro#1"},
+		{Input: "#3"}, // Page of the whole main source: the first page of deck
+	}}
+	slides, err := p.slideSource(scenario)
+	if err != nil {
+		t.Fatalf("slideSource failed: %v", err)
+	}
+	defer slides.Close()
+	for i, want := range []float64{400, 100, 300} {
+		if w, _, err := slides.GetPageDimensions(i); err != nil || w != want {
+			t.Errorf("slide %d: expected page width %.0f, got %.0f (%v)", i+1, want, w, err)
+		}
+	}
+
+	// Pages are counted within the input, not the main source
+	bad := &director.Scenario{Slides: []director.Slide{{Input: "intro#3"}}}
+	if _, err := p.slideSource(bad); err == nil {
+		t.Error("expected an error for a page beyond the input")
+	}
+}
+
+func TestApplySafeArea(t *testing.T) {
+	cfg := &config.Config{
+		Width: 1080, Height: 1920,
+		SafeArea:   "0.1,0.15,0.25,0",
+		Exclusions: "0,0,0.5,0.05",
+		QREnabled:  true, QRURL: "example.com", QRSize: 300, QRMarginRight: 20, QRMarginBottom: 40,
+	}
+	p := &VideoProject{Config: cfg}
+	dir := director.NewDirector(cfg.Width, cfg.Height)
+	if err := p.applySafeArea(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if dir.SafeArea != (director.Insets{Top: 0.1, Right: 0.15, Bottom: 0.25}) {
+		t.Errorf("unexpected safe area %+v", dir.SafeArea)
+	}
+	want := []director.Rectangle{
+		{X: 0, Y: 0, W: 540, H: 96},
+		{X: 760, Y: 1580, W: 300, H: 300}, // QR code in the bottom-right corner
+	}
+	if fmt.Sprint(dir.Exclusions) != fmt.Sprint(want) {
+		t.Errorf("expected exclusions %v, got %v", want, dir.Exclusions)
+	}
+
+	cfg.QREnabled = false
+	dir = director.NewDirector(cfg.Width, cfg.Height)
+	if err := p.applySafeArea(dir); err != nil || len(dir.Exclusions) != 1 {
+		t.Errorf("disabled QR code must not be excluded: %v %v", err, dir.Exclusions)
+	}
+}
@@ -0,0 +1,75 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/ivlev/pdf2video/internal/director"
+	"github.com/ivlev/pdf2video/internal/system"
+)
+
+// DrawAnnotations draws the annotations visible at time t over a frame that shows
+// the viewport window win. Annotation geometry is in viewport pixels, so shapes
+// follow the page under the camera and line widths grow with the zoom.
+func DrawAnnotations(p *Painter, annotations []director.TimedAnnotation, t float64, win Window, width, height int) {
+	if win.W <= 0 || win.H <= 0 {
+		return
+	}
+	sx, sy := float64(width)/win.W, float64(height)/win.H
+	toFrame := func(v director.Point) (float64, float64) {
+		return (v.X - win.X) * sx, (v.Y - win.Y) * sy
+	}
+	toFrameRect := func(r director.Rectangle) director.Rectangle {
+		return director.Rectangle{X: (r.X - win.X) * sx, Y: (r.Y - win.Y) * sy, W: r.W * sx, H: r.H * sy}
+	}
+
+	for _, a := range annotations {
+		opacity := a.Opacity(t)
+		if opacity <= 0 {
+			continue
+		}
+		c := WithOpacity(annotationColor(a.ColorOrDefault()), opacity)
+		stroke := a.StrokeWidth() * sx
+
+		switch a.Type {
+		case director.AnnotationBox:
+			p.Rect(toFrameRect(a.Rect), stroke, c)
+		case director.AnnotationCircle:
+			p.Ellipse(toFrameRect(a.Rect), stroke, c)
+		case director.AnnotationArrow:
+			x0, y0 := toFrame(a.From)
+			x1, y1 := toFrame(a.To)
+			p.Arrow(x0, y0, x1, y1, stroke, c)
+		case director.AnnotationLabel:
+			x, y := toFrame(a.At)
+			size := a.Size * sy
+			if a.Background != "" {
+				w, h := p.TextSize(a.Text, size)
+				pad := size * 0.25
+				plate := director.Rectangle{X: x - pad, Y: y - pad, W: w + 2*pad, H: h + 2*pad}
+				p.FillRect(plate, WithOpacity(annotationColor(a.Background), opacity))
+			}
+			p.Text(a.Text, x, y, size, c)
+		}
+	}
+}
+
+// annotationColor parses an annotation color; invalid colors are reported by the linter
+func annotationColor(s string) color.RGBA {
+	c, err := system.ParseHexColor(s)
+	if err != nil {
+		c, _ = system.ParseHexColor(director.DefaultAnnotationColor)
+	}
+	return c
+}
+
+// renderAnnotations draws the renderer annotations over a rendered frame
+func (r *FrameRenderer) renderAnnotations(t float64, win Window, dst *image.RGBA) {
+	if len(r.Annotations) == 0 {
+		return
+	}
+	if r.painter == nil || r.painter.Dst != dst {
+		r.painter = NewPainter(dst)
+	}
+	DrawAnnotations(r.painter, r.Annotations, t, win, dst.Rect.Dx(), dst.Rect.Dy())
+}
@@ -0,0 +1,240 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/ivlev/pdf2video/internal/director"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Painter draws anti-aliased shapes and text onto an RGBA image in its pixel
+// coordinates. Strokes are centered on the outline. A Painter reuses its
+// rasterizer and font faces and must not be shared between goroutines.
+type Painter struct {
+	Dst *image.RGBA
+
+	z     vector.Rasterizer
+	faces map[int]font.Face // Keyed by the text height in whole pixels
+}
+
+// NewPainter creates a painter for dst
+func NewPainter(dst *image.RGBA) *Painter {
+	return &Painter{Dst: dst, faces: make(map[int]font.Face)}
+}
+
+type vec struct{ x, y float64 }
+
+// Line strokes a segment with butt ends
+func (p *Painter) Line(x0, y0, x1, y1, width float64, c color.Color) {
+	dx, dy := x1-x0, y1-y0
+	length := math.Hypot(dx, dy)
+	if length == 0 || width <= 0 {
+		return
+	}
+	nx, ny := -dy/length*width/2, dx/length*width/2
+	p.fill(c, []vec{{x0 + nx, y0 + ny}, {x1 + nx, y1 + ny}, {x1 - nx, y1 - ny}, {x0 - nx, y0 - ny}})
+}
+
+// Arrow strokes a segment from the tail (x0, y0) with an arrowhead at (x1, y1)
+func (p *Painter) Arrow(x0, y0, x1, y1, width float64, c color.Color) {
+	dx, dy := x1-x0, y1-y0
+	length := math.Hypot(dx, dy)
+	if length == 0 || width <= 0 {
+		return
+	}
+	ux, uy := dx/length, dy/length
+	head := math.Min(math.Max(4*width, 12), length)
+	half := head * 0.6
+	bx, by := x1-ux*head, y1-uy*head
+
+	// The shaft ends inside the head so the two shapes join without a seam
+	p.Line(x0, y0, bx+ux*head/2, by+uy*head/2, width, c)
+	p.fill(c, []vec{{x1, y1}, {bx - uy*half, by + ux*half}, {bx + uy*half, by - ux*half}})
+}
+
+// Rect strokes the outline of a rectangle
+func (p *Painter) Rect(r director.Rectangle, width float64, c color.Color) {
+	if width <= 0 || r.W <= 0 || r.H <= 0 {
+		return
+	}
+	h := width / 2
+	outer := rectPath(r.X-h, r.Y-h, r.W+width, r.H+width)
+	if r.W <= width || r.H <= width {
+		p.fill(c, outer)
+		return
+	}
+	p.fill(c, outer, reversed(rectPath(r.X+h, r.Y+h, r.W-width, r.H-width)))
+}
+
+// Ellipse strokes the ellipse inscribed into a rectangle
+func (p *Painter) Ellipse(r director.Rectangle, width float64, c color.Color) {
+	if width <= 0 || r.W <= 0 || r.H <= 0 {
+		return
+	}
+	cx, cy := r.X+r.W/2, r.Y+r.H/2
+	rx, ry := r.W/2, r.H/2
+	outer := ellipsePath(cx, cy, rx+width/2, ry+width/2)
+	if rx <= width/2 || ry <= width/2 {
+		p.fill(c, outer)
+		return
+	}
+	p.fill(c, outer, reversed(ellipsePath(cx, cy, rx-width/2, ry-width/2)))
+}
+
+// Circle fills a circle
+func (p *Painter) Circle(cx, cy, radius float64, c color.Color) {
+	if radius > 0 {
+		p.fill(c, ellipsePath(cx, cy, radius, radius))
+	}
+}
+
+// FillRect fills a rectangle
+func (p *Painter) FillRect(r director.Rectangle, c color.Color) {
+	if r.W > 0 && r.H > 0 {
+		p.fill(c, rectPath(r.X, r.Y, r.W, r.H))
+	}
+}
+
+// Text draws a single line of text with its top-left corner at (x, y); size is the
+// line height in pixels
+func (p *Painter) Text(s string, x, y, size float64, c color.Color) {
+	face := p.face(size)
+	if face == nil {
+		return
+	}
+	d := &font.Drawer{
+		Dst:  p.Dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.Point26_6{X: toFixed(x), Y: toFixed(y) + face.Metrics().Ascent},
+	}
+	d.DrawString(s)
+}
+
+// TextSize measures a single line of text drawn with Text
+func (p *Painter) TextSize(s string, size float64) (float64, float64) {
+	face := p.face(size)
+	if face == nil {
+		return 0, 0
+	}
+	m := face.Metrics()
+	return fromFixed(font.MeasureString(face, s)), fromFixed(m.Ascent + m.Descent)
+}
+
+// fill fills the contours with the nonzero rule: a contour wound the other way cuts a hole
+func (p *Painter) fill(c color.Color, contours ...[]vec) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, contour := range contours {
+		for _, v := range contour {
+			minX, minY = math.Min(minX, v.x), math.Min(minY, v.y)
+			maxX, maxY = math.Max(maxX, v.x), math.Max(maxY, v.y)
+		}
+	}
+	bounds := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+	bounds = bounds.Intersect(p.Dst.Bounds())
+	if bounds.Empty() {
+		return
+	}
+
+	// The rasterizer covers only the shape bounds, its origin is bounds.Min
+	p.z.Reset(bounds.Dx(), bounds.Dy())
+	ox, oy := float64(bounds.Min.X), float64(bounds.Min.Y)
+	for _, contour := range contours {
+		for i, v := range contour {
+			if i == 0 {
+				p.z.MoveTo(float32(v.x-ox), float32(v.y-oy))
+			} else {
+				p.z.LineTo(float32(v.x-ox), float32(v.y-oy))
+			}
+		}
+		p.z.ClosePath()
+	}
+	p.z.Draw(p.Dst, bounds, image.NewUniform(c), image.Point{})
+}
+
+// face returns the text face for a line height rounded to whole pixels, so animated
+// text sizes share a few faces instead of growing the cache every frame
+func (p *Painter) face(size float64) font.Face {
+	key := int(math.Round(size))
+	if key < 1 {
+		return nil
+	}
+	if face, ok := p.faces[key]; ok {
+		return face
+	}
+	f, err := regularFont()
+	if err != nil {
+		return nil
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    float64(key),
+		DPI:     72, // Size in points equals size in pixels
+		Hinting: font.HintingNone,
+	})
+	if err != nil {
+		return nil
+	}
+	p.faces[key] = face
+	return face
+}
+
+var (
+	fontOnce sync.Once
+	fontGo   *opentype.Font
+	fontErr  error
+)
+
+// regularFont parses the embedded Go Regular font once
+func regularFont() (*opentype.Font, error) {
+	fontOnce.Do(func() {
+		fontGo, fontErr = opentype.Parse(goregular.TTF)
+	})
+	return fontGo, fontErr
+}
+
+func rectPath(x, y, w, h float64) []vec {
+	return []vec{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}
+}
+
+// ellipsePath approximates an ellipse with a polygon fine enough for its size
+func ellipsePath(cx, cy, rx, ry float64) []vec {
+	n := int(math.Max(math.Ceil(math.Max(rx, ry)), 16))
+	if n > 256 {
+		n = 256
+	}
+	path := make([]vec, n)
+	for i := range path {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		path[i] = vec{cx + rx*math.Cos(a), cy + ry*math.Sin(a)}
+	}
+	return path
+}
+
+func reversed(path []vec) []vec {
+	out := make([]vec, len(path))
+	for i, v := range path {
+		out[len(path)-1-i] = v
+	}
+	return out
+}
+
+// WithOpacity applies an opacity (0..1) to a color
+func WithOpacity(c color.RGBA, opacity float64) color.NRGBA {
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: uint8(math.Round(float64(c.A) * math.Max(0, math.Min(opacity, 1))))}
+}
+
+func toFixed(v float64) fixed.Int26_6 {
+	return fixed.Int26_6(math.Round(v * 64))
+}
+
+func fromFixed(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}
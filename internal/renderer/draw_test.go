@@ -0,0 +1,113 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+func TestPainterRectAntialiased(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	fill(dst, color.RGBA{A: 255})
+	red := color.RGBA{R: 255, A: 255}
+
+	// A 2px stroke centered on x = 10.5 covers [9.5, 11.5]: pixels 9 and 11 are half covered
+	NewPainter(dst).Rect(director.Rectangle{X: 10.5, Y: 10.5, W: 20, H: 20}, 2, red)
+
+	if c := dst.RGBAAt(10, 20); c.R != 255 {
+		t.Errorf("expected a solid stroke at x=10, got %v", c)
+	}
+	if c := dst.RGBAAt(9, 20); c.R < 100 || c.R > 155 {
+		t.Errorf("expected a half-covered edge at x=9, got %v", c)
+	}
+	if c := dst.RGBAAt(20, 20); c.R != 0 {
+		t.Errorf("expected the inside to stay untouched, got %v", c)
+	}
+}
+
+func TestPainterEllipseAndText(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 100, 60))
+	fill(dst, color.RGBA{A: 255})
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	p := NewPainter(dst)
+
+	p.Ellipse(director.Rectangle{X: 10, Y: 10, W: 40, H: 40}, 4, white)
+	if c := dst.RGBAAt(30, 10); c.R < 200 {
+		t.Errorf("expected the ellipse outline at the top, got %v", c)
+	}
+	if c := dst.RGBAAt(30, 30); c.R != 0 {
+		t.Errorf("expected the ellipse to be hollow, got %v", c)
+	}
+
+	w, h := p.TextSize("Hi", 20)
+	if w <= 0 || h < 15 || h > 30 {
+		t.Fatalf("unexpected text size %.1fx%.1f", w, h)
+	}
+	p.Text("Hi", 60, 20, 20, white)
+	lit := 0
+	for y := 20; y < 40; y++ {
+		for x := 60; x < 60+int(w); x++ {
+			if dst.RGBAAt(x, y).R > 0 {
+				lit++
+			}
+		}
+	}
+	if lit == 0 {
+		t.Error("expected the text to be drawn below its top-left corner")
+	}
+}
+
+func TestPainterFaceCache(t *testing.T) {
+	p := NewPainter(image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	for size := 10.0; size < 30; size += 0.01 {
+		p.TextSize("Hi", size)
+	}
+	if len(p.faces) > 21 {
+		t.Errorf("cached %d faces for sizes 10-30, want one per whole pixel", len(p.faces))
+	}
+	if p.face(0.4) != nil {
+		t.Error("expected no face below one pixel")
+	}
+}
+
+func TestDrawAnnotationsFollowCamera(t *testing.T) {
+	annotations := []director.TimedAnnotation{{
+		Annotation: director.Annotation{
+			Type:   director.AnnotationBox,
+			Rect:   director.Rectangle{X: 40, Y: 20, W: 40, H: 20},
+			Color:  "#FFFFFF",
+			Stroke: 2,
+		},
+		Start: 1,
+		End:   math.Inf(1),
+	}}
+	render := func(t float64, win Window) *image.RGBA {
+		dst := image.NewRGBA(image.Rect(0, 0, 160, 90))
+		fill(dst, color.RGBA{A: 255})
+		DrawAnnotations(NewPainter(dst), annotations, t, win, 160, 90)
+		return dst
+	}
+	full := Window{W: 160, H: 90}
+
+	if c := render(0.5, full).RGBAAt(40, 30); c.R != 0 {
+		t.Errorf("annotation must not be visible before it appears, got %v", c)
+	}
+	if c := render(1.15, full).RGBAAt(40, 30); c.R == 0 || c.R == 255 {
+		t.Errorf("expected a half faded annotation, got %v", c)
+	}
+	if c := render(2, full).RGBAAt(40, 30); c.R != 255 {
+		t.Errorf("expected the box edge at x=40, got %v", c)
+	}
+
+	// Zoom 2 on the box: its left edge moves to x=0 and the stroke doubles
+	zoomed := render(2, Window{X: 40, Y: 20, W: 80, H: 45})
+	if c := zoomed.RGBAAt(1, 20); c.R != 255 {
+		t.Errorf("expected the box edge to follow the camera, got %v", c)
+	}
+	if c := zoomed.RGBAAt(40, 20); c.R != 0 {
+		t.Errorf("expected no box edge at the old position, got %v", c)
+	}
+}
@@ -0,0 +1,223 @@
+package renderer
+
+import (
+	"image"
+	"math"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+// Emphasis strengths at full effect
+const (
+	dimStrength       = 0.6  // Share of brightness removed outside the focus by dim
+	spotlightStrength = 0.78 // Same for spotlight, which is darker and round
+	emphasisFeather   = 0.03 // Soft edge of the focus region as a share of the frame's shorter side
+	blurRadiusShare   = 1.0 / 90
+)
+
+// EmphasisState is the focus emphasis at a moment of the slide
+type EmphasisState struct {
+	Mode   string
+	Amount float64            // 0 = no emphasis, 1 = full effect
+	Rect   director.Rectangle // Focused region (viewport pixels)
+}
+
+// EmphasisAt interpolates the emphasis between keyframes with the same easing as
+// the camera: a keyframe without a mode has no emphasis, so the effect eases in
+// while the camera flies to an emphasized keyframe and out while it leaves.
+// Between two emphasized keyframes the focus region travels with the camera.
+func EmphasisAt(keyframes []director.Keyframe, t float64) EmphasisState {
+	if len(keyframes) == 0 {
+		return EmphasisState{}
+	}
+	last := len(keyframes) - 1
+	if t <= keyframes[0].Time {
+		return keyframeEmphasis(keyframes[0], 1)
+	}
+	if t >= keyframes[last].Time {
+		return keyframeEmphasis(keyframes[last], 1)
+	}
+
+	i := 0
+	for i < last-1 && t >= keyframes[i+1].Time {
+		i++
+	}
+	prev, next := keyframes[i], keyframes[i+1]
+	span := next.Time - prev.Time
+	if span <= 0 {
+		return keyframeEmphasis(next, 1)
+	}
+	p := prev.EasingCurve().Apply((t - prev.Time) / span)
+
+	switch {
+	case prev.HasEmphasis() && next.HasEmphasis():
+		mode := prev.Emphasis
+		if p >= 0.5 {
+			mode = next.Emphasis
+		}
+		return EmphasisState{Mode: mode, Amount: 1, Rect: lerpRect(prev.FocusArea(), next.FocusArea(), p)}
+	case prev.HasEmphasis():
+		return keyframeEmphasis(prev, 1-p)
+	case next.HasEmphasis():
+		return keyframeEmphasis(next, p)
+	}
+	return EmphasisState{}
+}
+
+func keyframeEmphasis(kf director.Keyframe, amount float64) EmphasisState {
+	if !kf.HasEmphasis() {
+		return EmphasisState{}
+	}
+	return EmphasisState{Mode: kf.Emphasis, Amount: amount, Rect: kf.FocusArea()}
+}
+
+func lerpRect(a, b director.Rectangle, t float64) director.Rectangle {
+	return director.Rectangle{
+		X: lerp(a.X, b.X, t),
+		Y: lerp(a.Y, b.Y, t),
+		W: lerp(a.W, b.W, t),
+		H: lerp(a.H, b.H, t),
+	}
+}
+
+// renderEmphasis treats everything outside the focus region of the frame
+func (r *FrameRenderer) renderEmphasis(t float64, win Window, dst *image.RGBA) {
+	if len(r.Emphasis) == 0 || win.W <= 0 || win.H <= 0 {
+		return
+	}
+	state := EmphasisAt(r.Emphasis, t)
+	if state.Amount <= 0 {
+		return
+	}
+
+	// Focus region in dst pixels: the frame, or the larger buffer of a rolling camera, which
+	// has the same scale. The feather and the blur radius are sized by the output frame,
+	// so the emphasis looks the same either way.
+	width, height := dst.Rect.Dx(), dst.Rect.Dy()
+	sx, sy := float64(width)/win.W, float64(height)/win.H
+	focus := director.Rectangle{
+		X: (state.Rect.X - win.X) * sx,
+		Y: (state.Rect.Y - win.Y) * sy,
+		W: state.Rect.W * sx,
+		H: state.Rect.H * sy,
+	}
+	feather := emphasisFeather * math.Min(float64(r.Width), float64(r.Height))
+
+	var blurred *image.RGBA
+	if state.Mode == director.EmphasisBlur {
+		radius := int(math.Max(2, math.Round(blurRadiusShare*math.Min(float64(r.Width), float64(r.Height)))))
+		blurred = r.blurFrame(dst, radius)
+	}
+
+	weight := rectFalloff(focus, feather)
+	if state.Mode == director.EmphasisSpotlight {
+		weight = ellipseFalloff(focus, feather)
+	}
+
+	for y := 0; y < height; y++ {
+		row := dst.Pix[y*dst.Stride : y*dst.Stride+width*4]
+		fy := float64(y) + 0.5
+		for x := 0; x < width; x++ {
+			s := state.Amount * weight(float64(x)+0.5, fy)
+			if s <= 0 {
+				continue
+			}
+			o := x * 4
+			red, green, blue := float64(row[o]), float64(row[o+1]), float64(row[o+2])
+
+			switch state.Mode {
+			case director.EmphasisDim:
+				k := 1 - dimStrength*s
+				red, green, blue = red*k, green*k, blue*k
+			case director.EmphasisSpotlight:
+				k := 1 - spotlightStrength*s
+				red, green, blue = red*k, green*k, blue*k
+			case director.EmphasisDesaturate:
+				luma := 0.299*red + 0.587*green + 0.114*blue
+				red, green, blue = lerp(red, luma, s), lerp(green, luma, s), lerp(blue, luma, s)
+			case director.EmphasisBlur:
+				b := blurred.Pix[y*blurred.Stride+o:]
+				red, green, blue = lerp(red, float64(b[0]), s), lerp(green, float64(b[1]), s), lerp(blue, float64(b[2]), s)
+			}
+
+			row[o] = uint8(red + 0.5)
+			row[o+1] = uint8(green + 0.5)
+			row[o+2] = uint8(blue + 0.5)
+		}
+	}
+}
+
+// rectFalloff returns the emphasis weight around a rectangle: 0 inside,
+// rising smoothly to 1 over the feather distance outside
+func rectFalloff(focus director.Rectangle, feather float64) func(x, y float64) float64 {
+	return func(x, y float64) float64 {
+		dx := math.Max(math.Max(focus.X-x, x-(focus.X+focus.W)), 0)
+		dy := math.Max(math.Max(focus.Y-y, y-(focus.Y+focus.H)), 0)
+		if dx == 0 && dy == 0 {
+			return 0
+		}
+		return smoothstep(math.Hypot(dx, dy) / feather)
+	}
+}
+
+// ellipseFalloff is rectFalloff for the ellipse around the rectangle (through its corners)
+func ellipseFalloff(focus director.Rectangle, feather float64) func(x, y float64) float64 {
+	cx, cy := focus.X+focus.W/2, focus.Y+focus.H/2
+	rx, ry := focus.W/2*math.Sqrt2, focus.H/2*math.Sqrt2
+	minR := math.Min(rx, ry)
+	return func(x, y float64) float64 {
+		if rx <= 0 || ry <= 0 {
+			return 1
+		}
+		q := math.Hypot((x-cx)/rx, (y-cy)/ry)
+		if q <= 1 {
+			return 0
+		}
+		return smoothstep((q - 1) * minR / (2 * feather))
+	}
+}
+
+func smoothstep(t float64) float64 {
+	if t >= 1 {
+		return 1
+	}
+	return t * t * (3 - 2*t)
+}
+
+// blurFrame returns a blurred copy of the frame (two box passes, close to a gaussian)
+func (r *FrameRenderer) blurFrame(src *image.RGBA, radius int) *image.RGBA {
+	if r.blurred == nil || r.blurred.Rect != src.Rect {
+		r.blurred = image.NewRGBA(src.Rect)
+		r.blurTmp = image.NewRGBA(src.Rect)
+	}
+	copy(r.blurred.Pix, src.Pix)
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	for pass := 0; pass < 2; pass++ {
+		boxBlur(r.blurTmp, r.blurred, radius, 4, r.blurred.Stride, width, height)
+		boxBlur(r.blurred, r.blurTmp, radius, r.blurred.Stride, 4, height, width)
+	}
+	return r.blurred
+}
+
+// boxBlur averages src along one axis into dst: step is the byte distance between
+// neighbouring samples, lineStep between lines; the edges are clamped
+func boxBlur(dst, src *image.RGBA, radius, step, lineStep, length, lines int) {
+	window := 2*radius + 1
+	for l := 0; l < lines; l++ {
+		base := l * lineStep
+		for c := 0; c < 3; c++ {
+			at := func(i int) int {
+				i = max(0, min(i, length-1))
+				return int(src.Pix[base+i*step+c])
+			}
+			sum := 0
+			for i := -radius; i <= radius; i++ {
+				sum += at(i)
+			}
+			for i := 0; i < length; i++ {
+				dst.Pix[base+i*step+c] = uint8((sum + window/2) / window)
+				sum += at(i+radius+1) - at(i-radius)
+			}
+		}
+	}
+}
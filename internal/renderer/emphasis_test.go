@@ -0,0 +1,111 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+func emphasisKeyframes(mode string) []director.Keyframe {
+	full := director.Rectangle{W: 160, H: 90}
+	block := director.Rectangle{X: 60, Y: 30, W: 40, H: 30}
+	return []director.Keyframe{
+		{Time: 0, Zoom: 1, Rect: full},
+		{Time: 1, Zoom: 1, Rect: block, Emphasis: mode},
+		{Time: 2, Zoom: 1, Rect: block, Emphasis: mode},
+		{Time: 3, Zoom: 1, Rect: full},
+	}
+}
+
+func TestEmphasisAtEasesWithCamera(t *testing.T) {
+	keyframes := emphasisKeyframes(director.EmphasisDim)
+	tests := []struct {
+		t      float64
+		amount float64
+	}{
+		{0, 0},
+		{0.5, 0.5}, // Eased midpoint of the flight in
+		{1, 1},
+		{1.5, 1}, // Hold
+		{2.5, 0.5},
+		{3, 0},
+	}
+	for _, tt := range tests {
+		got := EmphasisAt(keyframes, tt.t)
+		if math.Abs(got.Amount-tt.amount) > 1e-9 {
+			t.Errorf("t=%.1f: expected amount %.2f, got %.2f", tt.t, tt.amount, got.Amount)
+		}
+		if tt.amount > 0 && (got.Mode != director.EmphasisDim || got.Rect != keyframes[1].Rect) {
+			t.Errorf("t=%.1f: expected dim on the block, got %+v", tt.t, got)
+		}
+	}
+
+	// The focus region travels between two emphasized keyframes
+	moved := emphasisKeyframes(director.EmphasisBlur)
+	moved[2].Rect.X = 100
+	if got := EmphasisAt(moved, 1.5); got.Amount != 1 || got.Rect.X != 80 {
+		t.Errorf("expected the focus halfway at x=80 with full amount, got %+v", got)
+	}
+
+	// A camera window around the block still emphasizes the block
+	framed := emphasisKeyframes(director.EmphasisDim)
+	framed[1].FocusRect, framed[1].Rect = framed[1].Rect, director.Rectangle{X: 40, Y: 15, W: 80, H: 45}
+	if got := EmphasisAt(framed, 1); got.Rect != framed[1].FocusRect {
+		t.Errorf("expected the focused block %+v, got %+v", framed[1].FocusRect, got.Rect)
+	}
+
+	keyframes[1].Emphasis = director.EmphasisNone
+	keyframes[2].Emphasis = director.EmphasisNone
+	if got := EmphasisAt(keyframes, 1.5); got.Amount != 0 {
+		t.Errorf("emphasis none must not emphasize, got %+v", got)
+	}
+}
+
+func TestFrameRendererEmphasis(t *testing.T) {
+	for _, mode := range []string{director.EmphasisDim, director.EmphasisDesaturate, director.EmphasisSpotlight, director.EmphasisBlur} {
+		src := image.NewRGBA(image.Rect(0, 0, 160, 90))
+		fill(src, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		// A sharp stripe outside the focus shows the blur
+		for y := 0; y < 90; y++ {
+			src.SetRGBA(10, y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+		}
+
+		keyframes := emphasisKeyframes(mode)
+		fr := NewFrameRenderer(src, keyframes, 160, 90, 10)
+		fr.Emphasis = keyframes
+		dst := image.NewRGBA(image.Rect(0, 0, 160, 90))
+		if err := fr.RenderFrame(15, dst); err != nil { // t = 1.5, full emphasis at zoom 1
+			t.Fatal(err)
+		}
+
+		if c := dst.RGBAAt(80, 45); c != (color.RGBA{R: 200, G: 100, B: 50, A: 255}) {
+			t.Errorf("%s: the focus must stay untouched, got %v", mode, c)
+		}
+		outside, stripe := dst.RGBAAt(150, 80), dst.RGBAAt(10, 45)
+		switch mode {
+		case director.EmphasisDim, director.EmphasisSpotlight:
+			if outside.R >= 100 {
+				t.Errorf("%s: expected darkened surroundings, got %v", mode, outside)
+			}
+		case director.EmphasisDesaturate:
+			if outside.R != outside.G || outside.G != outside.B {
+				t.Errorf("%s: expected gray surroundings, got %v", mode, outside)
+			}
+		case director.EmphasisBlur:
+			if stripe.G >= 200 || stripe.G <= 100 {
+				t.Errorf("%s: expected the stripe blurred into the background, got %v", mode, stripe)
+			}
+		}
+
+		// No emphasis before the camera arrives
+		if err := fr.RenderFrame(0, dst); err != nil {
+			t.Fatal(err)
+		}
+		if c := dst.RGBAAt(150, 80); c != (color.RGBA{R: 200, G: 100, B: 50, A: 255}) {
+			t.Errorf("%s: expected no emphasis at the start, got %v", mode, c)
+		}
+	}
+}
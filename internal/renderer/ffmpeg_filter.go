@@ -2,6 +2,7 @@ package renderer
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/ivlev/pdf2video/internal/director"
@@ -9,6 +10,17 @@ import (
 
 // GenerateZoomPanFilter creates FFmpeg zoompan filter for scenario-based rendering
 func GenerateZoomPanFilter(keyframes []director.Keyframe, duration float64, fps int, width, height int) string {
+	return generateZoomPanFilter(keyframes, duration, fps, width, height, false)
+}
+
+// GenerateCanvasZoomPanFilter is GenerateZoomPanFilter for cameras that must land exactly
+// on the page of an upscaled input (reframing, scrolling): the pan is converted from
+// viewport pixels into input pixels
+func GenerateCanvasZoomPanFilter(keyframes []director.Keyframe, duration float64, fps int, width, height int) string {
+	return generateZoomPanFilter(keyframes, duration, fps, width, height, true)
+}
+
+func generateZoomPanFilter(keyframes []director.Keyframe, duration float64, fps int, width, height int, canvas bool) string {
 	if len(keyframes) == 0 {
 		return ""
 	}
@@ -20,11 +32,30 @@ func GenerateZoomPanFilter(keyframes []director.Keyframe, duration float64, fps
 
 	// Build zoompan filter with piecewise expressions
 	zoomExpr := buildZoomExpression(keyframes, fps)
-	xExpr := buildPanExpression(keyframes, fps, width, true, "zoom")
-	yExpr := buildPanExpression(keyframes, fps, height, false, "zoom")
+	margin := director.MaxRotationMargin(keyframes, width, height)
+	if margin <= 1 {
+		xExpr := buildPanExpression(keyframes, fps, width, true, "zoom", 1, canvas)
+		yExpr := buildPanExpression(keyframes, fps, height, false, "zoom", 1, canvas)
 
-	return fmt.Sprintf("zoompan=z='%s':x='%s':y='%s':d=%d:s=%dx%d:fps=%d",
-		zoomExpr, xExpr, yExpr, totalFrames, width, height, fps)
+		return fmt.Sprintf("zoompan=z='%s':x='%s':y='%s':d=%d:s=%dx%d:fps=%d",
+			zoomExpr, xExpr, yExpr, totalFrames, width, height, fps)
+	}
+
+	// A rolling camera: the input gets black margins, zoompan cuts a window that much
+	// larger than the frame, and rotate turns it and crops the frame from its center.
+	// The keyframes keep the rotated frame on the page, so the margins never show.
+	bufW := evenCeil(float64(width) * margin)
+	bufH := evenCeil(float64(height) * margin)
+	kx := float64(bufW) / float64(width)
+	ky := float64(bufH) / float64(height)
+	xExpr := buildPanExpression(keyframes, fps, width, true, "zoom", kx, true)
+	yExpr := buildPanExpression(keyframes, fps, height, false, "zoom", ky, true)
+	angleExpr := buildValueExpression(keyframes, fps, "n", func(kf director.Keyframe) float64 { return kf.Rotation })
+
+	return fmt.Sprintf("pad=w=ceil(iw*%.6f/2)*2:h=ceil(ih*%.6f/2)*2:x=(ow-iw)/2:y=(oh-ih)/2:color=black,"+
+		"zoompan=z='%s':x='%s':y='%s':d=%d:s=%dx%d:fps=%d,"+
+		"rotate=a='(%s)*PI/180':ow=%d:oh=%d:c=black",
+		kx, ky, zoomExpr, xExpr, yExpr, totalFrames, bufW, bufH, fps, angleExpr, width, height)
 }
 
 // GenerateDebugBoxFilter creates a drawbox filter that matches the zoompan focus
@@ -34,8 +65,8 @@ func GenerateDebugBoxFilter(keyframes []director.Keyframe, fps int, width, heigh
 	}
 	zoomInverse := buildZoomExpression(keyframes, fps)
 	// For drawbox, we can't use 'zoom' variable, must use explicit expression
-	xPan := buildPanExpression(keyframes, fps, width, true, "")
-	yPan := buildPanExpression(keyframes, fps, height, false, "")
+	xPan := buildPanExpression(keyframes, fps, width, true, "", 1, false)
+	yPan := buildPanExpression(keyframes, fps, height, false, "", 1, false)
 
 	return fmt.Sprintf("drawbox=x='%s':y='%s':w='iw/(%s)':h='ih/(%s)':color=red:t=5",
 		xPan, yPan, zoomInverse, zoomInverse)
@@ -43,42 +74,68 @@ func GenerateDebugBoxFilter(keyframes []director.Keyframe, fps int, width, heigh
 
 // buildZoomExpression creates piecewise zoom expression for FFmpeg
 func buildZoomExpression(keyframes []director.Keyframe, fps int) string {
+	return buildValueExpression(keyframes, fps, "on", func(kf director.Keyframe) float64 { return kf.Zoom })
+}
+
+// buildValueExpression creates a piecewise expression of a keyframe value over the
+// frame number variable (on in zoompan, n in the filters after it)
+func buildValueExpression(keyframes []director.Keyframe, fps int, frameVar string, value func(director.Keyframe) float64) string {
 	if len(keyframes) == 0 {
 		return "1"
 	}
 	if len(keyframes) == 1 {
-		return fmt.Sprintf("%.6f", keyframes[0].Zoom)
+		return fmt.Sprintf("%.6f", value(keyframes[0]))
 	}
 
 	exprParts := []string{}
 	for i := 0; i < len(keyframes)-1; i++ {
 		startFrame := int(keyframes[i].Time * float64(fps))
 		endFrame := int(keyframes[i+1].Time * float64(fps))
-		startZoom := keyframes[i].Zoom
-		endZoom := keyframes[i+1].Zoom
+		startValue := value(keyframes[i])
+		endValue := value(keyframes[i+1])
 
 		if endFrame > startFrame {
-			// val = startZoom + (on - startFrame) * (endZoom - startZoom) / (endFrame - startFrame)
-			part := fmt.Sprintf("between(on,%d,%d)*(%.6f+(on-%d)*(%.6f-%.6f)/(%d))",
-				startFrame, endFrame-1, startZoom, startFrame, endZoom, startZoom, endFrame-startFrame)
+			// val = startValue + (endValue - startValue) * E((on - startFrame) / (endFrame - startFrame))
+			part := fmt.Sprintf("between(%s,%d,%d)*%s",
+				frameVar, startFrame, endFrame-1, segmentExpr(frameVar, keyframes[i], startValue, endValue, startFrame, endFrame))
 			exprParts = append(exprParts, part)
 		}
 	}
 
 	// Add final value for frame >= last keyframe
 	lastFrame := int(keyframes[len(keyframes)-1].Time * float64(fps))
-	lastZoom := keyframes[len(keyframes)-1].Zoom
-	exprParts = append(exprParts, fmt.Sprintf("gte(on,%d)*%.6f", lastFrame, lastZoom))
+	lastValue := value(keyframes[len(keyframes)-1])
+	exprParts = append(exprParts, fmt.Sprintf("gte(%s,%d)*%.6f", frameVar, lastFrame, lastValue))
 
 	return strings.Join(exprParts, "+")
 }
 
-// buildPanExpression creates piecewise pan expression for X or Y axis
-func buildPanExpression(keyframes []director.Keyframe, fps int, dimension int, isX bool, zoomVar string) string {
+// buildPanExpression creates piecewise pan expression for X or Y axis.
+// Keyframes are in viewport pixels (dimension). On a canvas the filter input is upscaled,
+// so the window position is converted to input pixels (iw/ih). A margin above 1 means
+// the input is padded around the viewport to margin times its size and the window
+// is that much larger than the frame.
+func buildPanExpression(keyframes []director.Keyframe, fps int, dimension int, isX bool, zoomVar string, margin float64, canvas bool) string {
 	if len(keyframes) == 0 {
 		return "0"
 	}
 
+	inputVar := "ih"
+	if isX {
+		inputVar = "iw"
+	}
+
+	// window turns a viewport center expression into the window origin for the zoom zv
+	window := func(center, zv string) string {
+		switch {
+		case margin > 1:
+			return fmt.Sprintf("(%s+%.6f)*%s/%.6f-(%s/%s)/2", center, float64(dimension)*(margin-1)/2, inputVar, float64(dimension)*margin, inputVar, zv)
+		case canvas:
+			return fmt.Sprintf("%s*%s/%d-(%s/%s)/2", center, inputVar, dimension, inputVar, zv)
+		}
+		return fmt.Sprintf("%s-(%d/%s)/2", center, dimension, zv)
+	}
+
 	// Helper to get zoom expression for a specific interval or use variable
 	getZoomForInterval := func(i int) string {
 		if zoomVar != "" {
@@ -92,8 +149,7 @@ func buildPanExpression(keyframes []director.Keyframe, fps int, dimension int, i
 		if endFrame <= startFrame {
 			return fmt.Sprintf("%.6f", startZoom)
 		}
-		return fmt.Sprintf("(%.6f+(on-%d)*(%.6f-%.6f)/(%d))",
-			startZoom, startFrame, endZoom, startZoom, endFrame-startFrame)
+		return segmentExpr("on", keyframes[i], startZoom, endZoom, startFrame, endFrame)
 	}
 
 	if len(keyframes) == 1 {
@@ -102,7 +158,7 @@ func buildPanExpression(keyframes []director.Keyframe, fps int, dimension int, i
 		if zv == "" {
 			zv = fmt.Sprintf("%.6f", keyframes[0].Zoom)
 		}
-		return fmt.Sprintf("%.6f-(%d/%s)/2", center, dimension, zv)
+		return window(fmt.Sprintf("%.6f", center), zv)
 	}
 
 	exprParts := []string{}
@@ -114,9 +170,9 @@ func buildPanExpression(keyframes []director.Keyframe, fps int, dimension int, i
 
 		if endFrame > startFrame {
 			zv := getZoomForInterval(i)
-			// center = startCenter + (on - startFrame) * (endCenter - startCenter) / (endFrame - startFrame)
-			part := fmt.Sprintf("between(on,%d,%d)*(%.6f+(on-%d)*(%.6f-%.6f)/(%d)-(%d/%s)/2)",
-				startFrame, endFrame-1, startCenter, startFrame, endCenter, startCenter, endFrame-startFrame, dimension, zv)
+			// center = startCenter + (endCenter - startCenter) * E((on - startFrame) / (endFrame - startFrame))
+			part := fmt.Sprintf("between(on,%d,%d)*(%s)",
+				startFrame, endFrame-1, window(segmentExpr("on", keyframes[i], startCenter, endCenter, startFrame, endFrame), zv))
 			exprParts = append(exprParts, part)
 		}
 	}
@@ -129,7 +185,7 @@ func buildPanExpression(keyframes []director.Keyframe, fps int, dimension int, i
 	if zv == "" {
 		zv = fmt.Sprintf("%.6f", keyframes[lastIdx].Zoom)
 	}
-	exprParts = append(exprParts, fmt.Sprintf("gte(on,%d)*(%.6f-(%d/%s)/2)", lastFrame, lastCenter, dimension, zv))
+	exprParts = append(exprParts, fmt.Sprintf("gte(on,%d)*(%s)", lastFrame, window(fmt.Sprintf("%.6f", lastCenter), zv)))
 
 	return strings.Join(exprParts, "+")
 }
@@ -137,7 +193,55 @@ func buildPanExpression(keyframes []director.Keyframe, fps int, dimension int, i
 // getCenter extracts center coordinate from keyframe rectangle
 func getCenter(kf director.Keyframe, isX bool) float64 {
 	if isX {
-		return float64(kf.Rect.X + kf.Rect.W/2)
+		return kf.Rect.X + kf.Rect.W/2
+	}
+	return kf.Rect.Y + kf.Rect.H/2
+}
+
+// bezierExprSamples is the number of linear pieces that approximate a cubic-bezier easing
+const bezierExprSamples = 24
+
+// segmentExpr interpolates a value from a to b between two frames with the easing of
+// the keyframe that starts the segment, matching the interpolator
+func segmentExpr(frameVar string, kf director.Keyframe, a, b float64, startFrame, endFrame int) string {
+	progress := fmt.Sprintf("((%s-%d)/%d)", frameVar, startFrame, endFrame-startFrame)
+	return fmt.Sprintf("(%.6f+(%.6f-%.6f)*%s)", a, b, a, easingExpr(kf.EasingCurve(), progress))
+}
+
+// easingExpr builds the FFmpeg expression of an easing applied to the progress
+// expression u (0..1 inside the segment, parenthesized)
+func easingExpr(e director.Easing, u string) string {
+	if e.IsLinear() {
+		return u
+	}
+	switch e.Kind {
+	case director.EasingIn:
+		return fmt.Sprintf("pow(%s,3)", u)
+	case director.EasingOut:
+		return fmt.Sprintf("(1-pow(1-%s,3))", u)
+	case director.EasingInOut:
+		return fmt.Sprintf("if(lt(%s,0.5),4*pow(%s,3),1-pow(2-2*%s,3)/2)", u, u, u)
 	}
-	return float64(kf.Rect.Y + kf.Rect.H/2)
+
+	// A cubic-bezier has no closed form in t, so it is a piecewise linear curve
+	// through the samples: a sum of clamped ramps, one per piece
+	parts := []string{}
+	prev := 0.0
+	for k := 1; k <= bezierExprSamples; k++ {
+		x0 := float64(k-1) / bezierExprSamples
+		y := e.Apply(float64(k) / bezierExprSamples)
+		if dy := y - prev; dy != 0 {
+			parts = append(parts, fmt.Sprintf("%.6f*clip((%s-%.6f)*%d,0,1)", dy, u, x0, bezierExprSamples))
+		}
+		prev = y
+	}
+	if len(parts) == 0 {
+		return "0"
+	}
+	return "(" + strings.Join(parts, "+") + ")"
+}
+
+// evenCeil rounds a size up to an even number of pixels
+func evenCeil(v float64) int {
+	return int(math.Ceil(v/2)) * 2
 }
@@ -0,0 +1,326 @@
+package renderer
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+// FrameRenderer produces output frames in Go: for every frame it interpolates the
+// camera state, crops the matching window from the source page and resamples it
+// with sub-pixel precision. Unlike zoompan, the window position is never rounded
+// to whole pixels, so slow pans stay smooth.
+//
+// Keyframe rectangles are interpreted in viewport pixels (Width x Height), where
+// the source page is letterboxed into the viewport the same way the zoompan path does it.
+type FrameRenderer struct {
+	Keyframes []director.Keyframe
+	Width     int
+	Height    int
+	FPS       int
+	Path      PathOptions // Camera path between keyframes (linear by default)
+
+	// Emphasis keyframes carry the focus regions (viewport pixels, before reframing)
+	// and emphasis modes; nil disables the emphasis layer
+	Emphasis []director.Keyframe
+
+	// Annotations are drawn over every frame in viewport pixels and segment seconds
+	Annotations []director.TimedAnnotation
+
+	levels []*image.RGBA // Mip pyramid: levels[0] is the source, each next level is half size
+	scale  float64       // Source pixels -> viewport pixels
+	offX   float64       // Letterbox offset of the page inside the viewport
+	offY   float64
+
+	painter *Painter
+	blurred *image.RGBA // Emphasis blur buffers, reused between frames
+	blurTmp *image.RGBA
+
+	margin float64     // Window growth that covers the widest camera roll of the slide
+	rolled *image.RGBA // Unrotated frame of a rolling camera, margin times the frame size
+}
+
+// Window is the visible part of the viewport for a camera state (viewport pixels)
+type Window struct {
+	X, Y, W, H float64
+}
+
+// NewFrameRenderer prepares a renderer for a single slide
+func NewFrameRenderer(src image.Image, keyframes []director.Keyframe, width, height, fps int) *FrameRenderer {
+	r := &FrameRenderer{
+		Keyframes: keyframes,
+		Width:     width,
+		Height:    height,
+		FPS:       fps,
+	}
+
+	base := toRGBA(src)
+	srcW := float64(base.Rect.Dx())
+	srcH := float64(base.Rect.Dy())
+	if srcW > 0 && srcH > 0 {
+		r.scale = math.Min(float64(width)/srcW, float64(height)/srcH)
+		r.offX = (float64(width) - srcW*r.scale) / 2
+		r.offY = (float64(height) - srcH*r.scale) / 2
+	}
+
+	r.margin = director.MaxRotationMargin(keyframes, width, height)
+	r.levels = buildPyramid(base, r.maxMinification(keyframes))
+	return r
+}
+
+// CameraAt returns the camera state at the given time of the slide
+func (r *FrameRenderer) CameraAt(t float64) CameraState {
+	if len(r.Keyframes) == 0 {
+		return CameraState{X: float64(r.Width) / 2, Y: float64(r.Height) / 2, Zoom: 1.0}
+	}
+	return InterpolatePath(r.Keyframes, t, r.Path)
+}
+
+// WindowFor converts a camera state into the visible viewport window.
+// The window is clamped to the viewport so the camera never leaves the frame.
+func (r *FrameRenderer) WindowFor(state CameraState) Window {
+	zoom := state.Zoom
+	if zoom <= 0 {
+		zoom = 1.0
+	}
+	w := float64(r.Width) / zoom
+	h := float64(r.Height) / zoom
+
+	return Window{
+		X: clampWindow(state.X-w/2, w, float64(r.Width)),
+		Y: clampWindow(state.Y-h/2, h, float64(r.Height)),
+		W: w,
+		H: h,
+	}
+}
+
+// RenderFrame draws frame number index into dst (Width x Height, origin at 0,0)
+func (r *FrameRenderer) RenderFrame(index int, dst *image.RGBA) error {
+	fps := r.FPS
+	if fps <= 0 {
+		fps = 30
+	}
+	t := float64(index) / float64(fps)
+	state := r.CameraAt(t)
+	win := r.WindowFor(state)
+	if r.margin > 1 {
+		r.renderRolled(t, state.Rotation, win, dst)
+		return nil
+	}
+	r.renderLayers(t, win, dst)
+	return nil
+}
+
+// renderLayers draws the page window with the emphasis and annotations on top
+func (r *FrameRenderer) renderLayers(t float64, win Window, dst *image.RGBA) {
+	r.renderWindow(win, dst)
+	r.renderEmphasis(t, win, dst)
+	r.renderAnnotations(t, win, dst)
+}
+
+// renderRolled draws a frame of a rolling camera the way the zoompan path does it:
+// a window margin times larger is rendered unrotated, then turned around its center
+// and cropped to the frame
+func (r *FrameRenderer) renderRolled(t, rotation float64, win Window, dst *image.RGBA) {
+	bufW := evenCeil(float64(r.Width) * r.margin)
+	bufH := evenCeil(float64(r.Height) * r.margin)
+	if r.rolled == nil || r.rolled.Rect.Dx() != bufW || r.rolled.Rect.Dy() != bufH {
+		r.rolled = image.NewRGBA(image.Rect(0, 0, bufW, bufH))
+	}
+
+	cx, cy := win.X+win.W/2, win.Y+win.H/2
+	big := Window{W: win.W * float64(bufW) / float64(r.Width), H: win.H * float64(bufH) / float64(r.Height)}
+	big.X, big.Y = cx-big.W/2, cy-big.H/2
+	r.renderLayers(t, big, r.rolled)
+	rotateFrame(dst, r.rolled, rotation)
+}
+
+// rotateFrame turns src clockwise by rotation degrees around its center and writes
+// the central part into dst with bilinear filtering; uncovered pixels are black
+func rotateFrame(dst, src *image.RGBA, rotation float64) {
+	a := rotation * math.Pi / 180
+	cos, sin := math.Cos(a), math.Sin(a)
+	dw, dh := dst.Rect.Dx(), dst.Rect.Dy()
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	for j := 0; j < dh; j++ {
+		row := dst.Pix[j*dst.Stride : j*dst.Stride+dw*4]
+		dy := float64(j) + 0.5 - float64(dh)/2
+		for i := 0; i < dw; i++ {
+			// Inverse rotation of the output pixel into the source
+			dx := float64(i) + 0.5 - float64(dw)/2
+			sx := cos*dx + sin*dy + float64(sw)/2 - 0.5
+			sy := -sin*dx + cos*dy + float64(sh)/2 - 0.5
+
+			x0, y0 := math.Floor(sx), math.Floor(sy)
+			fx, fy := sx-x0, sy-y0
+			ix, iy := int(x0), int(y0)
+			var acc [3]float64
+			accumulate(&acc, src, validIndex(ix, sw), validIndex(iy, sh), (1-fx)*(1-fy))
+			accumulate(&acc, src, validIndex(ix+1, sw), validIndex(iy, sh), fx*(1-fy))
+			accumulate(&acc, src, validIndex(ix, sw), validIndex(iy+1, sh), (1-fx)*fy)
+			accumulate(&acc, src, validIndex(ix+1, sw), validIndex(iy+1, sh), fx*fy)
+
+			o := i * 4
+			row[o] = uint8(acc[0] + 0.5)
+			row[o+1] = uint8(acc[1] + 0.5)
+			row[o+2] = uint8(acc[2] + 0.5)
+			row[o+3] = 255
+		}
+	}
+}
+
+// renderWindow resamples the given viewport window into dst using bilinear filtering
+func (r *FrameRenderer) renderWindow(win Window, dst *image.RGBA) {
+	if r.scale <= 0 || len(r.levels) == 0 {
+		draw.Draw(dst, dst.Bounds(), image.Black, image.Point{}, draw.Src)
+		return
+	}
+	outW, outH := dst.Rect.Dx(), dst.Rect.Dy()
+
+	// Pick the pyramid level so that we never minify by more than 2x with bilinear sampling
+	srcPerOut := (win.W / float64(outW)) / r.scale
+	level := 0
+	for level+1 < len(r.levels) && srcPerOut >= 2.0 {
+		srcPerOut /= 2
+		level++
+	}
+	lvl := r.levels[level]
+	levelScale := math.Pow(2, float64(level))
+
+	// viewport -> level pixel coordinates
+	toLevel := 1.0 / (r.scale * levelScale)
+	xs := samplePositions(outW, win.X, win.W, r.offX, toLevel, lvl.Rect.Dx())
+	ys := samplePositions(outH, win.Y, win.H, r.offY, toLevel, lvl.Rect.Dy())
+
+	for j, sy := range ys {
+		row := dst.Pix[j*dst.Stride : j*dst.Stride+outW*4]
+		for i, sx := range xs {
+			var acc [3]float64
+			accumulate(&acc, lvl, sx.i0, sy.i0, (1-sx.f)*(1-sy.f))
+			accumulate(&acc, lvl, sx.i1, sy.i0, sx.f*(1-sy.f))
+			accumulate(&acc, lvl, sx.i0, sy.i1, (1-sx.f)*sy.f)
+			accumulate(&acc, lvl, sx.i1, sy.i1, sx.f*sy.f)
+
+			o := i * 4
+			row[o] = uint8(acc[0] + 0.5)
+			row[o+1] = uint8(acc[1] + 0.5)
+			row[o+2] = uint8(acc[2] + 0.5)
+			row[o+3] = 255
+		}
+	}
+}
+
+// maxMinification returns how many source pixels fall on one output pixel for the
+// widest shot of the slide (including the margin of a rolling camera), which is how
+// deep the mip pyramid has to go.
+func (r *FrameRenderer) maxMinification(keyframes []director.Keyframe) float64 {
+	if r.scale <= 0 {
+		return 1
+	}
+	minZoom := 1.0
+	for _, kf := range keyframes {
+		if kf.Zoom > 0 && kf.Zoom < minZoom {
+			minZoom = kf.Zoom
+		}
+	}
+	return r.margin / (r.scale * minZoom)
+}
+
+// tap describes two neighbouring source samples and the weight of the second one.
+// An index of -1 marks a sample outside of the page (rendered as black padding).
+type tap struct {
+	i0, i1 int
+	f      float64
+}
+
+func samplePositions(outSize int, winStart, winSize, offset, toLevel float64, limit int) []tap {
+	taps := make([]tap, outSize)
+	step := winSize / float64(outSize)
+	for i := range taps {
+		v := winStart + (float64(i)+0.5)*step
+		s := (v-offset)*toLevel - 0.5
+		fl := math.Floor(s)
+		i0 := int(fl)
+		taps[i] = tap{
+			i0: validIndex(i0, limit),
+			i1: validIndex(i0+1, limit),
+			f:  s - fl,
+		}
+	}
+	return taps
+}
+
+func validIndex(i, limit int) int {
+	if i < 0 || i >= limit {
+		return -1
+	}
+	return i
+}
+
+func accumulate(acc *[3]float64, img *image.RGBA, x, y int, weight float64) {
+	if x < 0 || y < 0 || weight == 0 {
+		return // Outside the page: black padding contributes nothing
+	}
+	o := y*img.Stride + x*4
+	acc[0] += float64(img.Pix[o]) * weight
+	acc[1] += float64(img.Pix[o+1]) * weight
+	acc[2] += float64(img.Pix[o+2]) * weight
+}
+
+// buildPyramid halves the source with a 2x2 box filter until a level is detailed
+// enough for the widest shot without minifying by more than 2x.
+func buildPyramid(base *image.RGBA, maxRatio float64) []*image.RGBA {
+	levels := []*image.RGBA{base}
+	cur := base
+	for cur.Rect.Dx() > 1 && cur.Rect.Dy() > 1 && maxRatio >= 2.0 {
+		cur = halve(cur)
+		levels = append(levels, cur)
+		maxRatio /= 2
+	}
+	return levels
+}
+
+func halve(src *image.RGBA) *image.RGBA {
+	w, h := src.Rect.Dx()/2, src.Rect.Dy()/2
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		r0 := src.Pix[(2*y)*src.Stride:]
+		r1 := src.Pix[(2*y+1)*src.Stride:]
+		out := dst.Pix[y*dst.Stride:]
+		for x := 0; x < w; x++ {
+			i := x * 8
+			for c := 0; c < 4; c++ {
+				sum := int(r0[i+c]) + int(r0[i+4+c]) + int(r1[i+c]) + int(r1[i+4+c])
+				out[x*4+c] = uint8((sum + 2) / 4)
+			}
+		}
+	}
+	return dst
+}
+
+// toRGBA returns the image as *image.RGBA with its origin at (0,0)
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
+
+// clampWindow keeps a window of the given size inside [0, limit]
+func clampWindow(start, size, limit float64) float64 {
+	if size >= limit {
+		return (limit - size) / 2
+	}
+	if start < 0 {
+		return 0
+	}
+	if start+size > limit {
+		return limit - size
+	}
+	return start
+}
@@ -0,0 +1,120 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+func TestFrameRendererWindowClamp(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 160, 90))
+	fr := NewFrameRenderer(src, nil, 160, 90, 30)
+
+	// Camera pushed past the bottom-right corner must stay inside the viewport
+	win := fr.WindowFor(CameraState{X: 155, Y: 88, Zoom: 2.0})
+	if win.W != 80 || win.H != 45 {
+		t.Fatalf("Expected 80x45 window, got %.1fx%.1f", win.W, win.H)
+	}
+	if win.X != 80 || win.Y != 45 {
+		t.Errorf("Expected window clamped to (80,45), got (%.1f,%.1f)", win.X, win.Y)
+	}
+
+	// Sub-pixel positions must be kept as is
+	win = fr.WindowFor(CameraState{X: 60.25, Y: 40.5, Zoom: 2.0})
+	if win.X != 20.25 || win.Y != 18 {
+		t.Errorf("Expected sub-pixel window at (20.25,18), got (%.2f,%.2f)", win.X, win.Y)
+	}
+}
+
+func TestFrameRendererUniformPage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 320, 180))
+	fill(src, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+
+	keyframes := []director.Keyframe{
+		{Time: 0.0, Rect: director.Rectangle{X: 0, Y: 0, W: 160, H: 90}, Zoom: 1.0},
+		{Time: 1.0, Rect: director.Rectangle{X: 40, Y: 20, W: 80, H: 45}, Zoom: 2.0},
+	}
+	fr := NewFrameRenderer(src, keyframes, 160, 90, 10)
+
+	dst := image.NewRGBA(image.Rect(0, 0, 160, 90))
+	for _, frame := range []int{0, 5, 10} {
+		if err := fr.RenderFrame(frame, dst); err != nil {
+			t.Fatalf("RenderFrame(%d) failed: %v", frame, err)
+		}
+		for _, p := range []image.Point{{0, 0}, {80, 45}, {159, 89}} {
+			c := dst.RGBAAt(p.X, p.Y)
+			if c.R != 200 || c.G != 100 || c.B != 50 || c.A != 255 {
+				t.Errorf("Frame %d at %v: expected page color, got %v", frame, p, c)
+			}
+		}
+	}
+}
+
+func TestFrameRendererRotation(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 320, 180))
+	fill(src, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+
+	page := director.Rectangle{W: 160, H: 90}
+	keyframes := director.FitRotatedKeyframes([]director.Keyframe{
+		{Time: 0.0, Rect: page, Zoom: 1.0, Rotation: -6},
+		{Time: 1.0, Rect: director.Rectangle{X: 0, Y: 0, W: 40, H: 22.5}, Zoom: 4.0, Rotation: 8},
+	}, 160, 90, page)
+	fr := NewFrameRenderer(src, keyframes, 160, 90, 10)
+
+	// The rolled camera stays on the page: no black corners
+	dst := image.NewRGBA(image.Rect(0, 0, 160, 90))
+	for _, frame := range []int{0, 5, 10} {
+		if err := fr.RenderFrame(frame, dst); err != nil {
+			t.Fatalf("RenderFrame(%d) failed: %v", frame, err)
+		}
+		for _, p := range []image.Point{{0, 0}, {159, 0}, {0, 89}, {159, 89}, {80, 45}} {
+			if c := dst.RGBAAt(p.X, p.Y); c.R < 195 || c.G < 95 {
+				t.Errorf("Frame %d at %v: expected page color, got %v", frame, p, c)
+			}
+		}
+	}
+
+	// A diagonal edge turns with the camera
+	edge := image.NewRGBA(image.Rect(0, 0, 160, 90))
+	fill(edge, color.RGBA{A: 255})
+	for y := 0; y < 90; y++ {
+		for x := 0; x < 80; x++ {
+			edge.Set(x, y, color.White)
+		}
+	}
+	fr = NewFrameRenderer(edge, []director.Keyframe{{Rect: director.Rectangle{X: 40, Y: 22.5, W: 80, H: 45}, Zoom: 2, Rotation: 30}}, 160, 90, 10)
+	if err := fr.RenderFrame(0, dst); err != nil {
+		t.Fatal(err)
+	}
+	// Turned clockwise, the vertical edge leans right at the top of the frame
+	if top, bottom := dst.RGBAAt(95, 5), dst.RGBAAt(65, 84); top.R < 200 || bottom.R > 50 {
+		t.Errorf("expected the edge turned clockwise, got top %v bottom %v", top, bottom)
+	}
+}
+
+func TestFrameRendererLetterbox(t *testing.T) {
+	// Square page in a 16:9 viewport leaves black bars on both sides
+	src := image.NewRGBA(image.Rect(0, 0, 90, 90))
+	fill(src, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	fr := NewFrameRenderer(src, nil, 160, 90, 30)
+
+	dst := image.NewRGBA(image.Rect(0, 0, 160, 90))
+	if err := fr.RenderFrame(0, dst); err != nil {
+		t.Fatalf("RenderFrame failed: %v", err)
+	}
+
+	if c := dst.RGBAAt(5, 45); c.R != 0 {
+		t.Errorf("Expected black padding at the left edge, got %v", c)
+	}
+	if c := dst.RGBAAt(80, 45); c.R != 255 {
+		t.Errorf("Expected page content in the center, got %v", c)
+	}
+}
+
+func fill(img *image.RGBA, c color.RGBA) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+}
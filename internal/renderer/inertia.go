@@ -0,0 +1,92 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+const (
+	defaultInertiaMass    = 1.0
+	defaultInertiaDamping = 0.6
+
+	// arrivalSpeedShare is the part of the average flight speed the camera still
+	// has when it reaches the target, so longer flights overshoot further
+	arrivalSpeedShare = 0.5
+
+	// inertiaSampleStep is the resampling interval (seconds) for paths with inertia
+	inertiaSampleStep = 1.0 / 15
+
+	// minSettleHold is the shortest hold (seconds) with room for a visible settle
+	minSettleHold = 0.25
+)
+
+// applyInertia adds the physics layer to a camera state on the keyframe path.
+// Every flight that ends in a hold arrives with a residual velocity instead of
+// stopping dead, then overshoots and settles like a damped mass on a spring.
+// Only the position is affected; zoom follows the path exactly.
+func applyInertia(state *CameraState, keyframes []director.Keyframe, currentTime float64, opts PathOptions) {
+	omegaN := 2 * math.Pi * 1.2 / math.Sqrt(opts.Mass)
+	zeta := math.Max(0.05, math.Min(0.95, opts.Damping))
+	omegaD := omegaN * math.Sqrt(1-zeta*zeta)
+	settleTime := 6 / (zeta * omegaN) // e^-6: the wobble is gone
+
+	for i := 1; i < len(keyframes)-1; i++ {
+		if !isArrival(keyframes, i) {
+			continue
+		}
+
+		start, end := keyframes[i-1].Time, keyframes[i].Time
+		dt := end - start
+		hold := keyframes[i+1].Time - end
+		if dt <= 0 || hold < minSettleHold {
+			// Too short a hold: the camera stops dead rather than wobble into the next flight
+			continue
+		}
+		vx, vy := arrivalVelocity(keyframes[i-1], keyframes[i], dt)
+
+		switch {
+		case currentTime > start && currentTime < end:
+			// Ease-out: the flight keeps part of its speed up to the target. A straight
+			// flight may already arrive moving (linear easing), only the rest is added.
+			bx, by := 0.0, 0.0
+			if !opts.IsSpline() {
+				bx, by = easedArrivalVelocity(keyframes[i-1], keyframes[i], dt)
+			}
+			s := (currentTime - start) / dt
+			h := (s*s*s - s*s) * dt
+			state.X += (vx - bx) * h
+			state.Y += (vy - by) * h
+		case currentTime >= end && currentTime-end < math.Min(settleTime, hold):
+			// Overshoot and settle around the target
+			tau := currentTime - end
+			a := math.Exp(-zeta*omegaN*tau) * math.Sin(omegaD*tau) / omegaD
+			if hold < settleTime {
+				// The settle must be over when the hold ends: taper it to rest smoothly
+				taper := 1 - tau/hold
+				a *= taper * taper
+			}
+			state.X += vx * a
+			state.Y += vy * a
+		}
+	}
+}
+
+// isArrival reports whether keyframe i ends a flight and the camera then holds there
+func isArrival(keyframes []director.Keyframe, i int) bool {
+	return !isHold(keyframes[i-1], keyframes[i]) && isHold(keyframes[i], keyframes[i+1])
+}
+
+// easedArrivalVelocity returns the velocity (pixels per second) a straight flight has at
+// its end by the easing of its keyframe: zero for eased curves, the average speed for linear
+func easedArrivalVelocity(from, to director.Keyframe, dt float64) (float64, float64) {
+	const ds = 1e-4
+	slope := (1 - from.EasingCurve().Apply(1-ds)) / ds / dt
+	return (getCenter(to, true) - getCenter(from, true)) * slope, (getCenter(to, false) - getCenter(from, false)) * slope
+}
+
+// arrivalVelocity returns the camera velocity (pixels per second) at the end of a flight
+func arrivalVelocity(from, to director.Keyframe, dt float64) (float64, float64) {
+	k := arrivalSpeedShare / dt
+	return (getCenter(to, true) - getCenter(from, true)) * k, (getCenter(to, false) - getCenter(from, false)) * k
+}
@@ -0,0 +1,127 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+func flightKeyframes() []director.Keyframe {
+	return []director.Keyframe{
+		{Time: 0.0, Rect: director.Rectangle{X: 0, Y: 0, W: 1920, H: 1080}, Zoom: 1.0, Easing: director.EasingInOut},
+		{Time: 2.0, Rect: director.Rectangle{X: 1200, Y: 440, W: 400, H: 200}, Zoom: 2.0, Easing: director.EasingInOut},
+		{Time: 6.0, Rect: director.Rectangle{X: 1200, Y: 440, W: 400, H: 200}, Zoom: 2.0, Easing: director.EasingInOut},
+		{Time: 8.0, Rect: director.Rectangle{X: 0, Y: 0, W: 1920, H: 1080}, Zoom: 1.0, Easing: director.EasingInOut},
+	}
+}
+
+func TestInertiaOvershootAndSettle(t *testing.T) {
+	keyframes := flightKeyframes()
+	opts := PathOptions{Mode: director.PathLinear, Mass: 1.0, Damping: 0.5}
+
+	// Camera reaches the target exactly at the keyframe
+	at := InterpolatePath(keyframes, 2.0, opts)
+	if abs(at.X-1400) > 1e-6 || abs(at.Y-540) > 1e-6 {
+		t.Errorf("Expected camera at (1400, 540) on arrival, got (%.2f, %.2f)", at.X, at.Y)
+	}
+
+	// Shortly after arrival it overshoots in the direction of travel (right)
+	overshoot := 0.0
+	for tm := 2.0; tm < 3.0; tm += 0.01 {
+		if dx := InterpolatePath(keyframes, tm, opts).X - 1400; dx > overshoot {
+			overshoot = dx
+		}
+	}
+	if overshoot < 1 {
+		t.Errorf("Expected visible overshoot, got %.2f px", overshoot)
+	}
+
+	// Zoom is not affected by the physics layer
+	if z := InterpolatePath(keyframes, 2.3, opts).Zoom; z != 2.0 {
+		t.Errorf("Expected zoom 2.0 during settle, got %.3f", z)
+	}
+
+	// Before the next flight the camera has settled
+	settled := InterpolatePath(keyframes, 5.9, opts)
+	if abs(settled.X-1400) > 0.5 || abs(settled.Y-540) > 0.5 {
+		t.Errorf("Expected camera settled at (1400, 540), got (%.2f, %.2f)", settled.X, settled.Y)
+	}
+
+	// Without inertia the camera stops dead
+	plain := InterpolatePath(keyframes, 2.3, PathOptions{Mode: director.PathLinear})
+	if plain.X != 1400 {
+		t.Errorf("Expected no overshoot without inertia, got x=%.2f", plain.X)
+	}
+}
+
+func TestInertiaHeavierCameraOvershootsFurther(t *testing.T) {
+	keyframes := flightKeyframes()
+
+	peak := func(mass float64) float64 {
+		opts := PathOptions{Mode: director.PathSpline, Mass: mass, Damping: 0.5}
+		best := 0.0
+		for tm := 2.0; tm < 4.0; tm += 0.01 {
+			if dx := InterpolatePath(keyframes, tm, opts).X - 1400; dx > best {
+				best = dx
+			}
+		}
+		return best
+	}
+
+	if light, heavy := peak(0.5), peak(2.0); heavy <= light {
+		t.Errorf("Expected heavier camera to overshoot further, got %.2f vs %.2f px", heavy, light)
+	}
+}
+
+func TestInertiaContinuousVelocity(t *testing.T) {
+	keyframes := flightKeyframes()
+	opts := PathOptions{Mode: director.PathLinear, Mass: 1.0, Damping: 0.6}
+	const eps = 0.001
+
+	// Eased and linear flights both hand their speed over to the settle
+	for _, easing := range []string{director.EasingInOut, director.EasingLinear} {
+		keyframes[0].Easing = easing
+		before := (InterpolatePath(keyframes, 2.0, opts).X - InterpolatePath(keyframes, 2.0-eps, opts).X) / eps
+		after := (InterpolatePath(keyframes, 2.0+eps, opts).X - InterpolatePath(keyframes, 2.0, opts).X) / eps
+		if before <= 0 || abs(after-before) > 0.05*before {
+			t.Errorf("%s: expected continuous arrival velocity, got %.2f px/s before and %.2f px/s after", easing, before, after)
+		}
+	}
+}
+
+func TestInertiaSettleEndsWithHold(t *testing.T) {
+	opts := PathOptions{Mode: director.PathLinear, Mass: 2.0, Damping: 0.2} // Slow settle
+	keyframes := flightKeyframes()
+	keyframes[2].Time = 2.6 // Short hold before the next flight
+	keyframes[3].Time = 4.6
+
+	// The camera is back on the target when the next flight starts
+	at := InterpolatePath(keyframes, 2.6, opts)
+	if abs(at.X-1400) > 1e-6 || abs(at.Y-540) > 1e-6 {
+		t.Errorf("Expected camera settled at (1400, 540) when the hold ends, got (%.2f, %.2f)", at.X, at.Y)
+	}
+	if plain := InterpolateKeyframes(keyframes, 2.8); abs(InterpolatePath(keyframes, 2.8, opts).X-plain.X) > 1e-6 {
+		t.Error("Expected the settle not to reach into the next flight")
+	}
+
+	// A hold too short for a settle gets none
+	keyframes[2].Time = 2.1
+	if x := InterpolatePath(keyframes, 2.05, opts).X; abs(x-1400) > 1e-6 {
+		t.Errorf("Expected no settle on a very short hold, got x=%.2f", x)
+	}
+}
+
+func TestPathOptionsForInertia(t *testing.T) {
+	opts := PathOptionsFor(&director.CameraSettings{Inertia: &director.InertiaSettings{}})
+	if !opts.HasInertia() || opts.Mass != defaultInertiaMass || opts.Damping != defaultInertiaDamping {
+		t.Errorf("Expected default inertia settings, got %+v", opts)
+	}
+	if opts.IsSpline() {
+		t.Error("Inertia alone should keep the linear path")
+	}
+
+	dense := ResamplePath(flightKeyframes(), opts, 1920, 1080)
+	if len(dense) <= len(flightKeyframes()) {
+		t.Error("Paths with inertia should be resampled for zoompan")
+	}
+}
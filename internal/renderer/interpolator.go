@@ -9,6 +9,8 @@ type CameraState struct {
 	X    float64 // Pan X position (center point in pixels)
 	Y    float64 // Pan Y position (center point in pixels)
 	Zoom float64 // Zoom level (1.0 = no zoom)
+
+	Rotation float64 // Camera roll in degrees (clockwise turn of the picture)
 }
 
 // InterpolateKeyframes calculates camera state at a given time by interpolating between keyframes
@@ -21,9 +23,10 @@ func InterpolateKeyframes(keyframes []director.Keyframe, currentTime float64) Ca
 	if currentTime <= keyframes[0].Time {
 		kf := keyframes[0]
 		return CameraState{
-			X:    float64(kf.Rect.X + kf.Rect.W/2),
-			Y:    float64(kf.Rect.Y + kf.Rect.H/2),
-			Zoom: kf.Zoom,
+			X:        kf.Rect.X + kf.Rect.W/2,
+			Y:        kf.Rect.Y + kf.Rect.H/2,
+			Zoom:     kf.Zoom,
+			Rotation: kf.Rotation,
 		}
 	}
 
@@ -31,9 +34,10 @@ func InterpolateKeyframes(keyframes []director.Keyframe, currentTime float64) Ca
 	if currentTime >= keyframes[len(keyframes)-1].Time {
 		kf := keyframes[len(keyframes)-1]
 		return CameraState{
-			X:    float64(kf.Rect.X + kf.Rect.W/2),
-			Y:    float64(kf.Rect.Y + kf.Rect.H/2),
-			Zoom: kf.Zoom,
+			X:        kf.Rect.X + kf.Rect.W/2,
+			Y:        kf.Rect.Y + kf.Rect.H/2,
+			Zoom:     kf.Zoom,
+			Rotation: kf.Rotation,
 		}
 	}
 
@@ -54,19 +58,20 @@ func InterpolateKeyframes(keyframes []director.Keyframe, currentTime float64) Ca
 	}
 	t := (currentTime - prevKf.Time) / timeDelta
 
-	// Apply easing (smooth in-out)
-	t = easeInOutCubic(t)
+	// Apply the easing of the segment (linear unless the keyframe names another)
+	t = prevKf.EasingCurve().Apply(t)
 
 	// Interpolate positions
-	prevX := float64(prevKf.Rect.X + prevKf.Rect.W/2)
-	prevY := float64(prevKf.Rect.Y + prevKf.Rect.H/2)
-	nextX := float64(nextKf.Rect.X + nextKf.Rect.W/2)
-	nextY := float64(nextKf.Rect.Y + nextKf.Rect.H/2)
+	prevX := prevKf.Rect.X + prevKf.Rect.W/2
+	prevY := prevKf.Rect.Y + prevKf.Rect.H/2
+	nextX := nextKf.Rect.X + nextKf.Rect.W/2
+	nextY := nextKf.Rect.Y + nextKf.Rect.H/2
 
 	return CameraState{
-		X:    lerp(prevX, nextX, t),
-		Y:    lerp(prevY, nextY, t),
-		Zoom: lerp(prevKf.Zoom, nextKf.Zoom, t),
+		X:        lerp(prevX, nextX, t),
+		Y:        lerp(prevY, nextY, t),
+		Zoom:     lerp(prevKf.Zoom, nextKf.Zoom, t),
+		Rotation: lerp(prevKf.Rotation, nextKf.Rotation, t),
 	}
 }
 
@@ -74,20 +79,3 @@ func InterpolateKeyframes(keyframes []director.Keyframe, currentTime float64) Ca
 func lerp(a, b, t float64) float64 {
 	return a + (b-a)*t
 }
-
-// easeInOutCubic applies smooth easing function
-func easeInOutCubic(t float64) float64 {
-	if t < 0.5 {
-		return 4 * t * t * t
-	}
-	return 1 - pow(-2*t+2, 3)/2
-}
-
-// pow calculates x^n
-func pow(x float64, n int) float64 {
-	result := 1.0
-	for i := 0; i < n; i++ {
-		result *= x
-	}
-	return result
-}
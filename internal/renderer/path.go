@@ -0,0 +1,215 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+// pathSampleStep is the interval (seconds) used to resample curved paths into
+// keyframes for the piecewise-linear zoompan expressions
+const pathSampleStep = 0.2
+
+// PathOptions selects how the camera travels between keyframes
+type PathOptions struct {
+	Mode    string  // director.PathLinear (default) or director.PathSpline
+	Tension float64 // Spline tension: 0 = Catmull-Rom, 1 = no curvature
+	Mass    float64 // Camera mass for inertia; 0 disables the physics layer
+	Damping float64 // Damping ratio of the settle after each flight
+}
+
+// PathOptionsFor converts scenario camera settings into path options.
+// Missing or unknown settings fall back to the linear path without inertia.
+func PathOptionsFor(cam *director.CameraSettings) PathOptions {
+	opts := PathOptions{Mode: director.PathLinear}
+	if cam == nil {
+		return opts
+	}
+	if cam.Path == director.PathSpline {
+		opts.Mode = director.PathSpline
+		opts.Tension = math.Max(0, math.Min(1, cam.Tension))
+	}
+	if cam.Inertia != nil {
+		opts.Mass = defaultInertiaMass
+		if cam.Inertia.Mass > 0 {
+			opts.Mass = cam.Inertia.Mass
+		}
+		opts.Damping = defaultInertiaDamping
+		if cam.Inertia.Damping > 0 {
+			opts.Damping = cam.Inertia.Damping
+		}
+	}
+	return opts
+}
+
+// IsSpline reports whether the options describe a curved path
+func (o PathOptions) IsSpline() bool {
+	return o.Mode == director.PathSpline
+}
+
+// HasInertia reports whether the physics layer is enabled
+func (o PathOptions) HasInertia() bool {
+	return o.Mass > 0
+}
+
+// InterpolatePath calculates camera state at a given time using the selected path type,
+// with the optional inertia layer on top. The plain linear path is the same as InterpolateKeyframes.
+func InterpolatePath(keyframes []director.Keyframe, currentTime float64, opts PathOptions) CameraState {
+	state := interpolateBase(keyframes, currentTime, opts)
+	if opts.HasInertia() {
+		applyInertia(&state, keyframes, currentTime, opts)
+	}
+	return state
+}
+
+// interpolateBase calculates the camera state on the keyframe path itself
+func interpolateBase(keyframes []director.Keyframe, currentTime float64, opts PathOptions) CameraState {
+	if !opts.IsSpline() || len(keyframes) < 3 {
+		return InterpolateKeyframes(keyframes, currentTime)
+	}
+
+	last := len(keyframes) - 1
+	if currentTime <= keyframes[0].Time {
+		return keyframeState(keyframes[0])
+	}
+	if currentTime >= keyframes[last].Time {
+		return keyframeState(keyframes[last])
+	}
+
+	i := 0
+	for i < last-1 && currentTime >= keyframes[i+1].Time {
+		i++
+	}
+
+	dt := keyframes[i+1].Time - keyframes[i].Time
+	if dt <= 0 {
+		return keyframeState(keyframes[i+1])
+	}
+	s := (currentTime - keyframes[i].Time) / dt
+	if keyframes[i].Easing != "" {
+		// The spline already rests on holds and at the ends; a named easing reshapes the timing on top
+		s = keyframes[i].EasingCurve().Apply(s)
+	}
+
+	from := keyframeState(keyframes[i])
+	to := keyframeState(keyframes[i+1])
+	m0 := splineTangent(keyframes, i, opts.Tension)
+	m1 := splineTangent(keyframes, i+1, opts.Tension)
+
+	return CameraState{
+		X:        hermite(from.X, to.X, m0.X*dt, m1.X*dt, s),
+		Y:        hermite(from.Y, to.Y, m0.Y*dt, m1.Y*dt, s),
+		Zoom:     hermite(from.Zoom, to.Zoom, m0.Zoom*dt, m1.Zoom*dt, s),
+		Rotation: hermite(from.Rotation, to.Rotation, m0.Rotation*dt, m1.Rotation*dt, s),
+	}
+}
+
+// ResamplePath converts a curved or physics-driven camera path into dense keyframes,
+// so that the zoompan expressions follow the same trajectory. The easing is already part
+// of the samples, so every resampled segment is linear; plain paths are returned unchanged.
+func ResamplePath(keyframes []director.Keyframe, opts PathOptions, width, height int) []director.Keyframe {
+	if len(keyframes) < 3 || (!opts.IsSpline() && !opts.HasInertia()) {
+		return keyframes
+	}
+
+	// The settle wobbles faster than the path bends, so it needs a denser grid
+	step := pathSampleStep
+	if opts.HasInertia() {
+		step = inertiaSampleStep
+	}
+
+	out := make([]director.Keyframe, 0, len(keyframes)*4)
+	for i := 0; i < len(keyframes)-1; i++ {
+		original := keyframes[i]
+		original.Easing = director.EasingLinear
+		out = append(out, original)
+
+		start, end := keyframes[i].Time, keyframes[i+1].Time
+		if end <= start || (isStill(keyframes[i], keyframes[i+1]) && !opts.HasInertia()) {
+			continue
+		}
+
+		steps := int(math.Ceil((end - start) / step))
+		for k := 1; k < steps; k++ {
+			t := start + (end-start)*float64(k)/float64(steps)
+			out = append(out, stateKeyframe(InterpolatePath(keyframes, t, opts), t, width, height))
+		}
+	}
+	return append(out, keyframes[len(keyframes)-1])
+}
+
+// splineTangent returns the velocity (units per second) of the path at keyframe i.
+// The camera rests at the ends of the path and on holds; zoom and rotation also rest
+// at their local extremes so that the spline never overshoots the requested values.
+func splineTangent(keyframes []director.Keyframe, i int, tension float64) CameraState {
+	if i <= 0 || i >= len(keyframes)-1 {
+		return CameraState{}
+	}
+
+	span := keyframes[i+1].Time - keyframes[i-1].Time
+	if span <= 0 {
+		return CameraState{}
+	}
+
+	prev := keyframeState(keyframes[i-1])
+	cur := keyframeState(keyframes[i])
+	next := keyframeState(keyframes[i+1])
+	k := (1 - tension) / span
+
+	var m CameraState
+	if !isHold(keyframes[i-1], keyframes[i]) && !isHold(keyframes[i], keyframes[i+1]) {
+		m.X = k * (next.X - prev.X)
+		m.Y = k * (next.Y - prev.Y)
+	}
+	if (cur.Zoom-prev.Zoom)*(next.Zoom-cur.Zoom) > 0 {
+		m.Zoom = k * (next.Zoom - prev.Zoom)
+	}
+	if (cur.Rotation-prev.Rotation)*(next.Rotation-cur.Rotation) > 0 {
+		m.Rotation = k * (next.Rotation - prev.Rotation)
+	}
+	return m
+}
+
+// hermite evaluates the cubic Hermite curve between p0 and p1 with tangents m0 and m1
+func hermite(p0, p1, m0, m1, s float64) float64 {
+	s2 := s * s
+	s3 := s2 * s
+	return (2*s3-3*s2+1)*p0 + (s3-2*s2+s)*m0 + (-2*s3+3*s2)*p1 + (s3-s2)*m1
+}
+
+// isHold reports whether the camera stays in place between two keyframes
+func isHold(a, b director.Keyframe) bool {
+	return getCenter(a, true) == getCenter(b, true) && getCenter(a, false) == getCenter(b, false)
+}
+
+// isStill reports whether the camera neither moves, zooms nor rolls between two keyframes
+func isStill(a, b director.Keyframe) bool {
+	return isHold(a, b) && a.Zoom == b.Zoom && a.Rotation == b.Rotation
+}
+
+func keyframeState(kf director.Keyframe) CameraState {
+	return CameraState{X: getCenter(kf, true), Y: getCenter(kf, false), Zoom: kf.Zoom, Rotation: kf.Rotation}
+}
+
+// stateKeyframe builds a keyframe whose rectangle is the visible window of the camera state
+func stateKeyframe(state CameraState, t float64, width, height int) director.Keyframe {
+	zoom := state.Zoom
+	if zoom <= 0 {
+		zoom = 1.0
+	}
+	w := float64(width) / zoom
+	h := float64(height) / zoom
+	return director.Keyframe{
+		Time:  t,
+		Focus: "path",
+		Rect: director.Rectangle{
+			X: state.X - w/2,
+			Y: state.Y - h/2,
+			W: w,
+			H: h,
+		},
+		Zoom:     state.Zoom,
+		Rotation: state.Rotation,
+		Easing:   director.EasingLinear,
+	}
+}
@@ -0,0 +1,119 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/director"
+)
+
+func tourKeyframes() []director.Keyframe {
+	return []director.Keyframe{
+		{Time: 0.0, Rect: director.Rectangle{X: 0, Y: 0, W: 1920, H: 1080}, Zoom: 1.0},
+		{Time: 1.0, Rect: director.Rectangle{X: 100, Y: 100, W: 400, H: 200}, Zoom: 2.0},
+		{Time: 2.0, Rect: director.Rectangle{X: 100, Y: 100, W: 400, H: 200}, Zoom: 2.0},
+		{Time: 3.0, Rect: director.Rectangle{X: 1200, Y: 100, W: 400, H: 200}, Zoom: 2.0},
+		{Time: 4.0, Rect: director.Rectangle{X: 1200, Y: 700, W: 400, H: 200}, Zoom: 2.0},
+		{Time: 5.0, Rect: director.Rectangle{X: 0, Y: 0, W: 1920, H: 1080}, Zoom: 1.0},
+	}
+}
+
+func TestInterpolatePathSplinePassesThroughKeyframes(t *testing.T) {
+	keyframes := tourKeyframes()
+	opts := PathOptions{Mode: director.PathSpline}
+
+	for _, kf := range keyframes {
+		state := InterpolatePath(keyframes, kf.Time, opts)
+		if abs(state.X-getCenter(kf, true)) > 1e-9 || abs(state.Y-getCenter(kf, false)) > 1e-9 || abs(state.Zoom-kf.Zoom) > 1e-9 {
+			t.Errorf("At %.1fs: expected (%.1f, %.1f, %.2f), got (%.1f, %.1f, %.2f)",
+				kf.Time, getCenter(kf, true), getCenter(kf, false), kf.Zoom, state.X, state.Y, state.Zoom)
+		}
+	}
+}
+
+func TestInterpolatePathSplineKeepsHolds(t *testing.T) {
+	keyframes := tourKeyframes()
+	opts := PathOptions{Mode: director.PathSpline}
+
+	for _, tm := range []float64{1.2, 1.5, 1.8} {
+		state := InterpolatePath(keyframes, tm, opts)
+		if state.X != 300 || state.Y != 200 || state.Zoom != 2.0 {
+			t.Errorf("At %.1fs: camera should hold at (300, 200) x2, got (%.2f, %.2f) x%.2f", tm, state.X, state.Y, state.Zoom)
+		}
+	}
+}
+
+func TestInterpolatePathSplineSmoothCorner(t *testing.T) {
+	keyframes := tourKeyframes()
+	const eps = 0.01
+
+	// The camera turns at 3.0s (moving right, then down)
+	eased := tourKeyframes()
+	for i := range eased {
+		eased[i].Easing = director.EasingInOut
+	}
+	linearIn := InterpolatePath(eased, 3.0-eps, PathOptions{Mode: director.PathLinear})
+	linearOut := InterpolatePath(eased, 3.0+eps, PathOptions{Mode: director.PathLinear})
+	splineIn := InterpolatePath(keyframes, 3.0-eps, PathOptions{Mode: director.PathSpline})
+	splineOut := InterpolatePath(keyframes, 3.0+eps, PathOptions{Mode: director.PathSpline})
+
+	// Linear path with easing stops at the corner
+	if abs(linearOut.Y-linearIn.Y) > 0.1 {
+		t.Errorf("Linear path should stop at the corner, moved %.3f px", linearOut.Y-linearIn.Y)
+	}
+
+	// Spline keeps moving through the corner in both directions
+	if splineOut.X-splineIn.X <= 1 || splineOut.Y-splineIn.Y <= 1 {
+		t.Errorf("Spline should pass the corner without stopping, moved (%.3f, %.3f) px",
+			splineOut.X-splineIn.X, splineOut.Y-splineIn.Y)
+	}
+}
+
+func TestInterpolatePathTension(t *testing.T) {
+	keyframes := tourKeyframes()
+
+	// With full tension the tangents vanish, so the camera stops at every keyframe
+	tight := PathOptions{Mode: director.PathSpline, Tension: 1}
+	before := InterpolatePath(keyframes, 3.0-0.01, tight)
+	after := InterpolatePath(keyframes, 3.0+0.01, tight)
+	if abs(after.X-before.X) > 1 || abs(after.Y-before.Y) > 1 {
+		t.Errorf("Tension 1 should stop at the keyframe, moved (%.3f, %.3f) px", after.X-before.X, after.Y-before.Y)
+	}
+}
+
+func TestResamplePath(t *testing.T) {
+	keyframes := tourKeyframes()
+
+	if got := ResamplePath(keyframes, PathOptions{Mode: director.PathLinear}, 1920, 1080); len(got) != len(keyframes) {
+		t.Errorf("Linear path should not be resampled, got %d keyframes", len(got))
+	}
+
+	opts := PathOptions{Mode: director.PathSpline}
+	dense := ResamplePath(keyframes, opts, 1920, 1080)
+	if len(dense) <= len(keyframes) {
+		t.Fatalf("Expected resampled path, got %d keyframes", len(dense))
+	}
+
+	for i := 1; i < len(dense); i++ {
+		if dense[i].Time < dense[i-1].Time {
+			t.Fatalf("Resampled keyframes are not sorted at %d", i)
+		}
+	}
+
+	for _, kf := range dense {
+		want := InterpolatePath(keyframes, kf.Time, opts)
+		if abs(getCenter(kf, true)-want.X) > 1 || abs(getCenter(kf, false)-want.Y) > 1 {
+			t.Errorf("At %.2fs: resampled center (%.1f, %.1f) is off the path (%.1f, %.1f)",
+				kf.Time, getCenter(kf, true), getCenter(kf, false), want.X, want.Y)
+		}
+	}
+}
+
+func TestPathOptionsFor(t *testing.T) {
+	if opts := PathOptionsFor(nil); opts.IsSpline() {
+		t.Error("Missing camera settings should use the linear path")
+	}
+	opts := PathOptionsFor(&director.CameraSettings{Path: director.PathSpline, Tension: 3})
+	if !opts.IsSpline() || opts.Tension != 1 {
+		t.Errorf("Expected spline with tension clamped to 1, got %+v", opts)
+	}
+}
@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/ivlev/pdf2video/internal/director"
@@ -64,9 +65,86 @@ func TestGenerateZoomPanFilter(t *testing.T) {
 		t.Error("Filter should contain y expression")
 	}
 
+	// The pan keeps viewport pixels, only a canvas converts it to the (upscaled) input
+	if !contains(filter, "-(1920/zoom)/2") || contains(filter, "*iw/1920") {
+		t.Error("Pan expressions should stay in viewport pixels")
+	}
+	canvas := GenerateCanvasZoomPanFilter(keyframes, 3.0, 30, 1920, 1080)
+	if !contains(canvas, "*iw/1920-(iw/zoom)/2") || !contains(canvas, "*ih/1080-(ih/zoom)/2") {
+		t.Error("Canvas pan expressions should be relative to the input size")
+	}
+
 	t.Logf("Generated filter: %s", filter)
 }
 
+func TestKeyframeEasing(t *testing.T) {
+	keyframes := []director.Keyframe{
+		{Time: 0.0, Rect: director.Rectangle{X: 0, Y: 0, W: 1920, H: 1080}, Zoom: 1.0, Easing: director.EasingIn},
+		{Time: 2.0, Rect: director.Rectangle{X: 0, Y: 0, W: 960, H: 540}, Zoom: 2.0, Easing: director.EasingLinear},
+		{Time: 4.0, Rect: director.Rectangle{X: 960, Y: 540, W: 960, H: 540}, Zoom: 2.0},
+	}
+
+	// The interpolator follows the easing of the keyframe that starts the segment
+	if got := InterpolateKeyframes(keyframes, 1.0).Zoom; abs(got-1.125) > 1e-9 {
+		t.Errorf("ease-in midpoint: expected zoom 1.125, got %.4f", got)
+	}
+	if got := InterpolateKeyframes(keyframes, 3.0).X; abs(got-960) > 1e-9 {
+		t.Errorf("linear midpoint: expected x 960, got %.2f", got)
+	}
+
+	// The zoompan and debug box expressions use the same curves
+	filter := GenerateZoomPanFilter(keyframes, 4.0, 30, 1920, 1080)
+	if !contains(filter, "(1.000000+(2.000000-1.000000)*pow(((on-0)/60),3))") {
+		t.Errorf("zoom should ease in: %s", filter)
+	}
+	if !contains(filter, "between(on,60,119)*((480.000000+(1440.000000-480.000000)*((on-60)/60))-(1920/zoom)/2)") {
+		t.Errorf("pan should be linear after the second keyframe: %s", filter)
+	}
+	box := GenerateDebugBoxFilter(keyframes, 30, 1920, 1080)
+	if !contains(box, "pow(((on-0)/60),3)") {
+		t.Errorf("debug box should ease like the camera: %s", box)
+	}
+}
+
+func TestGenerateZoomPanFilterRotation(t *testing.T) {
+	keyframes := []director.Keyframe{
+		{Time: 0.0, Rect: director.Rectangle{X: 0, Y: 0, W: 1920, H: 1080}, Zoom: 1.0},
+		{Time: 2.0, Rect: director.Rectangle{X: 100, Y: 100, W: 800, H: 600}, Zoom: 2.0, Rotation: 5},
+	}
+	filter := GenerateZoomPanFilter(keyframes, 3.0, 30, 1920, 1080)
+
+	// The window is cut with a margin from the padded input, turned and cropped to the frame
+	margin := director.RotationMargin(5, 1920, 1080)
+	size := fmt.Sprintf("s=%dx%d", evenCeil(1920*margin), evenCeil(1080*margin))
+	for _, part := range []string{"pad=w=ceil(iw*", "zoompan=", size, "rotate=a='(", "*PI/180':ow=1920:oh=1080"} {
+		if !contains(filter, part) {
+			t.Errorf("expected %q in the filter: %s", part, filter)
+		}
+	}
+	if !contains(filter, "between(n,0,59)*(0.000000+(5.000000-0.000000)*") {
+		t.Errorf("the angle should follow the keyframes by frame number: %s", filter)
+	}
+
+	keyframes[1].Rotation = 0
+	if filter := GenerateZoomPanFilter(keyframes, 3.0, 30, 1920, 1080); contains(filter, "rotate") || contains(filter, "pad=") {
+		t.Errorf("a camera without roll needs no rotation: %s", filter)
+	}
+}
+
+func TestEasingExprBezier(t *testing.T) {
+	e, err := director.ParseEasing("cubic-bezier(0.25, 0.1, 0.25, 1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expr := easingExpr(e, "(u)")
+	if !contains(expr, "*clip(((u)-0.000000)*24,0,1)") || !contains(expr, "*clip(((u)-0.958333)*24,0,1)") {
+		t.Errorf("expected clamped ramps over the samples: %s", expr)
+	}
+	if got := easingExpr(director.Easing{Kind: director.EasingLinear}, "(u)"); got != "(u)" {
+		t.Errorf("linear easing should keep the progress, got %s", got)
+	}
+}
+
 func abs(x float64) float64 {
 	if x < 0 {
 		return -x
@@ -0,0 +1,193 @@
+package source
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+// PageRef points to a page of a source
+type PageRef struct {
+	Source Source
+	Index  int // 0-based page index in Source
+}
+
+// CompositeSource is an edit list: every page is a page of some other source.
+// Pages can be reordered, repeated, dropped and mixed from several files.
+type CompositeSource struct {
+	pages  []PageRef
+	owned  []Source // Sources opened for the composite, closed with it
+	inputs []int    // Page count of every input of OpenInputs, in order
+}
+
+// NewCompositeSource builds a source from page references. The owned sources are
+// closed by Close; sources shared with the caller must not be listed there.
+func NewCompositeSource(pages []PageRef, owned []Source) (*CompositeSource, error) {
+	for i, p := range pages {
+		if p.Source == nil {
+			return nil, fmt.Errorf("page %d has no source", i+1)
+		}
+		if p.Index < 0 || p.Index >= p.Source.PageCount() {
+			return nil, fmt.Errorf("page %d refers to page %d of a source with %d pages", i+1, p.Index+1, p.Source.PageCount())
+		}
+	}
+	return &CompositeSource{pages: pages, owned: owned}, nil
+}
+
+// InputPages returns the page count of every input the composite was opened from with
+// OpenInputs, in order; the pages of an input follow those of the inputs before it.
+// It is nil for other composites.
+func (c *CompositeSource) InputPages() []int {
+	return c.inputs
+}
+
+func (c *CompositeSource) PageCount() int {
+	return len(c.pages)
+}
+
+func (c *CompositeSource) GetPageDimensions(index int) (float64, float64, error) {
+	p := c.pages[index]
+	return p.Source.GetPageDimensions(p.Index)
+}
+
+func (c *CompositeSource) RenderPage(index int, dpi int) (image.Image, error) {
+	p := c.pages[index]
+	return p.Source.RenderPage(p.Index, dpi)
+}
+
+func (c *CompositeSource) GetTextBlocks(index int, dpi int) ([]analyzer.Block, error) {
+	p := c.pages[index]
+	return p.Source.GetTextBlocks(p.Index, dpi)
+}
+
+func (c *CompositeSource) GetTextLines(index int, dpi int) ([]analyzer.TextLine, error) {
+	p := c.pages[index]
+	return p.Source.GetTextLines(p.Index, dpi)
+}
+
+func (c *CompositeSource) GetPageHash(index int) (string, error) {
+	p := c.pages[index]
+	return p.Source.GetPageHash(p.Index)
+}
+
+func (c *CompositeSource) HasTextLayer(index int) bool {
+	p := c.pages[index]
+	return p.Source.HasTextLayer(p.Index)
+}
+
+func (c *CompositeSource) Close() error {
+	var firstErr error
+	for _, s := range c.owned {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// OpenInputs opens several inputs as one source whose pages follow each other in
+// the order of the paths. A single input is opened as is. Every page keeps the hash
+// of its own input, so render caching stays per input.
+func OpenInputs(paths []string, opts Options) (Source, error) {
+	if len(paths) == 1 {
+		return OpenWith(paths[0], opts)
+	}
+
+	var owned []Source
+	var pages []PageRef
+	var inputs []int
+	closeOwned := func() {
+		for _, s := range owned {
+			s.Close()
+		}
+	}
+	for _, path := range paths {
+		src, err := OpenWith(path, opts)
+		if err != nil {
+			closeOwned()
+			return nil, err
+		}
+		owned = append(owned, src)
+		inputs = append(inputs, src.PageCount())
+		for i := 0; i < src.PageCount(); i++ {
+			pages = append(pages, PageRef{Source: src, Index: i})
+		}
+	}
+	composite, err := NewCompositeSource(pages, owned)
+	if err != nil {
+		closeOwned()
+		return nil, err
+	}
+	composite.inputs = inputs
+	return composite, nil
+}
+
+// PageFilter is a view of selected pages of another source. The pages keep their
+// order and everything of the underlying source, including page hashes.
+type PageFilter struct {
+	src   Source
+	pages []int // 0-based pages of src
+}
+
+// NewPageFilter selects pages (0-based, in order) of a source; Close closes the source
+func NewPageFilter(src Source, pages []int) (*PageFilter, error) {
+	for _, p := range pages {
+		if p < 0 || p >= src.PageCount() {
+			return nil, fmt.Errorf("page %d is out of range (source has %d pages)", p+1, src.PageCount())
+		}
+	}
+	return &PageFilter{src: src, pages: pages}, nil
+}
+
+// Source returns the underlying source
+func (f *PageFilter) Source() Source {
+	return f.src
+}
+
+// Page returns the 0-based page of the underlying source shown at index
+func (f *PageFilter) Page(index int) int {
+	return f.pages[index]
+}
+
+// Index finds where a page of the underlying source is shown, if it is selected
+func (f *PageFilter) Index(page int) (int, bool) {
+	for i, p := range f.pages {
+		if p == page {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (f *PageFilter) PageCount() int {
+	return len(f.pages)
+}
+
+func (f *PageFilter) GetPageDimensions(index int) (float64, float64, error) {
+	return f.src.GetPageDimensions(f.pages[index])
+}
+
+func (f *PageFilter) RenderPage(index int, dpi int) (image.Image, error) {
+	return f.src.RenderPage(f.pages[index], dpi)
+}
+
+func (f *PageFilter) GetTextBlocks(index int, dpi int) ([]analyzer.Block, error) {
+	return f.src.GetTextBlocks(f.pages[index], dpi)
+}
+
+func (f *PageFilter) GetTextLines(index int, dpi int) ([]analyzer.TextLine, error) {
+	return f.src.GetTextLines(f.pages[index], dpi)
+}
+
+func (f *PageFilter) GetPageHash(index int) (string, error) {
+	return f.src.GetPageHash(f.pages[index])
+}
+
+func (f *PageFilter) HasTextLayer(index int) bool {
+	return f.src.HasTextLayer(f.pages[index])
+}
+
+func (f *PageFilter) Close() error {
+	return f.src.Close()
+}
@@ -0,0 +1,109 @@
+package source
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenInputs(t *testing.T) {
+	dir := t.TempDir()
+	intro, outro := filepath.Join(dir, "intro.fb2"), filepath.Join(dir, "outro.fb2")
+	for _, path := range []string{intro, outro} {
+		if err := os.WriteFile(path, []byte(testFB2), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shots := filepath.Join(dir, "shots")
+	if err := os.Mkdir(shots, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writePNG(t, filepath.Join(shots, "1.png"), 64, 32)
+	writePNG(t, filepath.Join(shots, "2.png"), 32, 64)
+
+	src, err := OpenInputs([]string{intro, shots, outro}, Options{})
+	if err != nil {
+		t.Fatalf("OpenInputs failed: %v", err)
+	}
+	defer src.Close()
+
+	if src.PageCount() != 4 {
+		t.Fatalf("expected 4 pages (1 + 2 + 1), got %d", src.PageCount())
+	}
+	if w, h, err := src.GetPageDimensions(2); err != nil || w != 32 || h != 64 {
+		t.Errorf("page 3: expected the second screenshot 32x64, got %.0fx%.0f (%v)", w, h, err)
+	}
+
+	// The same book under two names is two inputs: its pages must not share a cache entry
+	first, err := src.GetPageHash(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := src.GetPageHash(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == last {
+		t.Error("pages of different inputs have the same hash")
+	}
+
+	if _, err := OpenInputs([]string{intro, filepath.Join(dir, "missing.pdf")}, Options{}); err == nil {
+		t.Error("expected an error for a missing input")
+	}
+}
+
+func TestPageFilter(t *testing.T) {
+	dir := t.TempDir()
+	for i, w := range []int{10, 20, 30, 40} {
+		writePNG(t, filepath.Join(dir, fmt.Sprintf("p%d.png", i+1)), w, 10)
+	}
+	src, err := NewImageSource(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := NewPageFilter(src, []int{0, 2, 3})
+	if err != nil {
+		t.Fatalf("NewPageFilter failed: %v", err)
+	}
+	defer filter.Close()
+
+	if filter.PageCount() != 3 {
+		t.Fatalf("expected 3 pages, got %d", filter.PageCount())
+	}
+	if w, _, err := filter.GetPageDimensions(1); err != nil || w != 30 {
+		t.Errorf("page 2 of the view: expected the third image, got width %.0f (%v)", w, err)
+	}
+	want, _ := src.GetPageHash(2)
+	if got, _ := filter.GetPageHash(1); got != want {
+		t.Error("the view must keep the page hash of the underlying source")
+	}
+	if filter.Page(1) != 2 {
+		t.Errorf("expected original page index 2, got %d", filter.Page(1))
+	}
+	if i, ok := filter.Index(3); !ok || i != 2 {
+		t.Errorf("Index(3) = %d, %v, want 2, true", i, ok)
+	}
+	if _, ok := filter.Index(1); ok {
+		t.Error("excluded page must not be found")
+	}
+
+	if _, err := NewPageFilter(src, []int{4}); err == nil {
+		t.Error("expected an error for a page out of range")
+	}
+}
@@ -0,0 +1,155 @@
+package source
+
+import (
+	"html"
+	"image"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+// The go-fitz text path reads the text layer from the MuPDF HTML of a page. It has no
+// glyph extents, fonts or words and serves the builds without structured text.
+
+var (
+	reBlock    = regexp.MustCompile(`(?i)<(?:div|p)[^>]+style="([^"]+)"`)
+	reLine     = regexp.MustCompile(`(?is)<p[^>]+style="([^"]+)"[^>]*>(.*?)</p>`)
+	reStylePt  = regexp.MustCompile(`([a-z-]+):\s*(-?[\d.]+)pt`)
+	reTag      = regexp.MustCompile(`<[^>]*>`)
+	lineCharEm = 0.5 // Average glyph width in font sizes, for the line width estimate
+)
+
+// fitzTextBlocks finds text blocks by the positions of the MuPDF HTML containers (<div>)
+// and lines (<p>)
+func (f *FitzPDFSource) fitzTextBlocks(index int, dpi int) ([]analyzer.Block, error) {
+	pageHTML, err := f.doc.HTML(index, false)
+	if err != nil {
+		return nil, err
+	}
+	scale := float64(textDPI(dpi)) / 72.0
+
+	var blocks []analyzer.Block
+	for _, m := range reBlock.FindAllStringSubmatch(pageHTML, -1) {
+		var left, top, width, height float64
+		var foundL, foundT, foundW, foundH bool
+		for _, p := range reStylePt.FindAllStringSubmatch(m[1], -1) {
+			val, _ := strconv.ParseFloat(p[2], 64)
+			switch p[1] {
+			case "left":
+				left, foundL = val, true
+			case "top":
+				top, foundT = val, true
+			case "width":
+				width, foundW = val, true
+			case "height":
+				height, foundH = val, true
+			}
+		}
+		if !foundL || !foundT {
+			continue
+		}
+
+		// Lines (<p>) only have left/top, their size is estimated
+		if !foundW {
+			width = 200
+		}
+		if !foundH {
+			height = 15
+		}
+		// Skip the page-level div and noise
+		if (width > 500 && height > 500) || width < 3 || height < 3 {
+			continue
+		}
+
+		blocks = append(blocks, analyzer.Block{
+			Rect: image.Rect(
+				int(left*scale),
+				int(top*scale),
+				int((left+width)*scale),
+				int((top+height)*scale),
+			),
+			Type:       analyzer.BlockTypeText,
+			Confidence: 1.0,
+			Score:      1.0,
+		})
+	}
+
+	// There is text but no positions: one big block keeps the camera moving
+	if len(blocks) == 0 && f.HasTextLayer(index) {
+		w, h, _ := f.GetPageDimensions(index)
+		blocks = append(blocks, analyzer.Block{
+			Rect: image.Rect(
+				int(w*0.1*scale),
+				int(h*0.1*scale),
+				int(w*0.9*scale),
+				int(h*0.9*scale),
+			),
+			Type: analyzer.BlockTypeText,
+		})
+	}
+
+	return blocks, nil
+}
+
+// fitzTextLines returns the lines of the MuPDF HTML with their text. MuPDF only gives
+// the line origin and height, the width is estimated from the font size.
+func (f *FitzPDFSource) fitzTextLines(index int, dpi int) ([]analyzer.TextLine, error) {
+	pageHTML, err := f.doc.HTML(index, false)
+	if err != nil {
+		return nil, err
+	}
+	scale := float64(textDPI(dpi)) / 72.0
+
+	var lines []analyzer.TextLine
+	for _, m := range reLine.FindAllStringSubmatch(pageHTML, -1) {
+		var left, top, lineHeight, fontSize float64
+		var foundL, foundT bool
+		for _, p := range reStylePt.FindAllStringSubmatch(m[1], -1) {
+			val, _ := strconv.ParseFloat(p[2], 64)
+			switch p[1] {
+			case "left":
+				left, foundL = val, true
+			case "top":
+				top, foundT = val, true
+			case "line-height":
+				lineHeight = val
+			}
+		}
+		if !foundL || !foundT {
+			continue
+		}
+
+		// Font size lives on the inner spans; the largest one defines the line
+		for _, p := range reStylePt.FindAllStringSubmatch(m[2], -1) {
+			if val, _ := strconv.ParseFloat(p[2], 64); p[1] == "font-size" && val > fontSize {
+				fontSize = val
+			}
+		}
+
+		text := strings.TrimSpace(html.UnescapeString(reTag.ReplaceAllString(m[2], "")))
+		if text == "" {
+			continue
+		}
+		if fontSize <= 0 {
+			fontSize = lineHeight
+		}
+		if lineHeight <= 0 {
+			lineHeight = fontSize
+		}
+		width := float64(len([]rune(text))) * fontSize * lineCharEm
+
+		lines = append(lines, analyzer.TextLine{
+			Rect: image.Rect(
+				int(left*scale),
+				int(top*scale),
+				int((left+width)*scale),
+				int((top+lineHeight)*scale),
+			),
+			Text: text,
+		})
+	}
+
+	return lines, nil
+}
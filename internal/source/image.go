@@ -77,11 +77,16 @@ func (s *ImageSource) RenderPage(index int, dpi int) (image.Image, error) {
 	return img, nil
 }
 
-func (s *ImageSource) GetTextBlocks(index int) ([]analyzer.Block, error) {
+func (s *ImageSource) GetTextBlocks(index int, dpi int) ([]analyzer.Block, error) {
 	// Image source doesn't support structured text extraction natively
 	return []analyzer.Block{}, nil
 }
 
+func (s *ImageSource) GetTextLines(index int, dpi int) ([]analyzer.TextLine, error) {
+	// Images have no text layer
+	return []analyzer.TextLine{}, nil
+}
+
 func (s *ImageSource) HasTextLayer(index int) bool {
 	return false
 }
@@ -99,10 +104,6 @@ func (s *ImageSource) GetPageHash(index int) (string, error) {
 	return fmt.Sprintf("%x", h), nil
 }
 
-func (s *ImageSource) SetDPI(dpi int) {
-	// Image source has fixed dimensions, DPI doesn't affect its scaling
-}
-
 func (s *ImageSource) Close() error {
 	return nil
 }
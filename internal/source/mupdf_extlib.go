@@ -0,0 +1,338 @@
+//go:build cgo && !nocgo && extlib
+
+package source
+
+// The wrapper is built only with the go-fitz extlib tag, where go-fitz itself links the
+// system MuPDF: the headers and the library come from the system (or from pkg-config
+// with the pkgconfig tag), the same flags go-fitz uses.
+
+/*
+#cgo !pkgconfig,!static LDFLAGS: -lmupdf -lm
+#cgo !pkgconfig,static LDFLAGS: -lmupdf -lm -lmupdf-third
+#cgo pkgconfig,!static pkg-config: mupdf
+#cgo pkgconfig,static pkg-config: --static mupdf
+
+#include <mupdf/fitz.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+// new_context creates a context of its own. MuPDF refuses it (NULL) when the linked
+// library is not the version of the headers this file is compiled with.
+static fz_context *new_context(void) {
+	fz_context *ctx = fz_new_context(NULL, NULL, FZ_STORE_DEFAULT);
+	if (!ctx)
+		return NULL;
+	fz_try(ctx)
+		fz_register_document_handlers(ctx);
+	fz_catch(ctx) {
+		fz_drop_context(ctx);
+		return NULL;
+	}
+	return ctx;
+}
+
+static int mupdf_available(void) {
+	fz_context *ctx = new_context();
+	if (!ctx)
+		return 0;
+	fz_drop_context(ctx);
+	return 1;
+}
+
+// open_doc opens a document from memory when data is set (MuPDF picks the handler by
+// magic), from the file otherwise
+static fz_document *open_doc(fz_context *ctx, const char *path, const char *magic, const unsigned char *data, size_t len) {
+	fz_stream *stm = NULL;
+	fz_document *doc = NULL;
+	fz_var(stm);
+	fz_try(ctx) {
+		if (data) {
+			stm = fz_open_memory(ctx, data, len);
+			doc = fz_open_document_with_stream(ctx, magic, stm);
+		} else {
+			doc = fz_open_document(ctx, path);
+		}
+	}
+	fz_always(ctx)
+		fz_drop_stream(ctx, stm);
+	fz_catch(ctx)
+		fz_rethrow(ctx);
+	return doc;
+}
+
+// mupdf_doc is a document open in a MuPDF context of its own
+typedef struct {
+	fz_context *ctx;
+	fz_document *doc;
+} mupdf_doc;
+
+static int open_mupdf_doc(mupdf_doc *d, const char *path, const char *magic, const unsigned char *data, size_t len, char *msg, int size) {
+	d->doc = NULL;
+	d->ctx = new_context();
+	if (!d->ctx) {
+		snprintf(msg, size, "cannot create a MuPDF %s context", FZ_VERSION);
+		return 0;
+	}
+	fz_try(d->ctx)
+		d->doc = open_doc(d->ctx, path, magic, data, len);
+	fz_catch(d->ctx) {
+		snprintf(msg, size, "%s", fz_caught_message(d->ctx));
+		fz_drop_context(d->ctx);
+		d->ctx = NULL;
+		return 0;
+	}
+	return 1;
+}
+
+static void close_mupdf_doc(mupdf_doc *d) {
+	if (!d->ctx)
+		return;
+	fz_drop_document(d->ctx, d->doc);
+	fz_drop_context(d->ctx);
+	d->ctx = NULL;
+	d->doc = NULL;
+}
+
+// reflow_to_pdf lays a reflowable document out on pages of w x h points and writes the
+// pages to a PDF in memory (*out, malloc'ed); the document stays laid out. A
+// fixed-layout document is left alone: *out stays NULL. A MuPDF error is caught here
+// and copied into msg.
+static int reflow_to_pdf(mupdf_doc *d, float w, float h, float em, unsigned char **out, size_t *out_len, char *msg, int size) {
+	fz_context *ctx = d->ctx;
+	fz_buffer *buf = NULL;
+	fz_document_writer *writer = NULL;
+	fz_page *page = NULL;
+	fz_var(buf);
+	fz_var(writer);
+	fz_var(page);
+
+	*out = NULL;
+	*out_len = 0;
+	if (!fz_is_document_reflowable(ctx, d->doc))
+		return 1;
+	fz_try(ctx) {
+		fz_layout_document(ctx, d->doc, w, h, em);
+
+		buf = fz_new_buffer(ctx, 64 << 10);
+		writer = fz_new_document_writer_with_buffer(ctx, buf, "pdf", NULL);
+		int count = fz_count_pages(ctx, d->doc);
+		for (int i = 0; i < count; i++) {
+			page = fz_load_page(ctx, d->doc, i);
+			fz_device *dev = fz_begin_page(ctx, writer, fz_bound_page(ctx, page));
+			fz_run_page(ctx, page, dev, fz_identity, NULL);
+			fz_end_page(ctx, writer);
+			fz_drop_page(ctx, page);
+			page = NULL;
+		}
+		fz_close_document_writer(ctx, writer);
+
+		unsigned char *pdf;
+		size_t n = fz_buffer_storage(ctx, buf, &pdf);
+		*out = malloc(n);
+		if (!*out)
+			fz_throw(ctx, FZ_ERROR_SYSTEM, "out of memory");
+		memcpy(*out, pdf, n);
+		*out_len = n;
+	}
+	fz_always(ctx) {
+		fz_drop_page(ctx, page);
+		fz_drop_document_writer(ctx, writer);
+		fz_drop_buffer(ctx, buf);
+	}
+	fz_catch(ctx) {
+		snprintf(msg, size, "%s", fz_caught_message(ctx));
+		return 0;
+	}
+	return 1;
+}
+
+// text_char is a character of the structured text with the bounds of its quad
+typedef struct {
+	int block, line;
+	int c;
+	float x0, y0, x1, y1;
+	float size;
+	int bold, italic;
+	char font[64];
+} text_char;
+
+// page_text reads the characters of the text blocks of a page in MuPDF order
+// (*out, malloc'ed). A MuPDF error is caught here and copied into msg.
+static int page_text(mupdf_doc *d, int number, text_char **out, int *count, char *msg, int size) {
+	fz_context *ctx = d->ctx;
+	fz_page *page = NULL;
+	fz_stext_page *text = NULL;
+	text_char *chars = NULL;
+	int n = 0, cap = 0, ok = 1;
+	fz_var(page);
+	fz_var(text);
+	fz_var(chars);
+	fz_var(n);
+	fz_var(cap);
+
+	*out = NULL;
+	*count = 0;
+	fz_try(ctx) {
+		fz_stext_options opts = { FZ_STEXT_PRESERVE_WHITESPACE };
+		page = fz_load_page(ctx, d->doc, number);
+		text = fz_new_stext_page_from_page(ctx, page, &opts);
+
+		int b = 0;
+		for (fz_stext_block *block = text->first_block; block; block = block->next) {
+			if (block->type != FZ_STEXT_BLOCK_TEXT)
+				continue;
+			int l = 0;
+			for (fz_stext_line *line = block->u.t.first_line; line; line = line->next, l++) {
+				for (fz_stext_char *ch = line->first_char; ch; ch = ch->next) {
+					if (n == cap) {
+						cap = cap ? cap * 2 : 256;
+						text_char *grown = realloc(chars, cap * sizeof(text_char));
+						if (!grown)
+							fz_throw(ctx, FZ_ERROR_SYSTEM, "out of memory");
+						chars = grown;
+					}
+					fz_rect r = fz_rect_from_quad(ch->quad);
+					text_char *tc = &chars[n++];
+					tc->block = b;
+					tc->line = l;
+					tc->c = ch->c;
+					tc->x0 = r.x0;
+					tc->y0 = r.y0;
+					tc->x1 = r.x1;
+					tc->y1 = r.y1;
+					tc->size = ch->size;
+					tc->bold = fz_font_is_bold(ctx, ch->font);
+					tc->italic = fz_font_is_italic(ctx, ch->font);
+					snprintf(tc->font, sizeof(tc->font), "%s", fz_font_name(ctx, ch->font));
+				}
+			}
+			b++;
+		}
+	}
+	fz_always(ctx) {
+		fz_drop_stext_page(ctx, text);
+		fz_drop_page(ctx, page);
+	}
+	fz_catch(ctx) {
+		free(chars);
+		snprintf(msg, size, "%s", fz_caught_message(ctx));
+		return 0;
+	}
+	*out = chars;
+	*count = n;
+	return ok;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"unsafe"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+// reflowEm is the base font size of reflowed documents (the MuPDF default)
+const reflowEm = 12
+
+// mupdfAvailable checks once that the linked MuPDF accepts a context of the headers
+var mupdfAvailable = sync.OnceValue(func() bool {
+	return C.mupdf_available() != 0
+})
+
+// ReflowSupported reports whether reflowable documents can be laid out at a custom page size
+func ReflowSupported() bool {
+	return mupdfAvailable()
+}
+
+// stextSupported reports whether structured text can be read
+func stextSupported() bool {
+	return mupdfAvailable()
+}
+
+// mupdfDoc is a document open in a MuPDF context of its own: go-fitz exposes neither
+// fz_layout_document nor fz_new_stext_page_from_page. A source keeps one for both the
+// reflow layout and the structured text. It is not safe for concurrent use.
+type mupdfDoc struct {
+	d    C.mupdf_doc
+	data unsafe.Pointer // C copy of the content the document is opened from
+}
+
+// openMupdfDoc opens the document from data when it is set (MuPDF picks the handler
+// by magic, as it would by a file extension), from the file otherwise
+func openMupdfDoc(path, magic string, data []byte) (*mupdfDoc, error) {
+	if !mupdfAvailable() {
+		return nil, errStextUnsupported
+	}
+	m := &mupdfDoc{}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	cmagic := C.CString(magic)
+	defer C.free(unsafe.Pointer(cmagic))
+	if len(data) > 0 {
+		m.data = C.CBytes(data)
+	}
+
+	var msg [256]C.char
+	if C.open_mupdf_doc(&m.d, cpath, cmagic, (*C.uchar)(m.data), C.size_t(len(data)), &msg[0], C.int(len(msg))) == 0 {
+		C.free(m.data)
+		return nil, fmt.Errorf("cannot open the document in MuPDF: %s", C.GoString(&msg[0]))
+	}
+	return m, nil
+}
+
+// reflow lays a reflowable document (EPUB, FB2, MOBI) out on pages of the given size in
+// points and returns the pages as a PDF, which go-fitz then opens like any other. The
+// document keeps the layout, so its structured text matches the PDF pages. A
+// fixed-layout document gives nil.
+func (m *mupdfDoc) reflow(width, height float64) ([]byte, error) {
+	var out *C.uchar
+	var outLen C.size_t
+	var msg [256]C.char
+	if C.reflow_to_pdf(&m.d, C.float(width), C.float(height), reflowEm, &out, &outLen, &msg[0], C.int(len(msg))) == 0 {
+		return nil, fmt.Errorf("reflow layout failed: %s", C.GoString(&msg[0]))
+	}
+	if out == nil {
+		return nil, nil
+	}
+	defer C.free(unsafe.Pointer(out))
+	return C.GoBytes(unsafe.Pointer(out), C.int(outLen)), nil
+}
+
+// page reads the structured text of a page
+func (m *mupdfDoc) page(index int) (StextPage, error) {
+	var out *C.text_char
+	var count C.int
+	var msg [256]C.char
+	if C.page_text(&m.d, C.int(index), &out, &count, &msg[0], C.int(len(msg))) == 0 {
+		return StextPage{}, fmt.Errorf("cannot read the text of page %d: %s", index+1, C.GoString(&msg[0]))
+	}
+	defer C.free(unsafe.Pointer(out))
+
+	chars := make([]stextChar, int(count))
+	for i, c := range unsafe.Slice(out, int(count)) {
+		chars[i] = stextChar{
+			block: int(c.block),
+			line:  int(c.line),
+			r:     rune(c.c),
+			box:   Box{float64(c.x0), float64(c.y0), float64(c.x1), float64(c.y1)},
+			style: analyzer.TextStyle{
+				Font: fontName(C.GoString(&c.font[0])),
+				// MuPDF sizes come from the text matrix; a tenth of a point keeps equal sizes equal
+				Size:   math.Round(float64(c.size)*10) / 10,
+				Bold:   c.bold != 0,
+				Italic: c.italic != 0,
+			},
+		}
+	}
+	return buildStext(chars), nil
+}
+
+func (m *mupdfDoc) close() {
+	C.close_mupdf_doc(&m.d)
+	C.free(m.data)
+	m.data = nil
+}
@@ -0,0 +1,32 @@
+//go:build !cgo || nocgo || !extlib
+
+package source
+
+// ReflowSupported reports whether reflowable documents can be laid out at a custom page
+// size; the MuPDF layout call needs cgo and the system MuPDF (the extlib tag)
+func ReflowSupported() bool {
+	return false
+}
+
+// stextSupported reports whether structured text can be read; it needs cgo and the
+// system MuPDF (the extlib tag). Without it the text layer comes from go-fitz.
+func stextSupported() bool {
+	return false
+}
+
+// mupdfDoc stands in for the MuPDF document of the extlib build
+type mupdfDoc struct{}
+
+func openMupdfDoc(path, magic string, data []byte) (*mupdfDoc, error) {
+	return nil, errStextUnsupported
+}
+
+func (m *mupdfDoc) reflow(width, height float64) ([]byte, error) {
+	return nil, errReflowUnsupported
+}
+
+func (m *mupdfDoc) page(index int) (StextPage, error) {
+	return StextPage{}, errStextUnsupported
+}
+
+func (m *mupdfDoc) close() {}
@@ -0,0 +1,189 @@
+package source
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sniffSize is how much of a file is read to recognize its format
+const sniffSize = 4096
+
+// Options tune how a source is opened
+type Options struct {
+	// Page size in points for reflowable documents (EPUB, FB2, MOBI);
+	// zero keeps the MuPDF default layout
+	ReflowWidth, ReflowHeight float64
+
+	format string // Name of the detected format, set by OpenWith
+}
+
+// Format is an input format recognized by its content
+type Format struct {
+	Name   string
+	Reflow bool // Pages are laid out at Options.ReflowWidth x ReflowHeight
+	match  func(s *sniffed) bool
+	open   func(path string, opts Options) (Source, error)
+}
+
+// sniffed is what format detection looks at
+type sniffed struct {
+	head    []byte   // First bytes of the file
+	entries []string // Entry names if the file is a ZIP archive
+}
+
+var (
+	openFitz  = func(path string, opts Options) (Source, error) { return NewFitzSource(path, opts) }
+	openImage = func(path string, opts Options) (Source, error) { return NewImageSource(path) }
+)
+
+// formats lists the supported formats in detection order
+var formats = []Format{
+	{Name: "PDF", match: func(s *sniffed) bool { return bytes.Contains(s.head[:min(len(s.head), 1024)], []byte("%PDF-")) }, open: openFitz},
+	{Name: "EPUB", Reflow: true, match: func(s *sniffed) bool { return s.hasEntry("META-INF/container.xml") }, open: openFitz},
+	{Name: "XPS", match: func(s *sniffed) bool { return s.hasEntryExt(".fdseq") }, open: openFitz}, // XPS and OpenXPS
+	{Name: "CBZ", match: func(s *sniffed) bool {
+		return s.office() == "" && s.hasEntryExt(".jpg", ".jpeg", ".png", ".gif", ".bmp", ".tif", ".tiff", ".jp2", ".jxr")
+	}, open: openFitz},
+	{Name: "FB2", Reflow: true, match: func(s *sniffed) bool { return bytes.Contains(s.head, []byte("<FictionBook")) }, open: openFitz},
+	{Name: "MOBI", Reflow: true, match: func(s *sniffed) bool {
+		return len(s.head) >= 68 && (string(s.head[60:68]) == "BOOKMOBI" || string(s.head[60:68]) == "TEXtREAd")
+	}, open: openFitz},
+	{Name: "PNG", match: func(s *sniffed) bool { return bytes.HasPrefix(s.head, []byte("\x89PNG\r\n\x1a\n")) }, open: openImage},
+	{Name: "JPEG", match: func(s *sniffed) bool { return bytes.HasPrefix(s.head, []byte("\xff\xd8\xff")) }, open: openImage},
+}
+
+// SupportedFormats lists the names of the formats Open accepts
+func SupportedFormats() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Open opens a document, an image or a folder of images by its path
+func Open(path string) (Source, error) {
+	return OpenWith(path, Options{})
+}
+
+// OpenWith opens a source, recognizing the format by the file content rather than
+// its extension. A folder is a sequence of images.
+func OpenWith(path string, opts Options) (Source, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return NewImageSource(path)
+	}
+
+	f, err := DetectFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !f.Reflow {
+		opts.ReflowWidth, opts.ReflowHeight = 0, 0
+	}
+	opts.format = f.Name
+	src, err := f.open(path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: cannot open %s document: %v", path, f.Name, err)
+	}
+	return src, nil
+}
+
+// DetectFormat recognizes the format of a file by its content
+func DetectFormat(path string) (Format, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Format{}, err
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return Format{}, err
+	}
+	s := &sniffed{head: head[:n]}
+
+	if bytes.HasPrefix(s.head, []byte("PK\x03\x04")) {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return Format{}, fmt.Errorf("%s: broken ZIP archive: %v", path, err)
+		}
+		for _, entry := range archive.File {
+			s.entries = append(s.entries, entry.Name)
+		}
+		archive.Close()
+	}
+
+	for _, f := range formats {
+		if f.match(s) {
+			return f, nil
+		}
+	}
+	return Format{}, fmt.Errorf("%s: unsupported input format%s (supported: %s or a folder of images)",
+		path, s.describe(), strings.Join(SupportedFormats(), ", "))
+}
+
+func (s *sniffed) hasEntry(name string) bool {
+	for _, e := range s.entries {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sniffed) hasEntryExt(exts ...string) bool {
+	for _, e := range s.entries {
+		ext := strings.ToLower(filepath.Ext(e))
+		for _, want := range exts {
+			if ext == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// office names the Office Open XML format of an archive, if it is one
+func (s *sniffed) office() string {
+	switch {
+	case s.hasEntry("word/document.xml"):
+		return "DOCX"
+	case s.hasEntry("ppt/presentation.xml"):
+		return "PPTX"
+	case s.hasEntry("xl/workbook.xml"):
+		return "XLSX"
+	}
+	return ""
+}
+
+// describe names what an unsupported file looks like, for the error message
+func (s *sniffed) describe() string {
+	switch {
+	case s.office() != "":
+		return " (" + s.office() + ")"
+	case s.entries != nil:
+		return " (ZIP archive)"
+	case len(s.head) == 0:
+		return " (empty file)"
+	}
+	return ""
+}
+
+// magic names the document handler for MuPDF: the detected format, or the file
+// extension when the source is opened directly
+func (o Options) magic(path string) string {
+	if o.format != "" {
+		return strings.ToLower(o.format)
+	}
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+}
@@ -0,0 +1,113 @@
+package source
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeZip(t *testing.T, path string, names ...string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for _, name := range names {
+		if _, err := w.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const testFB2 = `<?xml version="1.0" encoding="UTF-8"?>
+<FictionBook xmlns="http://www.gribuser.ru/xml/fictionbook/2.0"><body><section><p>Hello, reflow</p></section></body></FictionBook>`
+
+func TestDetectFormat(t *testing.T) {
+	dir := t.TempDir()
+	file := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	archive := func(name string, entries ...string) string {
+		path := filepath.Join(dir, name)
+		writeZip(t, path, entries...)
+		return path
+	}
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr string
+	}{
+		{file("doc.bin", "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), "PDF", ""},
+		{archive("book", "mimetype", "META-INF/container.xml", "OEBPS/ch1.xhtml"), "EPUB", ""},
+		{archive("doc.oxps", "[Content_Types].xml", "FixedDocumentSequence.fdseq"), "XPS", ""},
+		{archive("comic.zip", "001.png", "002.jpg"), "CBZ", ""},
+		{file("book.xml", testFB2), "FB2", ""},
+		{file("book.dat", strings.Repeat("\x00", 60)+"BOOKMOBI"+strings.Repeat("\x00", 16)), "MOBI", ""},
+		{file("slide", "\x89PNG\r\n\x1a\n\x00\x00"), "PNG", ""},
+		{archive("report.zip", "word/document.xml", "word/media/image1.png"), "", "DOCX"},
+		{archive("data.zip", "notes.txt"), "", "ZIP archive"},
+		{file("notes.pdf", "just text"), "", "unsupported input format"},
+	}
+	for _, tt := range tests {
+		f, err := DetectFormat(tt.path)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want one mentioning %q", filepath.Base(tt.path), err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || f.Name != tt.want {
+			t.Errorf("%s: format = %q (%v), want %s", filepath.Base(tt.path), f.Name, err, tt.want)
+		}
+	}
+}
+
+func TestOpenWithReflow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book") // No extension: the format comes from the content
+	if err := os.WriteFile(path, []byte(testFB2), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		opts          Options
+		width, height float64
+	}{
+		{Options{}, 450, 600}, // MuPDF default layout
+		{Options{ReflowWidth: 320, ReflowHeight: 180}, 320, 180},
+	} {
+		if tt.opts.ReflowWidth > 0 && !ReflowSupported() {
+			t.Log("reflow layout is not supported by this build")
+			continue
+		}
+		src, err := OpenWith(path, tt.opts)
+		if err != nil {
+			t.Fatalf("OpenWith(%+v): %v", tt.opts, err)
+		}
+		w, h, err := src.GetPageDimensions(0)
+		if err != nil || w != tt.width || h != tt.height {
+			t.Errorf("OpenWith(%+v): page = %vx%v (%v), want %vx%v", tt.opts, w, h, err, tt.width, tt.height)
+		}
+		if img, err := src.RenderPage(0, 72); err != nil || img.Bounds().Dx() != int(tt.width) {
+			t.Errorf("OpenWith(%+v): rendered page does not use the layout (%v)", tt.opts, err)
+		}
+		// The text layer lies on the same pages
+		if lines, err := src.GetTextLines(0, 72); err != nil || len(lines) == 0 {
+			t.Errorf("OpenWith(%+v): text lines = %v (%v)", tt.opts, lines, err)
+		} else if r := lines[0].Rect; r.Min.X < 0 || r.Max.Y > int(tt.height) {
+			t.Errorf("OpenWith(%+v): first line %v is off the %vx%v page", tt.opts, r, tt.width, tt.height)
+		}
+		src.Close()
+	}
+}
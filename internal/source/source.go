@@ -2,48 +2,77 @@ package source
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"image"
-	"regexp"
-	"strconv"
+	"os"
 	"sync"
 
 	"github.com/gen2brain/go-fitz"
 	"github.com/ivlev/pdf2video/internal/analyzer"
 )
 
+var (
+	// errReflowUnsupported means this build cannot lay reflowable documents out
+	errReflowUnsupported = errors.New("reflow layout is not supported by this build")
+	// errStextUnsupported means this build cannot read structured text
+	errStextUnsupported = errors.New("structured text is not supported by this build")
+)
+
 type Source interface {
 	PageCount() int
 	GetPageDimensions(index int) (width, height float64, err error)
 	RenderPage(index int, dpi int) (image.Image, error)
-	GetTextBlocks(index int) ([]analyzer.Block, error)
+	GetTextBlocks(index int, dpi int) ([]analyzer.Block, error)
+	GetTextLines(index int, dpi int) ([]analyzer.TextLine, error)
 	GetPageHash(index int) (string, error)
 	HasTextLayer(index int) bool
-	SetDPI(dpi int)
 	Close() error
 }
 
+// FitzPDFSource reads PDF and every other document format MuPDF opens
 type FitzPDFSource struct {
-	doc  *fitz.Document
-	path string
-	pool sync.Pool
-	dpi  int
+	doc   *fitz.Document
+	path  string
+	data  []byte // File content when MuPDF needs it from memory
+	magic string // MuPDF handler for data
+	opts  Options
+	pool  sync.Pool
+
+	stextMu sync.Mutex
+	mupdf   *mupdfDoc         // MuPDF document of the reflow layout and structured text
+	stext   map[int]StextPage // Text layer by page
 }
 
 func NewFitzPDFSource(path string) (*FitzPDFSource, error) {
-	doc, err := fitz.New(path)
-	if err != nil {
-		return nil, err
-	}
+	return NewFitzSource(path, Options{})
+}
 
+// NewFitzSource opens a document with MuPDF; reflowable documents are laid out
+// at the reflow page size of the options
+func NewFitzSource(path string, opts Options) (*FitzPDFSource, error) {
 	f := &FitzPDFSource{
-		doc:  doc,
-		path: path,
-		dpi:  300, // Default
+		path:  path,
+		magic: opts.magic(path),
+		opts:  opts,
+	}
+	// Without layout support the document keeps the MuPDF default pages; the caller
+	// checks ReflowSupported and warns
+	if opts.ReflowWidth > 0 && opts.ReflowHeight > 0 && ReflowSupported() {
+		if err := f.reflow(opts.ReflowWidth, opts.ReflowHeight); err != nil {
+			return nil, err
+		}
 	}
 
+	doc, err := f.openDocument()
+	if err != nil {
+		f.closeMupdf()
+		return nil, err
+	}
+	f.doc = doc
+
 	f.pool.New = func() interface{} {
-		d, err := fitz.New(f.path)
+		d, err := f.openDocument()
 		if err != nil {
 			return nil
 		}
@@ -53,6 +82,57 @@ func NewFitzPDFSource(path string) (*FitzPDFSource, error) {
 	return f, nil
 }
 
+// reflow lays the document out in MuPDF and keeps its pages as a PDF for go-fitz. The
+// laid out MuPDF document stays open for the structured text of the same pages.
+func (f *FitzPDFSource) reflow(width, height float64) error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("empty document")
+	}
+	doc, err := openMupdfDoc(f.path, f.magic, data)
+	if err != nil {
+		return err
+	}
+	pdf, err := doc.reflow(width, height)
+	if err != nil {
+		doc.close()
+		return err
+	}
+	if pdf != nil {
+		f.data, f.magic = pdf, "pdf"
+	}
+	f.mupdf = doc
+	return nil
+}
+
+// openDocument opens another handle of the document; a reflowed document is opened
+// from its laid out PDF. MuPDF picks some handlers (FB2, MOBI) by the file extension,
+// a file without the expected one is opened from memory, where go-fitz recognizes it
+// by content.
+func (f *FitzPDFSource) openDocument() (*fitz.Document, error) {
+	var doc *fitz.Document
+	var err error
+	if f.data == nil {
+		doc, err = fitz.New(f.path)
+		if err != nil && err != fitz.ErrNoSuchFile {
+			if data, errRead := os.ReadFile(f.path); errRead == nil {
+				if doc, err = fitz.NewFromMemory(data); err == nil {
+					f.data = data
+				}
+			}
+		}
+	} else {
+		doc, err = fitz.NewFromMemory(f.data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
 func (f *FitzPDFSource) PageCount() int {
 	return f.doc.NumPage()
 }
@@ -76,124 +156,65 @@ func (f *FitzPDFSource) RenderPage(index int, dpi int) (image.Image, error) {
 	return workerDoc.ImageDPI(index, float64(dpi))
 }
 
-func (f *FitzPDFSource) GetTextBlocks(index int) ([]analyzer.Block, error) {
-	// MuPDF's Text() method returns structured text including block information.
-	// The format is generally block-based, often with coordinates in the output
-	// or structured in a way that fitz-go can potentially expose.
-	// Since go-fitz's Text() returns a string, we'll use it to detect if text exists,
-	// but for REAL block coordinates, we should ideally use a more advanced method
-	// if go-fitz supports it. Checking go-fitz, it has a Blocks() method in newer versions.
-
-	// Let's try to use PlainText or similar if available, or fallback to a simpler approach.
-	// However, the user reported that camera doesn't move.
-	// This is because our HTML regex failed to find ANY blocks.
+// StructuredText returns the blocks, lines and words of the page text layer with the
+// extents of their characters (page points). The MuPDF document is opened once per
+// source, unless the reflow layout already did.
+func (f *FitzPDFSource) StructuredText(index int) (StextPage, error) {
+	f.stextMu.Lock()
+	defer f.stextMu.Unlock()
+	if page, ok := f.stext[index]; ok {
+		return page, nil
+	}
 
-	// FIX: Use go-fitz's structured text if possible.
-	// In the absence of a direct "Blocks" method in this version of go-fitz,
-	// we will use the text layer to at least identify that there IS content.
-	// BUT to fix the "no movement" issue, we need valid coordinates.
+	if f.mupdf == nil {
+		doc, err := openMupdfDoc(f.path, f.magic, f.data)
+		if err != nil {
+			return StextPage{}, err
+		}
+		f.mupdf = doc
+	}
+	page, err := f.mupdf.page(index)
+	if err != nil {
+		return StextPage{}, err
+	}
+	if f.stext == nil {
+		f.stext = make(map[int]StextPage)
+	}
+	f.stext[index] = page
+	return page, nil
+}
 
-	// Re-evaluating HTML parsing: the issue was the regex being too strict.
-	// Let's use a MUCH simpler regex that just finds ANY 'left:pt' etc.
-	html, err := f.doc.HTML(index, false)
+// GetTextBlocks returns the text blocks of the page in pixels of a render at the given DPI
+func (f *FitzPDFSource) GetTextBlocks(index int, dpi int) ([]analyzer.Block, error) {
+	if !stextSupported() {
+		return f.fitzTextBlocks(index, dpi)
+	}
+	page, err := f.StructuredText(index)
 	if err != nil {
 		return nil, err
 	}
+	return page.AnalyzerBlocks(textDPI(dpi)), nil
+}
 
-	// MuPDF HTML style attribute is notoriously inconsistent.
-	// We'll search for both <div> (containers/blocks) and <p> (lines) tags.
-	reTags := regexp.MustCompile(`(?i)<(?:div|p)[^>]+style="([^"]+)"`)
-	matches := reTags.FindAllStringSubmatch(html, -1)
-
-	var blocks []analyzer.Block
-	dpi := f.dpi
-	if dpi <= 0 {
-		dpi = 300
-	}
-	scale := float64(dpi) / 72.0
-
-	for _, m := range matches {
-		style := m[1]
-
-		var left, top, width, height float64
-		var foundL, foundT bool
-		var foundW, foundH bool
-
-		// Extract values. Some tags only have left/top (like <p>),
-		// some have all four (like <div>).
-		parts := regexp.MustCompile(`([a-z-]+):\s*(-?[\d.]+)pt`).FindAllStringSubmatch(style, -1)
-		for _, p := range parts {
-			val, _ := strconv.ParseFloat(p[2], 64)
-			switch p[1] {
-			case "left":
-				left = val
-				foundL = true
-			case "top":
-				top = val
-				foundT = true
-			case "width":
-				width = val
-				foundW = true
-			case "height":
-				height = val
-				foundH = true
-			}
-		}
-
-		if foundL && foundT {
-			// If it's a <p> tag or similar, it might only have left/top.
-			// We'll estimate a reasonable width/height for a line if missing.
-			if !foundW {
-				width = 200 // reasonable minimum line width
-			}
-			if !foundH {
-				height = 15 // reasonable line height
-			}
-
-			// Skip page-level div (usually fills most of the page)
-			if width > 500 && height > 500 {
-				continue
-			}
-
-			// Ignore noise
-			if width < 3 || height < 3 {
-				continue
-			}
-
-			blocks = append(blocks, analyzer.Block{
-				Rect: image.Rect(
-					int(left*scale),
-					int(top*scale),
-					int((left+width)*scale),
-					int((top+height)*scale),
-				),
-				Type:       analyzer.BlockTypeText,
-				Confidence: 1.0,
-				Score:      1.0,
-			})
-		}
+// GetTextLines returns the lines of the text layer with their text and words (words
+// only with structured text)
+func (f *FitzPDFSource) GetTextLines(index int, dpi int) ([]analyzer.TextLine, error) {
+	if !stextSupported() {
+		return f.fitzTextLines(index, dpi)
 	}
-
-	// If we still found nothing but THERE IS text, we create one big block for the page
-	// to at least allow some movement or centering.
-	if len(blocks) == 0 && f.HasTextLayer(index) {
-		w, h, _ := f.GetPageDimensions(index)
-		blocks = append(blocks, analyzer.Block{
-			Rect: image.Rect(
-				int(w*0.1*scale),
-				int(h*0.1*scale),
-				int(w*0.9*scale),
-				int(h*0.9*scale),
-			),
-			Type: analyzer.BlockTypeText,
-		})
+	page, err := f.StructuredText(index)
+	if err != nil {
+		return nil, err
 	}
-
-	return blocks, nil
+	return page.AnalyzerLines(textDPI(dpi)), nil
 }
 
-func (f *FitzPDFSource) SetDPI(dpi int) {
-	f.dpi = dpi
+// textDPI is the resolution of the text layer coordinates (300 when not set)
+func textDPI(dpi int) int {
+	if dpi <= 0 {
+		return 300
+	}
+	return dpi
 }
 
 func (f *FitzPDFSource) HasTextLayer(index int) bool {
@@ -217,5 +238,15 @@ func (f *FitzPDFSource) GetPageHash(index int) (string, error) {
 }
 
 func (f *FitzPDFSource) Close() error {
+	f.closeMupdf()
 	return f.doc.Close()
 }
+
+func (f *FitzPDFSource) closeMupdf() {
+	f.stextMu.Lock()
+	defer f.stextMu.Unlock()
+	if f.mupdf != nil {
+		f.mupdf.close()
+		f.mupdf = nil
+	}
+}
@@ -0,0 +1,237 @@
+package source
+
+import (
+	"image"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+// Structured text of a page as MuPDF finds it (fz_new_stext_page_from_page): blocks,
+// lines and characters with their quads, read through the cgo wrapper.
+
+const (
+	headerSizeRatio = 1.3 // Blocks set this much larger than the body text are headers
+	minBlockPixels  = 3   // Smaller blocks are noise
+)
+
+// Box is a rectangle in page points
+type Box struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// Empty reports whether the box has no area
+func (b Box) Empty() bool {
+	return b.X1 <= b.X0 || b.Y1 <= b.Y0
+}
+
+// Union returns the smallest box containing both boxes; empty boxes are ignored
+func (b Box) Union(o Box) Box {
+	if o.Empty() {
+		return b
+	}
+	if b.Empty() {
+		return o
+	}
+	return Box{math.Min(b.X0, o.X0), math.Min(b.Y0, o.Y0), math.Max(b.X1, o.X1), math.Max(b.Y1, o.Y1)}
+}
+
+// Pixels converts the box to render pixels at the given DPI, rounding outwards
+func (b Box) Pixels(dpi int) image.Rectangle {
+	s := float64(dpi) / 72.0
+	return image.Rect(int(math.Floor(b.X0*s)), int(math.Floor(b.Y0*s)), int(math.Ceil(b.X1*s)), int(math.Ceil(b.Y1*s)))
+}
+
+// StextWord is a run of characters without spaces
+type StextWord struct {
+	Box   Box // Bounds of the character quads
+	Text  string
+	Style analyzer.TextStyle // Style of the first character
+}
+
+// StextLine is a line of text as MuPDF found it
+type StextLine struct {
+	Box   Box
+	Text  string
+	Style analyzer.TextStyle // Style of most of the characters
+	Words []StextWord
+}
+
+// StextBlock is a block of lines as MuPDF found it
+type StextBlock struct {
+	Box   Box
+	Style analyzer.TextStyle
+	Lines []StextLine
+}
+
+// StextPage is the structured text of a page in MuPDF order
+type StextPage struct {
+	Blocks []StextBlock
+}
+
+// stextChar is a character of the MuPDF structured text with the bounds of its quad;
+// block and line number it within the page and the block
+type stextChar struct {
+	block, line int
+	r           rune
+	style       analyzer.TextStyle
+	box         Box
+}
+
+// buildStext groups the characters into the blocks and lines MuPDF found and splits
+// the lines into words at spaces. Blocks and lines without visible text are left out.
+func buildStext(chars []stextChar) StextPage {
+	var page StextPage
+	for start := 0; start < len(chars); {
+		end := start
+		for end < len(chars) && chars[end].block == chars[start].block {
+			end++
+		}
+		if block, ok := buildBlock(chars[start:end]); ok {
+			page.Blocks = append(page.Blocks, block)
+		}
+		start = end
+	}
+	return page
+}
+
+func buildBlock(chars []stextChar) (StextBlock, bool) {
+	var block StextBlock
+	var styles styleCounter
+	for start := 0; start < len(chars); {
+		end := start
+		for end < len(chars) && chars[end].line == chars[start].line {
+			end++
+		}
+		if line, ok := buildLine(chars[start:end]); ok {
+			block.Lines = append(block.Lines, line)
+			block.Box = block.Box.Union(line.Box)
+			for _, w := range line.Words {
+				styles.add(w.Style, len([]rune(w.Text)))
+			}
+		}
+		start = end
+	}
+	block.Style = styles.best
+	return block, len(block.Lines) > 0
+}
+
+func buildLine(chars []stextChar) (StextLine, bool) {
+	var line StextLine
+	var text strings.Builder
+	var word *StextWord
+	var styles styleCounter
+	for _, c := range chars {
+		text.WriteRune(c.r)
+		if unicode.IsSpace(c.r) {
+			word = nil
+			continue
+		}
+		styles.add(c.style, 1)
+		if word == nil {
+			line.Words = append(line.Words, StextWord{Style: c.style})
+			word = &line.Words[len(line.Words)-1]
+		}
+		word.Text += string(c.r)
+		word.Box = word.Box.Union(c.box)
+		line.Box = line.Box.Union(c.box)
+	}
+	line.Text = strings.TrimSpace(text.String())
+	line.Style = styles.best
+	return line, line.Text != "" && !line.Box.Empty()
+}
+
+// styleCounter finds the style most characters are set in
+type styleCounter struct {
+	counts map[analyzer.TextStyle]int
+	best   analyzer.TextStyle
+}
+
+func (c *styleCounter) add(style analyzer.TextStyle, chars int) {
+	if c.counts == nil {
+		c.counts = make(map[analyzer.TextStyle]int)
+	}
+	c.counts[style] += chars
+	if c.counts[style] > c.counts[c.best] {
+		c.best = style
+	}
+}
+
+// bodySize is the font size most of the page text is set in
+func (p StextPage) bodySize() float64 {
+	counts := make(map[float64]int)
+	best := 0.0
+	for _, b := range p.Blocks {
+		for _, line := range b.Lines {
+			for _, w := range line.Words {
+				counts[w.Style.Size] += len([]rune(w.Text))
+				if counts[w.Style.Size] > counts[best] {
+					best = w.Style.Size
+				}
+			}
+		}
+	}
+	return best
+}
+
+// AnalyzerBlocks converts the blocks to render pixels at the given DPI. Blocks set
+// noticeably larger than the body text become headers.
+func (p StextPage) AnalyzerBlocks(dpi int) []analyzer.Block {
+	body := p.bodySize()
+	var blocks []analyzer.Block
+	for _, b := range p.Blocks {
+		rect := b.Box.Pixels(dpi)
+		if rect.Dx() < minBlockPixels || rect.Dy() < minBlockPixels {
+			continue
+		}
+		blockType := analyzer.BlockTypeText
+		if body > 0 && b.Style.Size >= headerSizeRatio*body {
+			blockType = analyzer.BlockTypeHeader
+		}
+		lines := make([]analyzer.TextLine, len(b.Lines))
+		words := 0
+		for i, line := range b.Lines {
+			lines[i] = line.analyzerLine(dpi)
+			words += analyzer.CountWords(line.Text)
+		}
+		blocks = append(blocks, analyzer.Block{
+			Rect:       rect,
+			Type:       blockType,
+			Confidence: 1.0,
+			Score:      1.0,
+			WordCount:  words,
+			Lines:      lines,
+			Style:      b.Style,
+		})
+	}
+	return blocks
+}
+
+// AnalyzerLines converts all lines of the page to render pixels at the given DPI
+func (p StextPage) AnalyzerLines(dpi int) []analyzer.TextLine {
+	var lines []analyzer.TextLine
+	for _, b := range p.Blocks {
+		for _, line := range b.Lines {
+			lines = append(lines, line.analyzerLine(dpi))
+		}
+	}
+	return lines
+}
+
+func (l StextLine) analyzerLine(dpi int) analyzer.TextLine {
+	words := make([]analyzer.TextWord, len(l.Words))
+	for i, w := range l.Words {
+		words[i] = analyzer.TextWord{Rect: w.Box.Pixels(dpi), Text: w.Text, Style: w.Style}
+	}
+	return analyzer.TextLine{Rect: l.Box.Pixels(dpi), Text: l.Text, Style: l.Style, Words: words}
+}
+
+// fontName drops the subset prefix of an embedded font ("ABCDEF+Arial-Bold" -> "Arial-Bold")
+func fontName(name string) string {
+	if _, rest, ok := strings.Cut(name, "+"); ok {
+		return rest
+	}
+	return name
+}
@@ -0,0 +1,203 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ivlev/pdf2video/internal/analyzer"
+)
+
+// textChars lays text out as MuPDF characters of a block and line, 0.6 font sizes
+// apart, with quads from the baseline up to 0.7 font sizes above it
+func textChars(block, line int, text string, x, baseline float64, style analyzer.TextStyle) []stextChar {
+	var chars []stextChar
+	for _, r := range text {
+		chars = append(chars, stextChar{
+			block: block, line: line, r: r, style: style,
+			box: Box{x, baseline - 0.7*style.Size, x + 0.5*style.Size, baseline},
+		})
+		x += 0.6 * style.Size
+	}
+	return chars
+}
+
+func testPage() StextPage {
+	heading := analyzer.TextStyle{Font: "Arial-Bold", Size: 20, Bold: true}
+	body := analyzer.TextStyle{Font: "Times", Size: 10}
+	italic := analyzer.TextStyle{Font: "Times", Size: 10, Italic: true}
+
+	var chars []stextChar
+	chars = append(chars, textChars(0, 0, "Hi", 10, 26, heading)...)
+	chars = append(chars, textChars(1, 0, "ab ", 10, 58, body)...)
+	chars = append(chars, textChars(1, 0, "c&", 28, 58, italic)...)
+	chars = append(chars, textChars(1, 1, "ef", 10, 71, body)...)
+	chars = append(chars, textChars(2, 0, "  ", 10, 90, body)...) // Whitespace only
+	chars = append(chars, textChars(3, 0, "Z", 300, 71, body)...)
+	return buildStext(chars)
+}
+
+func TestBuildStext(t *testing.T) {
+	page := testPage()
+	if len(page.Blocks) != 3 {
+		t.Fatalf("blocks = %d, want 3 (heading, paragraph, far column)", len(page.Blocks))
+	}
+
+	heading := page.Blocks[0]
+	want := analyzer.TextStyle{Font: "Arial-Bold", Size: 20, Bold: true}
+	if heading.Style != want {
+		t.Errorf("heading style = %+v, want %+v", heading.Style, want)
+	}
+	if got := (Box{10, 12, 32, 26}); heading.Box != got {
+		t.Errorf("heading box = %+v, want the character extents %+v", heading.Box, got)
+	}
+
+	para := page.Blocks[1]
+	if len(para.Lines) != 2 {
+		t.Fatalf("paragraph lines = %d, want 2", len(para.Lines))
+	}
+	first := para.Lines[0]
+	if first.Text != "ab c&" || len(first.Words) != 2 {
+		t.Fatalf("first line = %q with %d words, want \"ab c&\" with 2", first.Text, len(first.Words))
+	}
+	word := first.Words[1]
+	if word.Text != "c&" || word.Box != (Box{28, 51, 39, 58}) {
+		t.Errorf("second word = %q %+v, want \"c&\" {28 51 39 58}", word.Text, word.Box)
+	}
+	if !word.Style.Italic || first.Words[0].Style.Italic {
+		t.Error("only the second word is italic")
+	}
+	if para.Lines[1].Box != (Box{10, 64, 21, 71}) {
+		t.Errorf("second line box = %+v", para.Lines[1].Box)
+	}
+	if para.Style != (analyzer.TextStyle{Font: "Times", Size: 10}) {
+		t.Errorf("paragraph style = %+v, want the style of most characters", para.Style)
+	}
+}
+
+func TestStextAnalyzerBlocks(t *testing.T) {
+	blocks := testPage().AnalyzerBlocks(144)
+	if len(blocks) != 3 {
+		t.Fatalf("blocks = %d, want 3", len(blocks))
+	}
+	if blocks[0].Type != analyzer.BlockTypeHeader || blocks[1].Type != analyzer.BlockTypeText {
+		t.Errorf("types = %s, %s, want header (twice the body size) and text", blocks[0].Type, blocks[1].Type)
+	}
+	if blocks[1].Rect != image.Rect(20, 102, 78, 142) {
+		t.Errorf("paragraph rect = %v, want the character extents at 144 DPI", blocks[1].Rect)
+	}
+	if blocks[1].WordCount != 3 || len(blocks[1].Lines[0].Words) != 2 {
+		t.Errorf("paragraph words = %d, want 3", blocks[1].WordCount)
+	}
+}
+
+// writeTextPDF writes a one-page PDF with a horizontal line and a line turned by 90°
+func writeTextPDF(t *testing.T) string {
+	content := "BT /F1 12 Tf 1 0 0 1 72 700 Tm (Hello world) Tj ET\n" +
+		"BT /F1 12 Tf 0 1 -1 0 300 400 Tm (Sideways) Tj ET\n"
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+
+	var sb strings.Builder
+	sb.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = sb.Len()
+		fmt.Fprintf(&sb, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := sb.Len()
+	fmt.Fprintf(&sb, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&sb, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&sb, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	path := filepath.Join(t.TempDir(), "text.pdf")
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStructuredText(t *testing.T) {
+	src, err := NewFitzPDFSource(writeTextPDF(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	page, err := src.StructuredText(0)
+	if errors.Is(err, errStextUnsupported) {
+		t.Skip("structured text is not supported by this build")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make(map[string]StextLine)
+	for _, b := range page.Blocks {
+		for _, line := range b.Lines {
+			lines[line.Text] = line
+		}
+	}
+	hello, ok := lines["Hello world"]
+	if !ok || len(hello.Words) != 2 || hello.Style.Font != "Helvetica" || hello.Style.Size != 12 {
+		t.Fatalf("horizontal line = %+v, want \"Hello world\" in Helvetica 12 (lines %v)", hello, lines)
+	}
+	if b := hello.Box; b.X0 < 71 || b.X0 > 73 || b.Y1 < 92 || b.Y1 > 96 {
+		t.Errorf("horizontal line box = %+v, want it at x 72 on the baseline 92 from the top", b)
+	}
+
+	// Rotated text is kept, with the box of its turned quads
+	side, ok := lines["Sideways"]
+	if !ok {
+		t.Fatalf("rotated line missing, lines %v", lines)
+	}
+	if b := side.Box; b.Y1-b.Y0 <= b.X1-b.X0 {
+		t.Errorf("rotated line box = %+v, want it taller than wide", b)
+	}
+}
+
+func TestTextLayer(t *testing.T) {
+	// Every build reads the text layer: structured text with cgo and the system MuPDF,
+	// the go-fitz HTML otherwise
+	src, err := NewFitzPDFSource(writeTextPDF(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	if !src.HasTextLayer(0) {
+		t.Fatal("expected a text layer")
+	}
+	lines, err := src.GetTextLines(0, 72)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hello *analyzer.TextLine
+	for i := range lines {
+		if lines[i].Text == "Hello world" {
+			hello = &lines[i]
+		}
+	}
+	if hello == nil {
+		t.Fatalf("lines = %+v, want \"Hello world\"", lines)
+	}
+	if r := hello.Rect; r.Min.X < 70 || r.Min.X > 74 || r.Max.Y < 90 || r.Max.Y > 100 {
+		t.Errorf("line rect = %v, want it at x 72 near the baseline 92 from the top", r)
+	}
+
+	blocks, err := src.GetTextBlocks(0, 72)
+	if err != nil || len(blocks) == 0 {
+		t.Fatalf("blocks = %v (%v), want the text blocks", blocks, err)
+	}
+}
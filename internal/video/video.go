@@ -8,6 +8,7 @@ import (
 	"image"
 	"image/draw"
 	"io"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,8 +20,12 @@ import (
 
 type ProgressFunc func(current, total float64)
 
+// FrameFunc fills dst with the output frame number index
+type FrameFunc func(index int, dst *image.RGBA) error
+
 type VideoEncoder interface {
 	EncodeSegment(ctx context.Context, img image.Image, videoPath string, params config.SegmentParams, encoderName string, quality int) error
+	EncodeFrames(ctx context.Context, videoPath string, params config.SegmentParams, encoderName string, quality int, render FrameFunc) error
 	Concatenate(ctx context.Context, segments []config.VideoSegment, finalPath string, tmpDir string, params config.Config, audioDelayMs int, progress ProgressFunc) error
 }
 
@@ -49,7 +54,7 @@ func (e *FFmpegEncoder) EncodeSegment(
 	}
 	filterFile.Close()
 
-	args := e.buildFFmpegArgs(inputW, inputH, videoPath, params, encoderName, quality, filterFile.Name())
+	args := e.buildFFmpegArgs(inputW, inputH, 0, videoPath, params, encoderName, quality, filterFile.Name())
 
 	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
 
@@ -80,8 +85,88 @@ func (e *FFmpegEncoder) EncodeSegment(
 	return nil
 }
 
+// EncodeFrames кодирует сегмент из кадров, которые рендерятся в Go (без zoompan).
+// Кадры передаются в FFmpeg потоком raw RGBA через stdin.
+func (e *FFmpegEncoder) EncodeFrames(
+	ctx context.Context,
+	videoPath string,
+	params config.SegmentParams,
+	encoderName string,
+	quality int,
+	render FrameFunc,
+) error {
+	filterPath := ""
+	if params.Filter != "" {
+		filterFile, err := os.CreateTemp("", "ffmpeg_filter_*.txt")
+		if err != nil {
+			return fmt.Errorf("failed to create temp filter file: %w", err)
+		}
+		defer os.Remove(filterFile.Name())
+
+		if _, err := filterFile.WriteString(params.Filter); err != nil {
+			filterFile.Close()
+			return fmt.Errorf("failed to write filter: %w", err)
+		}
+		filterFile.Close()
+		filterPath = filterFile.Name()
+	}
+
+	args := e.buildFFmpegArgs(params.Width, params.Height, params.FPS, videoPath, params, encoderName, quality, filterPath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("stdin pipe error: %w, stderr: %s", err, stderr.String())
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ffmpeg start error: %w, stderr: %s", err, stderr.String())
+	}
+
+	frame := system.GetImage(image.Rect(0, 0, params.Width, params.Height))
+	defer system.PutImage(frame)
+
+	totalFrames := int(math.Round(params.Duration * float64(params.FPS)))
+	if totalFrames < 1 {
+		totalFrames = 1
+	}
+
+	for i := 0; i < totalFrames; i++ {
+		if err := ctx.Err(); err != nil {
+			stdin.Close()
+			_ = cmd.Wait()
+			return err
+		}
+		if err := render(i, frame); err != nil {
+			stdin.Close()
+			_ = cmd.Wait()
+			return fmt.Errorf("render frame %d error: %w", i, err)
+		}
+		if _, err := stdin.Write(frame.Pix); err != nil {
+			stdin.Close()
+			_ = cmd.Wait()
+			return fmt.Errorf("write frame %d error: %w, stderr: %s", i, err, stderr.String())
+		}
+	}
+	stdin.Close()
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("ffmpeg wait error: %w, stderr: %s", err, stderr.String())
+	}
+
+	return nil
+}
+
+// buildFFmpegArgs собирает аргументы для кодирования сегмента.
+// inputFPS > 0 означает, что на вход подается поток кадров, а не один кадр;
+// пустой filterPath отключает -filter_script.
 func (e *FFmpegEncoder) buildFFmpegArgs(
 	inputW, inputH int,
+	inputFPS int,
 	videoPath string,
 	params config.SegmentParams,
 	encoderName string,
@@ -93,13 +178,20 @@ func (e *FFmpegEncoder) buildFFmpegArgs(
 		"-f", "rawvideo",
 		"-pixel_format", "rgba",
 		"-video_size", fmt.Sprintf("%dx%d", inputW, inputH),
-		"-i", "-",
-		"-filter_script:v", filterPath,
+	}
+	if inputFPS > 0 {
+		args = append(args, "-framerate", fmt.Sprintf("%d", inputFPS))
+	}
+	args = append(args, "-i", "-")
+	if filterPath != "" {
+		args = append(args, "-filter_script:v", filterPath)
+	}
+	args = append(args,
 		"-t", fmt.Sprintf("%f", params.Duration),
 		"-r", fmt.Sprintf("%d", params.FPS),
 		"-pix_fmt", "yuv420p",
 		"-c:v", encoderName,
-	}
+	)
 
 	// Качество в зависимости от энкодера
 	switch encoderName {
@@ -128,6 +220,11 @@ func (e *FFmpegEncoder) writeRawRGBA(w io.Writer, img image.Image) error {
 	return err
 }
 
+// isCut сообщает, что в сегмент входим жесткой склейкой, без перехода
+func isCut(s config.VideoSegment) bool {
+	return s.TransitionType == "" || s.TransitionType == "none" || s.FadeDuration <= 0
+}
+
 func (e *FFmpegEncoder) Concatenate(ctx context.Context, segments []config.VideoSegment, finalPath string, tmpDir string, params config.Config, audioDelayMs int, progress ProgressFunc) error {
 	// Используем сложный фильтр (filter_complex), если:
 	// 1. Нужен переход (xfade) хотя бы в одном сегменте
@@ -135,7 +232,7 @@ func (e *FFmpegEncoder) Concatenate(ctx context.Context, segments []config.Video
 	// 3. Есть основное аудио (особенно с задержкой)
 	hasTransition := false
 	for i := 1; i < len(segments); i++ {
-		if segments[i].TransitionType != "" && segments[i].TransitionType != "none" {
+		if !isCut(segments[i]) {
 			hasTransition = true
 			break
 		}
@@ -193,23 +290,37 @@ func (e *FFmpegEncoder) Concatenate(ctx context.Context, segments []config.Video
 	lastOut := "[0:v]"
 	currentOffset := 0.0
 
-	// 1. Видео фильтры (xfade)
+	// 1. Видео фильтры: xfade для переходов, concat для жестких склеек ("none")
 	if hasTransition {
+		input := func(i int) string { return fmt.Sprintf("[%d:v]", i) }
+		hasCut := false
 		for i := 1; i < len(segments); i++ {
-			fadeDur := segments[i].FadeDuration
-			transType := segments[i].TransitionType
-			if transType == "" || transType == "none" || fadeDur <= 0 {
-				transType = "fade"
-				fadeDur = 0.05 // Минимальный фейд для корректной работы xfade
+			hasCut = hasCut || isCut(segments[i])
+		}
+		if hasCut {
+			// concat выдает кадры во временной базе AVTB, а xfade требует одинаковую базу входов
+			for i := range segments {
+				filterGraph += fmt.Sprintf("[%d:v]settb=AVTB[tb%d];", i, i)
+			}
+			input = func(i int) string { return fmt.Sprintf("[tb%d]", i) }
+			lastOut = input(0)
+		}
+
+		for i := 1; i < len(segments); i++ {
+			outName := fmt.Sprintf("[v%d]", i)
+			if isCut(segments[i]) {
+				// Склейка без перехода: сегменты не перекрываются
+				currentOffset += segments[i-1].Duration
+				filterGraph += fmt.Sprintf("%s%sconcat=n=2:v=1:a=0%s;", lastOut, input(i), outName)
+				lastOut = outName
+				continue
 			}
 
 			// offset: время предыдущего сегмента минус текущий фейд
+			fadeDur := segments[i].FadeDuration
 			currentOffset += segments[i-1].Duration - fadeDur
-
-			nextIn := fmt.Sprintf("[%d:v]", i)
-			outName := fmt.Sprintf("[v%d]", i)
 			filterGraph += fmt.Sprintf("%s%sxfade=transition=%s:duration=%f:offset=%f%s;",
-				lastOut, nextIn, transType, fadeDur, currentOffset, outName)
+				lastOut, input(i), segments[i].TransitionType, fadeDur, currentOffset, outName)
 			lastOut = outName
 		}
 	} else if len(segments) > 1 {